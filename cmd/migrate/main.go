@@ -53,11 +53,94 @@ func main() {
 		log.Fatal("Failed to migrate Channel model:", err)
 	}
 
+	slog.Info("Migrating ChannelMember model...")
+	if err := db.AutoMigrate(&models.ChannelMember{}); err != nil {
+		log.Fatal("Failed to migrate ChannelMember model:", err)
+	}
+
+	slog.Info("Migrating ChannelMute model...")
+	if err := db.AutoMigrate(&models.ChannelMute{}); err != nil {
+		log.Fatal("Failed to migrate ChannelMute model:", err)
+	}
+
+	// Backfill public message IDs for chats created before the uuid column existed.
+	// The model requires the column, so it is added as nullable and filled in
+	// before AutoMigrate sets NOT NULL.
+	if db.Migrator().HasTable(&models.Chat{}) {
+		slog.Info("Backfilling chat UUIDs...")
+		if err := db.Exec("ALTER TABLE chats ADD COLUMN IF NOT EXISTS uuid uuid").Error; err != nil {
+			log.Fatal("Failed to add chat UUID column:", err)
+		}
+		if err := db.Exec("UPDATE chats SET uuid = gen_random_uuid() WHERE uuid IS NULL").Error; err != nil {
+			log.Fatal("Failed to backfill chat UUIDs:", err)
+		}
+	}
+
 	slog.Info("Migrating Chat (message) model...")
 	if err := db.AutoMigrate(&models.Chat{}); err != nil {
 		log.Fatal("Failed to migrate Chat model:", err)
 	}
 
+	slog.Info("Migrating Attachment model...")
+	if err := db.AutoMigrate(&models.Attachment{}); err != nil {
+		log.Fatal("Failed to migrate Attachment model:", err)
+	}
+
+	slog.Info("Migrating Reaction model...")
+	if err := db.AutoMigrate(&models.Reaction{}); err != nil {
+		log.Fatal("Failed to migrate Reaction model:", err)
+	}
+
+	slog.Info("Migrating PinnedMessage model...")
+	if err := db.AutoMigrate(&models.PinnedMessage{}); err != nil {
+		log.Fatal("Failed to migrate PinnedMessage model:", err)
+	}
+
+	slog.Info("Migrating ChannelRead model...")
+	if err := db.AutoMigrate(&models.ChannelRead{}); err != nil {
+		log.Fatal("Failed to migrate ChannelRead model:", err)
+	}
+
+	slog.Info("Migrating ChannelWebhook model...")
+	if err := db.AutoMigrate(&models.ChannelWebhook{}); err != nil {
+		log.Fatal("Failed to migrate ChannelWebhook model:", err)
+	}
+
+	slog.Info("Migrating InboundWebhook model...")
+	if err := db.AutoMigrate(&models.InboundWebhook{}); err != nil {
+		log.Fatal("Failed to migrate InboundWebhook model:", err)
+	}
+
+	slog.Info("Migrating BlockedUser model...")
+	if err := db.AutoMigrate(&models.BlockedUser{}); err != nil {
+		log.Fatal("Failed to migrate BlockedUser model:", err)
+	}
+
+	slog.Info("Migrating RefreshToken model...")
+	if err := db.AutoMigrate(&models.RefreshToken{}); err != nil {
+		log.Fatal("Failed to migrate RefreshToken model:", err)
+	}
+
+	slog.Info("Migrating Invite model...")
+	if err := db.AutoMigrate(&models.Invite{}); err != nil {
+		log.Fatal("Failed to migrate Invite model:", err)
+	}
+
+	slog.Info("Migrating UserPresence model...")
+	if err := db.AutoMigrate(&models.UserPresence{}); err != nil {
+		log.Fatal("Failed to migrate UserPresence model:", err)
+	}
+
+	slog.Info("Migrating Mention model...")
+	if err := db.AutoMigrate(&models.Mention{}); err != nil {
+		log.Fatal("Failed to migrate Mention model:", err)
+	}
+
+	slog.Info("Migrating MentionNotification model...")
+	if err := db.AutoMigrate(&models.MentionNotification{}); err != nil {
+		log.Fatal("Failed to migrate MentionNotification model:", err)
+	}
+
 	// Create indexes for better performance
 	slog.Info("Creating database indexes...")
 	if err := createIndexes(db); err != nil {
@@ -78,6 +161,7 @@ func createIndexes(db *gorm.DB) error {
 		"CREATE INDEX IF NOT EXISTS idx_chats_receiver_id ON chats (receiver_id);",
 		"CREATE INDEX IF NOT EXISTS idx_chats_channel_id ON chats (channel_id);",
 		"CREATE INDEX IF NOT EXISTS idx_chats_created_at ON chats (created_at);",
+		"CREATE INDEX IF NOT EXISTS idx_chats_text_search ON chats USING GIN (to_tsvector('simple', coalesce(text, '')));",
 	}
 
 	for _, indexSQL := range indexes {
@@ -35,7 +35,7 @@ func main() {
 	channelRepo := postgres.NewChannelRepository(db)
 
 	// Initialize services
-	channelService := services.NewChannelService(channelRepo, userRepo)
+	channelService := services.NewChannelService(channelRepo, userRepo, 0) // seed data is not subject to the owned channel limit
 
 	// Seed initial users
 	slog.Info("Creating initial users...")
@@ -46,6 +46,7 @@ func main() {
 		Username: "admin",
 		Email:    "admin@notify.com",
 		Password: string(adminPassword),
+		IsAdmin:  true,
 	}
 
 	if err := userRepo.Create(adminUser); err != nil {
@@ -150,7 +151,7 @@ func seedSampleMessages(db *gorm.DB, userRepo *postgres.UserRepository, channelR
 			SenderID:  admin.ID,
 			ChannelID: generalChannel.ID,
 			Text:      stringPtr("Welcome to the general channel! 👋"),
-			// Type is now implicit by ChannelID being set
+			// The type follows from ChannelID; Chat.BeforeCreate rejects a chat with both or neither target
 		},
 		{
 			SenderID:  alice.ID,
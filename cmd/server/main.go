@@ -65,9 +65,71 @@ func main() {
 	}
 
 	chatRepo := postgres.NewChatRepository(db)
+	channelRepo := postgres.NewChannelRepository(db)
+	reactionRepo := postgres.NewReactionRepository(db)
+	userRepo := postgres.NewUserRepository(db)
+	chatService := services.NewChatService(chatRepo, channelRepo, reactionRepo, userRepo, cfg.Search.SnippetMaxWords, cfg.Message.MaxTextBytes)
+	readService := services.NewReadStateService(postgres.NewReadStateRepository(db), chatRepo, channelRepo)
+
+	// Initialize offline delivery webhook (optional)
+	var offlineNotifier *services.OfflineNotifier
+	var webhookDispatcher *services.WebhookDispatcher
+	if cfg.OfflineWebhook.URL != "" {
+		webhookDispatcher = services.NewWebhookDispatcher(
+			cfg.OfflineWebhook.URL,
+			cfg.OfflineWebhook.Secret,
+			cfg.OfflineWebhook.Timeout,
+			cfg.OfflineWebhook.MaxRetries,
+		)
+		webhookDispatcher.Start()
+		offlineNotifier = services.NewOfflineNotifier(channelRepo, redisService, webhookDispatcher, cfg.OfflineWebhook.IncludeText)
+		slog.Info("Offline delivery webhook enabled", "url", cfg.OfflineWebhook.URL)
+	}
+
+	// Initialize channel integration webhooks
+	channelWebhookDispatcher := services.NewPublicWebhookDispatcher(cfg.ChannelWebhook.Timeout, cfg.ChannelWebhook.MaxRetries)
+	channelWebhookDispatcher.Start()
+	channelWebhookNotifier := services.NewChannelWebhookNotifier(postgres.NewChannelWebhookRepository(db), channelWebhookDispatcher)
+
+	// Initialize presence webhook (optional)
+	var presenceNotifier *services.PresenceNotifier
+	var presenceWebhookDispatcher *services.WebhookDispatcher
+	if cfg.PresenceWebhook.URL != "" {
+		presenceWebhookDispatcher = services.NewWebhookDispatcher(
+			cfg.PresenceWebhook.URL,
+			cfg.PresenceWebhook.Secret,
+			cfg.PresenceWebhook.Timeout,
+			cfg.PresenceWebhook.MaxRetries,
+		)
+		presenceWebhookDispatcher.Start()
+		presenceNotifier = services.NewPresenceNotifier(presenceWebhookDispatcher, cfg.PresenceWebhook.Debounce)
+		slog.Info("Presence webhook enabled", "url", cfg.PresenceWebhook.URL)
+	}
+
+	// Initialize error monitoring webhook (optional)
+	var errorNotifier *services.ErrorNotifier
+	var errorWebhookDispatcher *services.WebhookDispatcher
+	if cfg.ErrorWebhook.URL != "" {
+		errorWebhookDispatcher = services.NewWebhookDispatcher(
+			cfg.ErrorWebhook.URL,
+			cfg.ErrorWebhook.Secret,
+			cfg.ErrorWebhook.Timeout,
+			cfg.ErrorWebhook.MaxRetries,
+		)
+		errorWebhookDispatcher.Start()
+		errorNotifier = services.NewErrorNotifier(errorWebhookDispatcher, cfg.ErrorWebhook.BatchSize, cfg.ErrorWebhook.FlushInterval)
+		errorNotifier.Start()
+		slog.Info("Error monitoring webhook enabled", "url", cfg.ErrorWebhook.URL)
+	}
+
+	// Daily message quota, a no-op while NOTIFY_MESSAGE_DAILY_QUOTA is 0
+	messageQuota := services.NewMessageQuota(redisService, userRepo, cfg.Message.DailyQuota)
+
+	mentionService := services.NewMentionService(userRepo, postgres.NewMentionRepository(db), redisService)
+	mentionService.Start()
 
 	// Initialize WebSocket hub
-	hub := websocket.NewHub(redisService, chatRepo)
+	hub := websocket.NewHub(redisService, chatRepo, channelRepo, postgres.NewPresenceRepository(db), chatService, readService, offlineNotifier, channelWebhookNotifier, presenceNotifier, errorNotifier, messageQuota, mentionService, nil, cfg.WebSocket)
 	go hub.Run()
 
 	// Initialize router with all dependencies
@@ -76,7 +138,7 @@ func main() {
 		redisService,
 		redisClient.GetClient(),
 		db,
-		cfg.JWT.Secret,
+		cfg,
 	)
 	router.SetupRoutes()
 
@@ -112,6 +174,22 @@ func main() {
 	// Stop WebSocket hub
 	hub.Stop()
 
+	// Record mentions of the last persisted messages
+	mentionService.Stop()
+
+	// Flush pending webhook deliveries
+	if webhookDispatcher != nil {
+		webhookDispatcher.Stop()
+	}
+	channelWebhookDispatcher.Stop()
+	if presenceWebhookDispatcher != nil {
+		presenceWebhookDispatcher.Stop()
+	}
+	if errorNotifier != nil {
+		errorNotifier.Stop()
+		errorWebhookDispatcher.Stop()
+	}
+
 	// Shutdown HTTP server
 	if err := server.Shutdown(ctx); err != nil {
 		slog.Error("Server forced to shutdown", "error", err)
@@ -15,9 +15,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
+        "/admin/channels/bulk": {
             "post": {
-                "description": "Authenticate user with email and password",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin only. Creates many channels in a single transaction. Items that fail validation or creation are rolled back individually and reported; the rest are kept.",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,25 +30,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "User login",
+                "summary": "Provision channels in bulk",
                 "parameters": [
                     {
-                        "description": "User login credentials",
+                        "description": "Channel definitions",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/chat-service_internal_models.LoginRequest"
+                            "$ref": "#/definitions/chat-service_internal_models.BulkCreateChannelsRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Login successful - returns JWT token and user data",
+                        "description": "Per-item results",
                         "schema": {
-                            "$ref": "#/definitions/chat-service_internal_models.LoginResponse"
+                            "$ref": "#/definitions/chat-service_internal_models.BulkCreateChannelsResponse"
                         }
                     },
                     "400": {
@@ -53,53 +58,13 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Unauthorized - invalid credentials",
-                        "schema": {
-                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
+                        "description": "Unauthorized - invalid or missing token",
                         "schema": {
                             "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/auth/register": {
-            "post": {
-                "description": "Register a new user with username, email, and password",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "auth"
-                ],
-                "summary": "Register a new user",
-                "parameters": [
-                    {
-                        "description": "User registration data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/chat-service_internal_models.RegisterRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "User created successfully",
-                        "schema": {
-                            "$ref": "#/definitions/chat-service_internal_models.UserResponse"
-                        }
                     },
-                    "400": {
-                        "description": "Bad request - invalid input data",
+                    "403": {
+                        "description": "Forbidden - admin privileges required",
                         "schema": {
                             "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
                         }
@@ -113,29 +78,29 @@ const docTemplate = `{
                 }
             }
         },
-        "/channels/": {
+        "/admin/channels/sizes": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all channels that the current user is a member of, separated by type",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Admin-only diagnostics: how many clients on this instance joined each channel, largest first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "channels"
+                    "admin"
                 ],
-                "summary": "Get user's channels",
+                "summary": "List channel fan-out sizes",
                 "responses": {
                     "200": {
-                        "description": "Object with direct and group channel lists",
+                        "description": "Channel sizes",
                         "schema": {
-                            "$ref": "#/definitions/chat-service_internal_models.UserChannelsResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/chat-service_internal_websocket.ChannelSize"
+                            }
                         }
                     },
                     "401": {
@@ -144,21 +109,23 @@ const docTemplate = `{
                             "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "403": {
+                        "description": "Forbidden - admin privileges required",
                         "schema": {
                             "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/users/{id}/disconnect": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new channel with the specified name and selected users",
+                "description": "Closes the user's connections on every instance with a connection.force_logout frame carrying the reason (e.g. after a password change or ban; moderators use the same frame, there is no separate disconnected frame) and revokes all of the user's refresh tokens, so no session can be renewed. Allowed for admins and for the user themselves.",
                 "consumes": [
                     "application/json"
                 ],
@@ -166,29 +133,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "channels"
+                    "websocket"
                 ],
-                "summary": "Create a new channel",
+                "summary": "Forcibly disconnect a user's WebSocket connections",
                 "parameters": [
                     {
-                        "description": "Channel creation data with user selection",
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Disconnect reason",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/chat-service_internal_models.CreateChannelRequest"
+                            "$ref": "#/definitions/chat-service_internal_models.ForceDisconnectRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Channel created successfully",
+                        "description": "Disconnect issued",
                         "schema": {
-                            "$ref": "#/definitions/chat-service_internal_models.ChannelResponse"
+                            "$ref": "#/definitions/chat-service_internal_models.ForceDisconnectResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad request - invalid input data",
+                        "description": "Bad request - invalid user ID",
                         "schema": {
                             "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
                         }
@@ -199,6 +172,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden - not allowed to disconnect this user",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -208,38 +187,37 @@ const docTemplate = `{
                 }
             }
         },
-        "/channels/{id}": {
+        "/admin/ws/connections": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get detailed information about a specific channel",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Admin-only diagnostics: a snapshot of every client connected to this instance, ordered by user ID, for tracking down ghost connections",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "channels"
+                    "admin"
                 ],
-                "summary": "Get channel by ID",
+                "summary": "List WebSocket connections",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Channel ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "description": "Only clients that joined this channel",
+                        "name": "channel",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Channel details retrieved successfully",
+                        "description": "Connections",
                         "schema": {
-                            "$ref": "#/definitions/chat-service_internal_models.ChannelDetailResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/chat-service_internal_websocket.ConnectionMetadata"
+                            }
                         }
                     },
                     "401": {
@@ -248,64 +226,59 @@ const docTemplate = `{
                             "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Channel not found",
+                    "403": {
+                        "description": "Forbidden - admin privileges required",
                         "schema": {
                             "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/admin/ws/errors": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update the name of an existing channel",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Admin-only diagnostics: the latest hub errors on this instance, newest first, with all-time totals and per-minute rates by type",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "channels"
+                    "admin"
                 ],
-                "summary": "Update channel",
+                "summary": "List recent hub errors",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Channel ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "description": "Only errors of this type, e.g. persist or redis",
+                        "name": "type",
+                        "in": "query"
                     },
                     {
-                        "description": "Channel update data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
+                        "type": "string",
+                        "description": "Only errors of this severity (warning or error)",
+                        "name": "severity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum errors to return (default 100, max 500)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Channel updated successfully",
+                        "description": "Error history",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/chat-service_internal_websocket.ErrorHistory"
                         }
                     },
                     "400": {
-                        "description": "Bad request - invalid input data",
+                        "description": "Bad request - invalid severity or limit",
                         "schema": {
                             "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
                         }
@@ -316,21 +289,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "403": {
+                        "description": "Forbidden - admin privileges required",
                         "schema": {
                             "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Delete a channel (only channel owner can delete). This will remove all channel members and perform soft delete on the channel.",
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Authenticate user with email and password",
                 "consumes": [
                     "application/json"
                 ],
@@ -338,42 +308,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "channels"
+                    "auth"
                 ],
-                "summary": "Delete channel",
+                "summary": "User login",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Channel ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "User login credentials",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.LoginRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Channel deleted successfully",
+                        "description": "Login successful - returns JWT token and user data",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/chat-service_internal_models.LoginResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad request - channel not found or user is not owner",
+                        "description": "Bad request - invalid input data",
                         "schema": {
                             "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized - invalid or missing token",
-                        "schema": {
-                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden - only channel owner can delete channel",
+                        "description": "Unauthorized - invalid credentials",
                         "schema": {
                             "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
                         }
@@ -387,14 +350,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/channels/{id}/user": {
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Remove the current user from a channel",
+        "/auth/logout": {
+            "post": {
+                "description": "Revoke a refresh token. Access tokens already issued stay valid until they expire.",
                 "consumes": [
                     "application/json"
                 ],
@@ -402,21 +360,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "channels"
+                    "auth"
                 ],
-                "summary": "Leave channel",
+                "summary": "Log out",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Channel ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Refresh token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.RefreshTokenRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User left channel successfully",
+                        "description": "Logged out",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -424,8 +384,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized - invalid or missing token",
+                    "400": {
+                        "description": "Bad request - invalid input data",
                         "schema": {
                             "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
                         }
@@ -437,14 +397,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/refresh": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Add a user to a channel (only channel owner can add users)",
+                "description": "Exchange a refresh token for a new access token. The refresh token is single use and a replacement is returned; presenting a used token revokes all of the user's refresh tokens.",
                 "consumes": [
                     "application/json"
                 ],
@@ -452,38 +409,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "channels"
+                    "auth"
                 ],
-                "summary": "Add user to channel",
+                "summary": "Refresh an access token",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Channel ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "User addition data",
+                        "description": "Refresh token",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "integer"
-                            }
+                            "$ref": "#/definitions/chat-service_internal_models.RefreshTokenRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User added to channel successfully",
+                        "description": "New access and refresh tokens",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/chat-service_internal_models.TokenResponse"
                         }
                     },
                     "400": {
@@ -493,7 +437,7 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Unauthorized - invalid or missing token",
+                        "description": "Unauthorized - invalid, expired or reused refresh token",
                         "schema": {
                             "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
                         }
@@ -505,14 +449,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Remove a user from a channel (only channel owner can remove users)",
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Register a new user with username, email, and password",
                 "consumes": [
                     "application/json"
                 ],
@@ -520,38 +461,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "channels"
+                    "auth"
                 ],
-                "summary": "Remove user from channel",
+                "summary": "Register a new user",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Channel ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "User removal data",
+                        "description": "User registration data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "integer"
-                            }
+                            "$ref": "#/definitions/chat-service_internal_models.RegisterRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "User removed from channel successfully",
+                    "201": {
+                        "description": "User created successfully",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/chat-service_internal_models.UserResponse"
                         }
                     },
                     "400": {
@@ -560,12 +488,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized - invalid or missing token",
-                        "schema": {
-                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -575,14 +497,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/messages/channel/{id}": {
+        "/channels/": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all chat messages for a specific channel (paginated)",
+                "description": "Get all channels that the current user is a member of, separated by type. Archived channels are only listed with includeArchived=true.",
                 "consumes": [
                     "application/json"
                 ],
@@ -590,35 +512,77 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "chats"
+                    "channels"
                 ],
-                "summary": "Get chat messages in a channel",
+                "summary": "Get user's channels",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Channel ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page size",
-                        "name": "limit",
+                        "type": "boolean",
+                        "description": "Also list archived channels",
+                        "name": "includeArchived",
                         "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Object with direct and group channel lists",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.UserChannelsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
                     },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
                     {
-                        "type": "integer",
-                        "description": "Cursor for infinite scroll (timestamp)",
-                        "name": "before",
-                        "in": "query"
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new channel with the specified name and selected users",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "channels"
+                ],
+                "summary": "Create a new channel",
+                "parameters": [
+                    {
+                        "description": "Channel creation data with user selection",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.CreateChannelRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Paginated chat messages",
+                        "description": "Channel created successfully",
                         "schema": {
-                            "$ref": "#/definitions/chat-service_internal_models.PaginatedChatResponse"
+                            "$ref": "#/definitions/chat-service_internal_models.ChannelResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input data or unknown user",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
                         }
                     },
                     "401": {
@@ -627,8 +591,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Channel not found",
+                    "403": {
+                        "description": "Forbidden - owned channel limit reached",
                         "schema": {
                             "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
                         }
@@ -642,14 +606,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/profile": {
+        "/channels/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get the current user's profile information",
+                "description": "Get detailed information about a specific channel",
                 "consumes": [
                     "application/json"
                 ],
@@ -657,14 +621,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "channels"
+                ],
+                "summary": "Get channel by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get user profile",
                 "responses": {
                     "200": {
-                        "description": "User profile retrieved successfully",
+                        "description": "Channel details retrieved successfully",
                         "schema": {
-                            "$ref": "#/definitions/chat-service_internal_models.UserResponse"
+                            "$ref": "#/definitions/chat-service_internal_models.ChannelDetailResponse"
                         }
                     },
                     "401": {
@@ -673,6 +646,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Channel not found",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -687,7 +666,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update the current user's profile information (username, avatar, password)",
+                "description": "Update the name of an existing channel",
                 "consumes": [
                     "application/json"
                 ],
@@ -695,25 +674,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "channels"
                 ],
-                "summary": "Update user profile",
+                "summary": "Update channel",
                 "parameters": [
                     {
-                        "description": "Profile update request",
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Channel update data",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/chat-service_internal_models.UpdateProfileRequest"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Profile updated successfully",
+                        "description": "Channel updated successfully",
                         "schema": {
-                            "$ref": "#/definitions/chat-service_internal_models.UserResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
@@ -728,8 +720,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden - current password is incorrect",
+                    "404": {
+                        "description": "Channel not found",
                         "schema": {
                             "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
                         }
@@ -741,16 +733,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/users/search": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Search for users by username (partial match for channel creation)",
+                "description": "Delete a channel (only channel owner can delete). This will remove all channel members and perform soft delete on the channel.",
                 "consumes": [
                     "application/json"
                 ],
@@ -758,36 +748,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "channels"
                 ],
-                "summary": "Search users by username",
+                "summary": "Delete channel",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Username to search for",
-                        "name": "username",
-                        "in": "query",
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of users found",
+                        "description": "Channel deleted successfully",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/chat-service_internal_models.UserResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad request - invalid username",
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
                         "schema": {
                             "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized - invalid or missing token",
+                    "403": {
+                        "description": "Forbidden - only channel owner can delete channel",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Channel not found",
                         "schema": {
                             "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
                         }
@@ -800,91 +796,4084 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "chat-service_internal_models.Channel": {
-            "type": "object",
-            "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "deleted_at": {
-                    "$ref": "#/definitions/gorm.DeletedAt"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "members": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/chat-service_internal_models.User"
+        },
+        "/channels/{id}/archive": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
                     }
-                },
-                "name": {
+                ],
+                "description": "Make a channel read-only (only channel owner). Members and history are kept; new messages are rejected and the channel is hidden from the default channel list.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "channels"
+                ],
+                "summary": "Archive a channel",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Channel archived",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ChannelResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - only channel owner can archive",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Channel not found",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/channels/{id}/forward": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Copy a message from this channel into another channel the user is a member of, keeping a reference to the original message",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "chats"
+                ],
+                "summary": "Forward a message to another channel",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Source channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Message and target channel",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ForwardMessageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Forwarded message",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ChatResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not a member of both channels or blocked by the recipient",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Message not found",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Target channel is archived",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/channels/{id}/inbound-webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the channel's inbound webhooks (only channel owner or admin)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "channels"
+                ],
+                "summary": "List inbound webhooks",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Inbound webhooks",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/chat-service_internal_models.InboundWebhook"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - only channel owner or admin can manage webhooks",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Channel not found",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a bot identity that external systems can post into the channel as, through POST /webhooks/{token} (only channel owner or admin). The token is only returned here.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "channels"
+                ],
+                "summary": "Add an inbound webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Bot name",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.CreateInboundWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Inbound webhook created",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.InboundWebhookCreatedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - only channel owner or admin can manage webhooks",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Channel not found",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/channels/{id}/inbound-webhooks/{webhookId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoke an inbound webhook token. Messages already posted stay attributed to the bot (only channel owner or admin).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "channels"
+                ],
+                "summary": "Delete an inbound webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Inbound webhook ID",
+                        "name": "webhookId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Inbound webhook deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - only channel owner or admin can manage webhooks",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Channel or webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/channels/{id}/invites": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mint an invite token that lets any signed-in user join the group channel (channel owner or admins only). The invite can expire after expiresIn seconds and stop after maxUses joins; omit either for no limit.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "channels"
+                ],
+                "summary": "Create a channel invite link",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Invite limits",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.CreateInviteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Invite created",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.Invite"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input data or not a group channel",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - channel owner or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Channel not found",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Channel is archived",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/channels/{id}/members/{userId}/role": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Promote a member to admin or demote an admin to member. Only the channel owner may change roles.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "channels"
+                ],
+                "summary": "Change a member's role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Member user ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New role",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.UpdateMemberRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated membership",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ChannelMember"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid role or target is the owner",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - only channel owner can change roles",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Channel not found or user is not a member",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/channels/{id}/messages": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cursor-paginated message history, newest first. Pass the returned nextCursor as \"before\" to load the next older page; nextCursor is null on the last page.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "chats"
+                ],
+                "summary": "Get channel message history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Return messages older than this message ID",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Page of messages",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ChatHistoryPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid channel ID or cursor",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not a member of the channel",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/channels/{id}/messages/{messageId}/pin": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Pin a message in its channel. Only channel admins and owners may pin, and a channel holds at most 50 pins. A pin event is broadcast to the channel. Pinning an already pinned message is a no-op.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "chats"
+                ],
+                "summary": "Pin a message",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Message ID",
+                        "name": "messageId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pin result",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.PinEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid channel or message ID",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not a channel owner or admin",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Channel or message not found",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Channel has reached its pin limit",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a message's pin. Only channel admins and owners may unpin. An unpin event is broadcast to the channel. Unpinning a message that is not pinned is a no-op.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "chats"
+                ],
+                "summary": "Unpin a message",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Message ID",
+                        "name": "messageId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Unpin result",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.PinEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid channel or message ID",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not a channel owner or admin",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Channel not found",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/channels/{id}/mute": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mute notifications from a channel until a time, or until unmuted when no time is given. Messages are still delivered over WebSocket with a \"muted\" hint.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "channels"
+                ],
+                "summary": "Mute a channel",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Mute end time",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.MuteChannelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Channel muted",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ChannelMute"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not a channel member",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove the caller's mute for a channel",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "channels"
+                ],
+                "summary": "Unmute a channel",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Channel unmuted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not a channel member",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/channels/{id}/pins": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the channel's pinned messages, most recently pinned first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "chats"
+                ],
+                "summary": "List pinned messages",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pinned messages",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/chat-service_internal_models.PinnedMessageResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid channel ID",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not a member of the channel",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/channels/{id}/presence": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "IDs of the channel's members who are online on any instance",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "websocket"
+                ],
+                "summary": "Get a channel's online members",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Online members",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_websocket.ChannelPresence"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not a channel member",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Channel not found",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/channels/{id}/read": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Set the read pointer to the latest message sent at or before readAt. Useful when a client only knows when it viewed the conversation. The pointer never moves backwards.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "channels"
+                ],
+                "summary": "Mark a direct message thread as read up to a time",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Read time",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.MarkReadByTimeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Current read pointer",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ReadStateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input data or not a direct channel",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not a channel member",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Channel not found",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/channels/{id}/reads": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get every member's last-read message ID so clients can render read markers. Members who have never read anything are omitted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "channels"
+                ],
+                "summary": "List read pointers for a channel",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Read pointers",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/chat-service_internal_models.ChannelRead"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not a channel member",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/channels/{id}/slow-mode": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Set the minimum number of seconds between messages per user (only channel owner or admin). 0 disables slow mode.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "channels"
+                ],
+                "summary": "Update channel slow mode",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Slow mode interval",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.UpdateSlowModeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated channel",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ChannelResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input data or interval out of range",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - only channel owner can change slow mode",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Channel not found",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/channels/{id}/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get engagement analytics for a channel: message count, most active members, most used reactions and busiest hour (UTC). Only channel owner or admin. Results are cached briefly.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "channels"
+                ],
+                "summary": "Get channel stats",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Channel stats",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ChannelStatsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - only channel owner or admin can view stats",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Channel not found",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/channels/{id}/summary": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the channel's latest message, its total message count and how many messages from other members arrived after the caller's read pointer. Works for group and direct channels.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "channels"
+                ],
+                "summary": "Get a channel summary",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Channel summary",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ChannelSummaryResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not a channel member",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/channels/{id}/unarchive": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reopen an archived channel for new messages (only channel owner)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "channels"
+                ],
+                "summary": "Unarchive a channel",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Channel unarchived",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ChannelResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - only channel owner can unarchive",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Channel not found",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/channels/{id}/user": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove the current user from a channel",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "channels"
+                ],
+                "summary": "Leave channel",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User left channel successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Channel not found",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a user to a channel as a member (channel owner or admins only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "channels"
+                ],
+                "summary": "Add user to channel",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User addition data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User added to channel successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - channel owner or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Channel or user not found",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a user from a channel. The owner may remove anyone but themselves; admins may remove members only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "channels"
+                ],
+                "summary": "Remove user from channel",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User removal data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User removed from channel successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input data or target is the owner",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - role does not outrank the target",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Channel or user not found",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/channels/{id}/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the channel's webhooks with their last delivery status (only channel owner or admin)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "channels"
+                ],
+                "summary": "List channel webhooks",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Channel webhooks",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/chat-service_internal_models.ChannelWebhook"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - only channel owner or admin can manage webhooks",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Channel not found",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Register an https URL that receives every new message in the channel (only channel owner or admin). URLs resolving to loopback, private, link-local or other non-public addresses are rejected. Deliveries are POSTed asynchronously with retries and signed with X-Notify-Signature (HMAC-SHA256 of the body). The signing secret is only returned here.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "channels"
+                ],
+                "summary": "Add a channel webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Webhook URL",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.CreateChannelWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Webhook created",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ChannelWebhookCreatedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input data or webhook URL not https or not public",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - only channel owner or admin can manage webhooks",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Channel not found",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/channels/{id}/webhooks/{webhookId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Toggle delivery to a channel webhook (only channel owner or admin)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "channels"
+                ],
+                "summary": "Enable or disable a channel webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "webhookId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Enabled flag",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.UpdateChannelWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated webhook",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ChannelWebhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - only channel owner or admin can manage webhooks",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Channel or webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a channel webhook (only channel owner or admin)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "channels"
+                ],
+                "summary": "Delete a channel webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "webhookId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhook deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - only channel owner or admin can manage webhooks",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Channel or webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Reports whether the hub is accepting connections and the state of its Redis circuit breaker (closed, open or half-open)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "websocket"
+                ],
+                "summary": "Hub health",
+                "responses": {
+                    "200": {
+                        "description": "Health status",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Reports the hub's health (unhealthy while shedding load), active connections on this instance and hub errors in the load shedding window",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "WebSocket subsystem health",
+                "responses": {
+                    "200": {
+                        "description": "Health status",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/invites/{token}/accept": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Join the channel an invite token belongs to. Members already in the channel get joined=false and do not use up the invite. Other channel members are sent a channel.join event.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "channels"
+                ],
+                "summary": "Join a channel through an invite",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Invite token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Joined the channel",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.AcceptInviteResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Invite not found",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Channel is archived",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Invite has expired or reached its maximum uses",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/messages/catchup": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "For each channel, return the messages newer than the given last seen message ID, oldest first, so a reconnecting client can catch up in one request. Channels the user does not belong to are left out of the response. hasMore is set for a channel when the per-channel cap left messages out; page through those with the channel history.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "chats"
+                ],
+                "summary": "Catch up on several channels",
+                "parameters": [
+                    {
+                        "description": "Last seen message ID per channel ID",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.CatchupRequest"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Messages per channel (default 50, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "New messages per channel",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.CatchupResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input data or too many channels",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/messages/channel/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all chat messages for a specific channel (paginated)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "chats"
+                ],
+                "summary": "Get chat messages in a channel",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cursor for infinite scroll (timestamp)",
+                        "name": "before",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Paginated chat messages",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.PaginatedChatResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Channel not found",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/messages/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Full-text search over messages in the current user's channels. Each result carries a snippet with matched terms wrapped in \u003cmark\u003e\u003c/mark\u003e.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "chats"
+                ],
+                "summary": "Search messages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Restrict the search to one channel",
+                        "name": "channelId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum results (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Matching messages",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/chat-service_internal_models.MessageSearchResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - missing query or invalid channel ID",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not a member of the channel",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/messages/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the text of a message. Only the original sender may edit. The edit is broadcast to the channel over WebSocket so open clients update in place.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "chats"
+                ],
+                "summary": "Edit a message",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Message ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New text",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.EditMessageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Edited message",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ChatResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not the sender",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Message not found or deleted",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Channel is archived",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Message text exceeds the size limit",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Soft-delete a message. The sender or a channel owner or admin may delete it. A tombstone event is broadcast to the channel and history shows a placeholder instead of the content. Deleting an already deleted message is a no-op.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "chats"
+                ],
+                "summary": "Delete a message",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Message ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Message deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid message ID",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not the sender or a channel owner or admin",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Message not found",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/messages/{id}/reactions": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add the current user's emoji reaction to a message. Adding the same reaction twice is a no-op. Changes are broadcast to the channel over WebSocket.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "chats"
+                ],
+                "summary": "Add a reaction to a message",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Message ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reaction emoji",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ReactionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reaction state after the change",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ReactionEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not a member of the channel",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Message not found",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/messages/{id}/reactions/{emoji}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove the current user's emoji reaction from a message. Removing a missing reaction is a no-op. Changes are broadcast to the channel over WebSocket.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "chats"
+                ],
+                "summary": "Remove a reaction from a message",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Message ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reaction emoji (URL encoded)",
+                        "name": "emoji",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reaction state after the change",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ReactionEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not a member of the channel",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Message not found",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/messages/{id}/thread": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a message and its replies, oldest first. Passing a reply returns the thread it belongs to.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "chats"
+                ],
+                "summary": "Get a message thread",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Message ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Parent message and replies",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ThreadResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid message ID",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not a member of the channel",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Message not found",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics": {
+            "get": {
+                "description": "WebSocket hub metrics in the Prometheus text exposition format: active and per-channel connections, broadcast counts and latency, and errors by type",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "websocket"
+                ],
+                "summary": "Prometheus metrics",
+                "responses": {
+                    "200": {
+                        "description": "Metrics",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Checks Postgres, Redis and the hub run loop. Returns 503 when any of them is down so traffic is routed elsewhere.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "Ready",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "503": {
+                        "description": "A dependency is down",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Page through the user directory. q matches usernames and emails case-insensitively, prefix matches first. Users you have blocked are not listed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List and search users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Text to match against username or email",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of users to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Page of users",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.UserDirectoryPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid limit or offset",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/quota": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get how many messages the current user has sent today against the daily quota. The quota resets at midnight UTC; a limit of 0 means no quota is configured.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get daily message quota usage",
+                "responses": {
+                    "200": {
+                        "description": "Quota usage retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.MessageQuotaUsage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/profile": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the current user's profile information",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user profile",
+                "responses": {
+                    "200": {
+                        "description": "User profile retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.UserResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update the current user's profile information (username, avatar, password)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update user profile",
+                "parameters": [
+                    {
+                        "description": "Profile update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.UpdateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Profile updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - current password is incorrect",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Search for users by username (partial match for channel creation)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Search users by username",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username to search for",
+                        "name": "username",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of users found",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/chat-service_internal_models.UserResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid username",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/disconnect": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Closes the user's connections on every instance with a connection.force_logout frame carrying the reason (e.g. after a password change or ban; moderators use the same frame, there is no separate disconnected frame) and revokes all of the user's refresh tokens, so no session can be renewed. Allowed for admins and for the user themselves.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "websocket"
+                ],
+                "summary": "Forcibly disconnect a user's WebSocket connections",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Disconnect reason",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ForceDisconnectRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Disconnect issued",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ForceDisconnectResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not allowed to disconnect this user",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/presence": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Whether the user is online on any instance, with last activity and joined channels when known. Offline users include lastSeen, when they were last connected. Unknown users are reported offline.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "websocket"
+                ],
+                "summary": "Get a user's presence",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User presence",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_websocket.UserPresence"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{token}": {
+            "post": {
+                "description": "Post a message into the webhook's channel as its bot identity. No user account is needed; the token in the path authenticates the request. Limited to 30 messages per minute per webhook.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Post a message through an inbound webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Inbound webhook token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Message",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.InboundWebhookMessageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Message posted",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ChatResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid webhook token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Channel is archived",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Message text exceeds the size limit",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ws/connections/{userId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin-only diagnostics: local connection metadata for the user and whether any instance reports them online",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "websocket"
+                ],
+                "summary": "Get a user's WebSocket connection state",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Connection state",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_websocket.ConnectionState"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - admin privileges required",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ws/protocol": {
+            "get": {
+                "description": "Machine-readable list of the actions clients may send and the events the server emits, generated from the hub's action registry",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "websocket"
+                ],
+                "summary": "Describe the WebSocket protocol",
+                "responses": {
+                    "200": {
+                        "description": "Protocol description",
+                        "schema": {
+                            "$ref": "#/definitions/chat-service_internal_websocket.ProtocolDescription"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "chat-service_internal_models.AcceptInviteResponse": {
+            "type": "object",
+            "properties": {
+                "channelId": {
+                    "type": "integer"
+                },
+                "joined": {
+                    "description": "false when the user was already a member",
+                    "type": "boolean"
+                }
+            }
+        },
+        "chat-service_internal_models.Attachment": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "height": {
+                    "description": "images and video only",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "messageId": {
+                    "type": "integer"
+                },
+                "mimeType": {
+                    "type": "string"
+                },
+                "size": {
+                    "description": "bytes",
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "width": {
+                    "description": "images and video only",
+                    "type": "integer"
+                }
+            }
+        },
+        "chat-service_internal_models.BulkChannelItem": {
+            "type": "object",
+            "required": [
+                "ownerId",
+                "type",
+                "userIds"
+            ],
+            "properties": {
+                "name": {
+                    "description": "required for group channels",
+                    "type": "string"
+                },
+                "ownerId": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "direct",
+                        "group"
+                    ]
+                },
+                "userIds": {
+                    "description": "must include the owner",
+                    "type": "array",
+                    "maxItems": 4,
+                    "minItems": 2,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "chat-service_internal_models.BulkChannelResult": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "$ref": "#/definitions/chat-service_internal_models.ChannelResponse"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "chat-service_internal_models.BulkCreateChannelsRequest": {
+            "type": "object",
+            "required": [
+                "channels"
+            ],
+            "properties": {
+                "channels": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/chat-service_internal_models.BulkChannelItem"
+                    }
+                }
+            }
+        },
+        "chat-service_internal_models.BulkCreateChannelsResponse": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/chat-service_internal_models.BulkChannelResult"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer"
+                }
+            }
+        },
+        "chat-service_internal_models.CatchupChannel": {
+            "type": "object",
+            "properties": {
+                "hasMore": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/chat-service_internal_models.ChatResponse"
+                    }
+                }
+            }
+        },
+        "chat-service_internal_models.CatchupRequest": {
+            "type": "object",
+            "required": [
+                "channels"
+            ],
+            "properties": {
+                "channels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "chat-service_internal_models.CatchupResponse": {
+            "type": "object",
+            "properties": {
+                "channels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/chat-service_internal_models.CatchupChannel"
+                    }
+                }
+            }
+        },
+        "chat-service_internal_models.Channel": {
+            "type": "object",
+            "properties": {
+                "archived": {
+                    "description": "Read-only: members and history are kept but no new messages are accepted",
+                    "type": "boolean"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/chat-service_internal_models.User"
+                    }
+                },
+                "name": {
                     "description": "Name of the channel",
                     "type": "string"
                 },
-                "ownerId": {
-                    "description": "ID of the channel owner",
+                "ownerId": {
+                    "description": "ID of the channel owner",
+                    "type": "integer"
+                },
+                "slowModeSeconds": {
+                    "description": "Minimum seconds between messages per user, 0 disables",
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "Type of channel, either 'direct' or 'group'",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "chat-service_internal_models.ChannelDetailResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "members": {
+                    "description": "List of members in the channel",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/chat-service_internal_models.User"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "ownerId": {
+                    "type": "integer"
+                },
+                "slowModeSeconds": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "chat-service_internal_models.ChannelMember": {
+            "type": "object",
+            "properties": {
+                "channelId": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "chat-service_internal_models.ChannelMute": {
+            "type": "object",
+            "properties": {
+                "channelId": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "mutedUntil": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "chat-service_internal_models.ChannelRead": {
+            "type": "object",
+            "properties": {
+                "channelId": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lastReadAt": {
+                    "description": "created_at of the last read message",
+                    "type": "string"
+                },
+                "lastReadMessageId": {
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "chat-service_internal_models.ChannelResponse": {
+            "type": "object",
+            "properties": {
+                "archived": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "ownerId": {
+                    "type": "integer"
+                },
+                "slowModeSeconds": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "chat-service_internal_models.ChannelStatsResponse": {
+            "type": "object",
+            "properties": {
+                "busiestHour": {
+                    "description": "hour of day in UTC, 0-23",
+                    "type": "integer"
+                },
+                "busiestHourMessages": {
+                    "type": "integer"
+                },
+                "channelId": {
+                    "type": "integer"
+                },
+                "generatedAt": {
+                    "type": "string"
+                },
+                "messageCount": {
+                    "type": "integer"
+                },
+                "topMembers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/chat-service_internal_models.MemberActivity"
+                    }
+                },
+                "topReactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/chat-service_internal_models.ReactionUsage"
+                    }
+                }
+            }
+        },
+        "chat-service_internal_models.ChannelSummaryResponse": {
+            "type": "object",
+            "properties": {
+                "channelId": {
+                    "type": "integer"
+                },
+                "lastReadMessageId": {
+                    "type": "integer"
+                },
+                "latestMessage": {
+                    "description": "null for an empty channel",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/chat-service_internal_models.ChatResponse"
+                        }
+                    ]
+                },
+                "totalMessages": {
+                    "type": "integer"
+                },
+                "unreadCount": {
+                    "description": "messages from others after the caller's read pointer",
+                    "type": "integer"
+                }
+            }
+        },
+        "chat-service_internal_models.ChannelWebhook": {
+            "type": "object",
+            "properties": {
+                "channelId": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "integer"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lastDeliveredAt": {
+                    "type": "string"
+                },
+                "lastError": {
+                    "type": "string"
+                },
+                "lastErrorAt": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "chat-service_internal_models.ChannelWebhookCreatedResponse": {
+            "type": "object",
+            "properties": {
+                "channelId": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "integer"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lastDeliveredAt": {
+                    "type": "string"
+                },
+                "lastError": {
+                    "type": "string"
+                },
+                "lastErrorAt": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "chat-service_internal_models.ChatHistoryPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/chat-service_internal_models.ChatResponse"
+                    }
+                },
+                "nextCursor": {
+                    "type": "integer"
+                }
+            }
+        },
+        "chat-service_internal_models.ChatResponse": {
+            "type": "object",
+            "properties": {
+                "attachments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/chat-service_internal_models.Attachment"
+                    }
+                },
+                "channelId": {
+                    "description": "channel",
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "description": "timestamp of when the message was created",
+                    "type": "string"
+                },
+                "deleted": {
+                    "description": "tombstone: content is withheld",
+                    "type": "boolean"
+                },
+                "editedAt": {
+                    "description": "last edit time, absent if never edited",
+                    "type": "string"
+                },
+                "fileName": {
+                    "description": "optional file name for media",
+                    "type": "string"
+                },
+                "forwardedFrom": {
+                    "description": "original message ID when forwarded",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "parentId": {
+                    "description": "thread parent when this is a reply",
+                    "type": "integer"
+                },
+                "receiverId": {
+                    "description": "Relate to type message",
+                    "type": "integer"
+                },
+                "senderAvatar": {
+                    "description": "url string for avatar",
+                    "type": "string"
+                },
+                "senderId": {
+                    "description": "ID of the user who sent the message",
+                    "type": "integer"
+                },
+                "senderName": {
+                    "description": "Username of the sender",
+                    "type": "string"
+                },
+                "text": {
+                    "description": "free text message",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "\"direct\" | \"group\"",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "optional URL for media",
+                    "type": "string"
+                },
+                "uuid": {
+                    "description": "public message ID",
+                    "type": "string"
+                }
+            }
+        },
+        "chat-service_internal_models.CreateChannelRequest": {
+            "type": "object",
+            "required": [
+                "type",
+                "userIds"
+            ],
+            "properties": {
+                "name": {
+                    "description": "Optional for direct messages, required for group",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "direct",
+                        "group"
+                    ]
+                },
+                "userIds": {
+                    "description": "Minimum 2, maximum 4 users",
+                    "type": "array",
+                    "maxItems": 4,
+                    "minItems": 2,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "chat-service_internal_models.CreateChannelWebhookRequest": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "url": {
+                    "description": "https, public hosts only",
+                    "type": "string",
+                    "maxLength": 2048
+                }
+            }
+        },
+        "chat-service_internal_models.CreateInboundWebhookRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "description": "display name of the bot",
+                    "type": "string",
+                    "maxLength": 40,
+                    "minLength": 3
+                }
+            }
+        },
+        "chat-service_internal_models.CreateInviteRequest": {
+            "type": "object",
+            "properties": {
+                "expiresIn": {
+                    "description": "seconds, omit for no expiry",
+                    "type": "integer",
+                    "maximum": 2592000,
+                    "minimum": 60
+                },
+                "maxUses": {
+                    "description": "omit for unlimited",
+                    "type": "integer",
+                    "maximum": 10000,
+                    "minimum": 1
+                }
+            }
+        },
+        "chat-service_internal_models.DirectChannelResponse": {
+            "type": "object",
+            "properties": {
+                "archived": {
+                    "type": "boolean"
+                },
+                "avatar": {
+                    "description": "Optional avatar for direct channels",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "ownerId": {
+                    "type": "integer"
+                },
+                "slowModeSeconds": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "chat-service_internal_models.EditMessageRequest": {
+            "type": "object",
+            "required": [
+                "text"
+            ],
+            "properties": {
+                "text": {
+                    "description": "size limit enforced by the service (413)",
+                    "type": "string"
+                }
+            }
+        },
+        "chat-service_internal_models.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "details": {
+                    "type": "string"
+                },
+                "errors": {
+                    "description": "field-level validation failures",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/chat-service_internal_models.FieldError"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "chat-service_internal_models.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "param": {
+                    "type": "string"
+                },
+                "rule": {
+                    "type": "string"
+                }
+            }
+        },
+        "chat-service_internal_models.ForceDisconnectRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "chat-service_internal_models.ForceDisconnectResponse": {
+            "type": "object",
+            "properties": {
+                "closedLocally": {
+                    "description": "other instances are notified asynchronously",
+                    "type": "boolean"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "chat-service_internal_models.ForwardMessageRequest": {
+            "type": "object",
+            "required": [
+                "messageId",
+                "targetChannelId"
+            ],
+            "properties": {
+                "messageId": {
+                    "type": "integer"
+                },
+                "targetChannelId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "chat-service_internal_models.InboundWebhook": {
+            "type": "object",
+            "properties": {
+                "botUserId": {
+                    "type": "integer"
+                },
+                "channelId": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "integer"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "chat-service_internal_models.InboundWebhookCreatedResponse": {
+            "type": "object",
+            "properties": {
+                "botUserId": {
                     "type": "integer"
                 },
-                "type": {
-                    "description": "Type of channel, either 'direct' or 'group'",
+                "channelId": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "integer"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "token": {
                     "type": "string"
                 },
-                "updated_at": {
+                "updatedAt": {
                     "type": "string"
                 }
             }
         },
-        "chat-service_internal_models.ChannelDetailResponse": {
+        "chat-service_internal_models.InboundWebhookMessageRequest": {
+            "type": "object",
+            "required": [
+                "channelId",
+                "text"
+            ],
+            "properties": {
+                "channelId": {
+                    "type": "integer"
+                },
+                "text": {
+                    "description": "limited to the configured message size",
+                    "type": "string"
+                }
+            }
+        },
+        "chat-service_internal_models.Invite": {
             "type": "object",
             "properties": {
+                "channelId": {
+                    "type": "integer"
+                },
                 "createdAt": {
                     "type": "string"
                 },
+                "createdBy": {
+                    "type": "integer"
+                },
+                "expiresAt": {
+                    "description": "nil never expires",
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "members": {
-                    "description": "List of members in the channel",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/chat-service_internal_models.User"
-                    }
+                "maxUses": {
+                    "description": "0 is unlimited",
+                    "type": "integer"
                 },
-                "name": {
+                "token": {
                     "type": "string"
                 },
-                "ownerId": {
+                "uses": {
+                    "type": "integer"
+                }
+            }
+        },
+        "chat-service_internal_models.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "chat-service_internal_models.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "refreshToken": {
+                    "description": "exchange at /auth/refresh when the token expires",
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/chat-service_internal_models.UserResponse"
+                }
+            }
+        },
+        "chat-service_internal_models.MarkReadByTimeRequest": {
+            "type": "object",
+            "required": [
+                "readAt"
+            ],
+            "properties": {
+                "readAt": {
+                    "description": "RFC 3339",
+                    "type": "string"
+                }
+            }
+        },
+        "chat-service_internal_models.MemberActivity": {
+            "type": "object",
+            "properties": {
+                "messageCount": {
                     "type": "integer"
                 },
-                "type": {
+                "userId": {
+                    "type": "integer"
+                },
+                "username": {
                     "type": "string"
                 }
             }
         },
-        "chat-service_internal_models.ChannelResponse": {
+        "chat-service_internal_models.MessageQuotaUsage": {
+            "type": "object",
+            "properties": {
+                "exempt": {
+                    "description": "admins are not limited",
+                    "type": "boolean"
+                },
+                "limit": {
+                    "description": "0 when no quota is configured",
+                    "type": "integer"
+                },
+                "resetsAt": {
+                    "description": "next UTC midnight",
+                    "type": "string"
+                },
+                "used": {
+                    "type": "integer"
+                }
+            }
+        },
+        "chat-service_internal_models.MessageSearchResult": {
             "type": "object",
             "properties": {
+                "channelId": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "name": {
+                "rank": {
+                    "type": "number"
+                },
+                "senderId": {
+                    "type": "integer"
+                },
+                "senderName": {
                     "type": "string"
                 },
-                "ownerId": {
+                "snippet": {
+                    "type": "string"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "chat-service_internal_models.MuteChannelRequest": {
+            "type": "object",
+            "properties": {
+                "until": {
+                    "description": "RFC 3339, omit to mute until unmuted",
+                    "type": "string"
+                }
+            }
+        },
+        "chat-service_internal_models.PaginatedChatResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/chat-service_internal_models.ChatResponse"
+                    }
+                },
+                "nextCursor": {
                     "type": "integer"
                 },
-                "type": {
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "chat-service_internal_models.PinEvent": {
+            "type": "object",
+            "properties": {
+                "changed": {
+                    "description": "false when the message was already pinned or unpinned",
+                    "type": "boolean"
+                },
+                "channelId": {
+                    "type": "integer"
+                },
+                "messageId": {
+                    "type": "integer"
+                },
+                "pinnedAt": {
+                    "description": "absent for unpins",
                     "type": "string"
+                },
+                "userId": {
+                    "description": "who pinned, or who unpinned",
+                    "type": "integer"
                 }
             }
         },
-        "chat-service_internal_models.ChatResponse": {
+        "chat-service_internal_models.PinnedMessageResponse": {
             "type": "object",
             "properties": {
+                "attachments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/chat-service_internal_models.Attachment"
+                    }
+                },
                 "channelId": {
                     "description": "channel",
                     "type": "integer"
@@ -893,13 +4882,35 @@ const docTemplate = `{
                     "description": "timestamp of when the message was created",
                     "type": "string"
                 },
+                "deleted": {
+                    "description": "tombstone: content is withheld",
+                    "type": "boolean"
+                },
+                "editedAt": {
+                    "description": "last edit time, absent if never edited",
+                    "type": "string"
+                },
                 "fileName": {
                     "description": "optional file name for media",
                     "type": "string"
                 },
+                "forwardedFrom": {
+                    "description": "original message ID when forwarded",
+                    "type": "integer"
+                },
                 "id": {
                     "type": "integer"
                 },
+                "parentId": {
+                    "description": "thread parent when this is a reply",
+                    "type": "integer"
+                },
+                "pinnedAt": {
+                    "type": "string"
+                },
+                "pinnedBy": {
+                    "type": "integer"
+                },
                 "receiverId": {
                     "description": "Relate to type message",
                     "type": "integer"
@@ -927,116 +4938,119 @@ const docTemplate = `{
                 "url": {
                     "description": "optional URL for media",
                     "type": "string"
-                }
-            }
-        },
-        "chat-service_internal_models.CreateChannelRequest": {
-            "type": "object",
-            "required": [
-                "type",
-                "userIds"
-            ],
-            "properties": {
-                "name": {
-                    "description": "Optional for direct messages, required for group",
-                    "type": "string"
-                },
-                "type": {
-                    "type": "string",
-                    "enum": [
-                        "direct",
-                        "group"
-                    ]
                 },
-                "userIds": {
-                    "description": "Minimum 2, maximum 4 users",
-                    "type": "array",
-                    "maxItems": 4,
-                    "minItems": 2,
-                    "items": {
-                        "type": "integer"
-                    }
+                "uuid": {
+                    "description": "public message ID",
+                    "type": "string"
                 }
             }
         },
-        "chat-service_internal_models.DirectChannelResponse": {
+        "chat-service_internal_models.ReactionEvent": {
             "type": "object",
             "properties": {
-                "avatar": {
-                    "description": "Optional avatar for direct channels",
-                    "type": "string"
+                "changed": {
+                    "description": "false when the operation was a no-op",
+                    "type": "boolean"
                 },
-                "id": {
+                "channelId": {
                     "type": "integer"
                 },
-                "name": {
+                "count": {
+                    "description": "total reactions with this emoji after the change",
+                    "type": "integer"
+                },
+                "emoji": {
                     "type": "string"
                 },
-                "ownerId": {
+                "messageId": {
                     "type": "integer"
                 },
-                "type": {
+                "op": {
+                    "description": "\"add\" | \"remove\"",
                     "type": "string"
+                },
+                "reactions": {
+                    "description": "every emoji on the message after the change",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/chat-service_internal_models.ReactionSummary"
+                    }
+                },
+                "userId": {
+                    "type": "integer"
                 }
             }
         },
-        "chat-service_internal_models.ErrorResponse": {
+        "chat-service_internal_models.ReactionRequest": {
             "type": "object",
+            "required": [
+                "emoji"
+            ],
             "properties": {
-                "code": {
-                    "type": "integer"
-                },
-                "details": {
-                    "type": "string"
-                },
-                "message": {
-                    "type": "string"
+                "emoji": {
+                    "type": "string",
+                    "maxLength": 32
                 }
             }
         },
-        "chat-service_internal_models.LoginRequest": {
+        "chat-service_internal_models.ReactionSummary": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
-                    "type": "string"
+                "count": {
+                    "type": "integer"
                 },
-                "password": {
+                "emoji": {
                     "type": "string"
+                },
+                "userIds": {
+                    "description": "in reaction order",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 }
             }
         },
-        "chat-service_internal_models.LoginResponse": {
+        "chat-service_internal_models.ReactionUsage": {
             "type": "object",
             "properties": {
-                "token": {
-                    "type": "string"
+                "count": {
+                    "type": "integer"
                 },
-                "user": {
-                    "$ref": "#/definitions/chat-service_internal_models.UserResponse"
+                "emoji": {
+                    "type": "string"
                 }
             }
         },
-        "chat-service_internal_models.PaginatedChatResponse": {
+        "chat-service_internal_models.ReadStateResponse": {
             "type": "object",
             "properties": {
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/chat-service_internal_models.ChatResponse"
-                    }
+                "advanced": {
+                    "description": "false when the pointer was already at or past the requested point",
+                    "type": "boolean"
                 },
-                "nextCursor": {
+                "channelId": {
                     "type": "integer"
                 },
-                "total": {
+                "lastReadAt": {
+                    "type": "string"
+                },
+                "lastReadMessageId": {
                     "type": "integer"
                 }
             }
         },
+        "chat-service_internal_models.RefreshTokenRequest": {
+            "type": "object",
+            "required": [
+                "refreshToken"
+            ],
+            "properties": {
+                "refreshToken": {
+                    "type": "string"
+                }
+            }
+        },
         "chat-service_internal_models.RegisterRequest": {
             "type": "object",
             "required": [
@@ -1059,6 +5073,57 @@ const docTemplate = `{
                 }
             }
         },
+        "chat-service_internal_models.ThreadResponse": {
+            "type": "object",
+            "properties": {
+                "parent": {
+                    "$ref": "#/definitions/chat-service_internal_models.ChatResponse"
+                },
+                "replies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/chat-service_internal_models.ChatResponse"
+                    }
+                }
+            }
+        },
+        "chat-service_internal_models.TokenResponse": {
+            "type": "object",
+            "properties": {
+                "refreshToken": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "chat-service_internal_models.UpdateChannelWebhookRequest": {
+            "type": "object",
+            "required": [
+                "enabled"
+            ],
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "chat-service_internal_models.UpdateMemberRoleRequest": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "admin",
+                        "member"
+                    ]
+                }
+            }
+        },
         "chat-service_internal_models.UpdateProfileRequest": {
             "type": "object",
             "required": [
@@ -1084,6 +5149,20 @@ const docTemplate = `{
                 }
             }
         },
+        "chat-service_internal_models.UpdateSlowModeRequest": {
+            "type": "object",
+            "required": [
+                "seconds"
+            ],
+            "properties": {
+                "seconds": {
+                    "description": "0 disables slow mode",
+                    "type": "integer",
+                    "maximum": 21600,
+                    "minimum": 0
+                }
+            }
+        },
         "chat-service_internal_models.User": {
             "type": "object",
             "properties": {
@@ -1097,10 +5176,10 @@ const docTemplate = `{
                         "$ref": "#/definitions/chat-service_internal_models.Channel"
                     }
                 },
-                "created_at": {
+                "createdAt": {
                     "type": "string"
                 },
-                "deleted_at": {
+                "deletedAt": {
                     "$ref": "#/definitions/gorm.DeletedAt"
                 },
                 "email": {
@@ -1110,7 +5189,11 @@ const docTemplate = `{
                 "id": {
                     "type": "integer"
                 },
-                "updated_at": {
+                "isBot": {
+                    "description": "IsBot marks integration identities that post through inbound webhooks and cannot log in",
+                    "type": "boolean"
+                },
+                "updatedAt": {
                     "type": "string"
                 },
                 "username": {
@@ -1138,6 +5221,27 @@ const docTemplate = `{
                 }
             }
         },
+        "chat-service_internal_models.UserDirectoryPage": {
+            "type": "object",
+            "properties": {
+                "hasMore": {
+                    "description": "another page follows at offset+limit",
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/chat-service_internal_models.UserResponse"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                }
+            }
+        },
         "chat-service_internal_models.UserResponse": {
             "type": "object",
             "properties": {
@@ -1158,6 +5262,309 @@ const docTemplate = `{
                 }
             }
         },
+        "chat-service_internal_websocket.ChannelPresence": {
+            "type": "object",
+            "properties": {
+                "channelId": {
+                    "type": "string"
+                },
+                "online": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "chat-service_internal_websocket.ChannelSize": {
+            "type": "object",
+            "properties": {
+                "channelId": {
+                    "type": "string"
+                },
+                "clients": {
+                    "type": "integer"
+                }
+            }
+        },
+        "chat-service_internal_websocket.ConnectionMetadata": {
+            "type": "object",
+            "properties": {
+                "channels": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "connectedAt": {
+                    "type": "string"
+                },
+                "heartbeatCount": {
+                    "type": "integer"
+                },
+                "lastActivity": {
+                    "type": "string"
+                },
+                "lastHeartbeat": {
+                    "type": "string"
+                },
+                "quality": {
+                    "$ref": "#/definitions/chat-service_internal_websocket.QualityReport"
+                },
+                "remoteAddr": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/chat-service_internal_websocket.PresenceStatus"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "chat-service_internal_websocket.ConnectionState": {
+            "type": "object",
+            "properties": {
+                "connection": {
+                    "$ref": "#/definitions/chat-service_internal_websocket.ConnectionMetadata"
+                },
+                "globalOnline": {
+                    "description": "reported online in Redis by any instance",
+                    "type": "boolean"
+                },
+                "local": {
+                    "description": "connected to this instance",
+                    "type": "boolean"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "chat-service_internal_websocket.ErrorHistory": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "description": "newest first, after filtering",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/chat-service_internal_websocket.HubErrorEntry"
+                    }
+                },
+                "rates": {
+                    "description": "errors per minute over the last minute, by type",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "totals": {
+                    "description": "all errors counted since start, by type",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "chat-service_internal_websocket.FieldSpec": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "required": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "chat-service_internal_websocket.HubErrorEntry": {
+            "type": "object",
+            "properties": {
+                "severity": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "chat-service_internal_websocket.MessageSpec": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "fields": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/chat-service_internal_websocket.FieldSpec"
+                    }
+                },
+                "type": {
+                    "$ref": "#/definitions/chat-service_internal_websocket.MessageType"
+                }
+            }
+        },
+        "chat-service_internal_websocket.MessageType": {
+            "type": "string",
+            "enum": [
+                "connection.connect",
+                "connection.disconnect",
+                "connection.force_logout",
+                "connection.reconnect_hint",
+                "connection.resume",
+                "user.presence",
+                "user.quota_exceeded",
+                "presence.subscribe",
+                "presence.unsubscribe",
+                "presence.update",
+                "channel.join",
+                "channel.leave",
+                "channel.message",
+                "channel.reaction",
+                "channel.typing",
+                "channel.read",
+                "channel.message.edit",
+                "channel.message.delete",
+                "channel.message.pin",
+                "channel.message.unpin",
+                "channel.message.ack",
+                "channel.message.nack",
+                "direct.message",
+                "error"
+            ],
+            "x-enum-varnames": [
+                "MessageTypeConnect",
+                "MessageTypeDisconnect",
+                "MessageTypeForceLogout",
+                "MessageTypeReconnectHint",
+                "MessageTypeResume",
+                "MessageTypePresence",
+                "MessageTypeQuotaExceeded",
+                "MessageTypePresenceSubscribe",
+                "MessageTypePresenceUnsubscribe",
+                "MessageTypePresenceUpdate",
+                "MessageTypeJoinChannel",
+                "MessageTypeLeaveChannel",
+                "MessageTypeChannelMessage",
+                "MessageTypeReaction",
+                "MessageTypeTyping",
+                "MessageTypeRead",
+                "MessageTypeMessageEdit",
+                "MessageTypeMessageDelete",
+                "MessageTypeMessagePin",
+                "MessageTypeMessageUnpin",
+                "MessageTypeMessageAck",
+                "MessageTypeMessageNack",
+                "MessageTypeDirectMessage",
+                "MessageTypeError"
+            ]
+        },
+        "chat-service_internal_websocket.PresenceStatus": {
+            "type": "string",
+            "enum": [
+                "online",
+                "away",
+                "offline"
+            ],
+            "x-enum-varnames": [
+                "PresenceOnline",
+                "PresenceAway",
+                "PresenceOffline"
+            ]
+        },
+        "chat-service_internal_websocket.ProtocolDescription": {
+            "type": "object",
+            "properties": {
+                "clientActions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/chat-service_internal_websocket.MessageSpec"
+                    }
+                },
+                "envelopeVersion": {
+                    "description": "value of the \"version\" field in message envelopes",
+                    "type": "integer"
+                },
+                "serverEvents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/chat-service_internal_websocket.MessageSpec"
+                    }
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "chat-service_internal_websocket.QualityReport": {
+            "type": "object",
+            "properties": {
+                "latencyMs": {
+                    "type": "integer"
+                },
+                "missedPongs": {
+                    "type": "integer"
+                },
+                "pingsSent": {
+                    "type": "integer"
+                },
+                "pongsReceived": {
+                    "type": "integer"
+                },
+                "score": {
+                    "description": "0 (unusable) to 100 (healthy)",
+                    "type": "integer"
+                },
+                "slowWrites": {
+                    "type": "integer"
+                },
+                "writeErrors": {
+                    "type": "integer"
+                }
+            }
+        },
+        "chat-service_internal_websocket.UserPresence": {
+            "type": "object",
+            "properties": {
+                "channels": {
+                    "description": "joined channels on this instance",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "lastActivity": {
+                    "description": "last activity, or last status change when not local",
+                    "type": "string"
+                },
+                "lastSeen": {
+                    "description": "when an offline user was last connected",
+                    "type": "string"
+                },
+                "online": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "description": "online or away, only known for local connections",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/chat-service_internal_websocket.PresenceStatus"
+                        }
+                    ]
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
         "gorm.DeletedAt": {
             "type": "object",
             "properties": {
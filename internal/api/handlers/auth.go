@@ -3,6 +3,7 @@ package handlers
 import (
 	"chat-service/internal/models"
 	"chat-service/internal/services"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -32,11 +33,7 @@ func NewAuthHandler(userService *services.UserService, redisClient *redis.Client
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req models.RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Invalid input data",
-			Details: "Invalid input request",
-		})
+		c.JSON(http.StatusBadRequest, newBindingErrorResponse("Invalid input data", err))
 		return
 	}
 
@@ -78,11 +75,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req models.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Invalid input data",
-			Details: "Invalid input request",
-		})
+		c.JSON(http.StatusBadRequest, newBindingErrorResponse("Invalid input data", err))
 		return
 	}
 
@@ -98,3 +91,74 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 	c.JSON(http.StatusOK, loginResponse)
 }
+
+// Refresh godoc
+// @Summary Refresh an access token
+// @Description Exchange a refresh token for a new access token. The refresh token is single use and a replacement is returned; presenting a used token revokes all of the user's refresh tokens.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} models.TokenResponse "New access and refresh tokens"
+// @Failure 400 {object} models.ErrorResponse "Bad request - invalid input data"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid, expired or reused refresh token"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req models.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, newBindingErrorResponse("Invalid input data", err))
+		return
+	}
+
+	tokens, err := h.userService.Refresh(req.RefreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidRefreshToken), errors.Is(err, services.ErrRefreshTokenReused):
+			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+				Code:    http.StatusUnauthorized,
+				Message: "Unauthorized",
+				Details: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to refresh token",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Logout godoc
+// @Summary Log out
+// @Description Revoke a refresh token. Access tokens already issued stay valid until they expire.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} map[string]string "Logged out"
+// @Failure 400 {object} models.ErrorResponse "Bad request - invalid input data"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req models.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, newBindingErrorResponse("Invalid input data", err))
+		return
+	}
+
+	if err := h.userService.Logout(req.RefreshToken); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to log out",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out"})
+}
@@ -1,40 +1,47 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"chat-service/internal/models"
 	"chat-service/internal/services"
+	"chat-service/internal/websocket"
 
 	"github.com/gin-gonic/gin"
 )
 
 type ChannelHandler struct {
 	channelService *services.ChannelService
+	statsService   *services.ChannelStatsService
+	readService    *services.ReadStateService
+	hub            *websocket.Hub
 }
 
 // Ensure models package is imported for Swagger generation
 var _ models.ChannelResponse
 
-func NewChannelHandler(channelService *services.ChannelService) *ChannelHandler {
-	return &ChannelHandler{channelService: channelService}
+func NewChannelHandler(channelService *services.ChannelService, statsService *services.ChannelStatsService, readService *services.ReadStateService, hub *websocket.Hub) *ChannelHandler {
+	return &ChannelHandler{channelService: channelService, statsService: statsService, readService: readService, hub: hub}
 }
 
 // GetUserChannels godoc
 // @Summary Get user's channels
-// @Description Get all channels that the current user is a member of, separated by type
+// @Description Get all channels that the current user is a member of, separated by type. Archived channels are only listed with includeArchived=true.
 // @Tags channels
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param includeArchived query bool false "Also list archived channels"
 // @Success 200 {object} models.UserChannelsResponse "Object with direct and group channel lists"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /channels/ [get]
 func (h *ChannelHandler) GetUserChannels(c *gin.Context) {
 	userID := c.MustGet("user_id").(uint)
-	directChannels, groupChannels, err := h.channelService.GetAllChannel(userID)
+	includeArchived := c.Query("includeArchived") == "true"
+	directChannels, groupChannels, err := h.channelService.GetAllChannel(userID, includeArchived)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Code:    http.StatusInternalServerError,
@@ -59,8 +66,9 @@ func (h *ChannelHandler) GetUserChannels(c *gin.Context) {
 // @Security BearerAuth
 // @Param request body models.CreateChannelRequest true "Channel creation data with user selection"
 // @Success 200 {object} models.ChannelResponse "Channel created successfully"
-// @Failure 400 {object} models.ErrorResponse "Bad request - invalid input data"
+// @Failure 400 {object} models.ErrorResponse "Bad request - invalid input data or unknown user"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - owned channel limit reached"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /channels/ [post]
 func (h *ChannelHandler) CreateChannel(c *gin.Context) {
@@ -68,11 +76,7 @@ func (h *ChannelHandler) CreateChannel(c *gin.Context) {
 
 	var req models.CreateChannelRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Invalid input data",
-			Details: err.Error(),
-		})
+		c.JSON(http.StatusBadRequest, newBindingErrorResponse("Invalid input data", err))
 		return
 	}
 
@@ -115,11 +119,26 @@ func (h *ChannelHandler) CreateChannel(c *gin.Context) {
 
 	channel, err := h.channelService.CreateChannelWithUsers(req.Name, userID, req.Type, req.UserIDs)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to create channel",
-			Details: err.Error(),
-		})
+		switch {
+		case errors.Is(err, services.ErrChannelLimitReached):
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "Channel limit reached",
+				Details: err.Error(),
+			})
+		case errors.Is(err, services.ErrUserNotFound):
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid user",
+				Details: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to create channel",
+				Details: err.Error(),
+			})
+		}
 		return
 	}
 	c.JSON(http.StatusOK, channel)
@@ -137,6 +156,7 @@ func (h *ChannelHandler) CreateChannel(c *gin.Context) {
 // @Success 200 {object} map[string]string "Channel updated successfully"
 // @Failure 400 {object} models.ErrorResponse "Bad request - invalid input data"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 404 {object} models.ErrorResponse "Channel not found"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /channels/{id} [put]
 func (h *ChannelHandler) UpdateChannel(c *gin.Context) {
@@ -145,15 +165,19 @@ func (h *ChannelHandler) UpdateChannel(c *gin.Context) {
 		Name string `json:"name"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Invalid input data",
-			Details: err.Error(),
-		})
+		c.JSON(http.StatusBadRequest, newBindingErrorResponse("Invalid input data", err))
 		return
 	}
 	err := h.channelService.UpdateChannel(uint(id), req.Name)
 	if err != nil {
+		if errors.Is(err, services.ErrChannelNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "Channel not found",
+				Details: err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Code:    http.StatusInternalServerError,
 			Message: "Update failed",
@@ -173,42 +197,115 @@ func (h *ChannelHandler) UpdateChannel(c *gin.Context) {
 // @Security BearerAuth
 // @Param id path int true "Channel ID"
 // @Success 200 {object} map[string]string "Channel deleted successfully"
-// @Failure 400 {object} models.ErrorResponse "Bad request - channel not found or user is not owner"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
 // @Failure 403 {object} models.ErrorResponse "Forbidden - only channel owner can delete channel"
+// @Failure 404 {object} models.ErrorResponse "Channel not found"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /channels/{id} [delete]
 func (h *ChannelHandler) DeleteChannel(c *gin.Context) {
 	userID := c.MustGet("user_id").(uint)
 	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err := h.channelService.DeleteChannel(userID, uint(id)); err != nil {
-		// Check specific error types to return appropriate HTTP status codes
-		if err.Error() == "channel not found" {
+		switch {
+		case errors.Is(err, services.ErrChannelNotFound):
 			c.JSON(http.StatusNotFound, models.ErrorResponse{
 				Code:    http.StatusNotFound,
 				Message: "Channel not found",
 				Details: err.Error(),
 			})
-			return
-		}
-		if err.Error() == "only channel owner can delete channel" {
+		case errors.Is(err, services.ErrInsufficientRole):
 			c.JSON(http.StatusForbidden, models.ErrorResponse{
 				Code:    http.StatusForbidden,
 				Message: "Forbidden",
 				Details: err.Error(),
 			})
-			return
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Delete failed",
+				Details: err.Error(),
+			})
 		}
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Delete failed",
-			Details: err.Error(),
-		})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Channel deleted"})
 }
 
+// ArchiveChannel godoc
+// @Summary Archive a channel
+// @Description Make a channel read-only (only channel owner). Members and history are kept; new messages are rejected and the channel is hidden from the default channel list.
+// @Tags channels
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Channel ID"
+// @Success 200 {object} models.ChannelResponse "Channel archived"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - only channel owner can archive"
+// @Failure 404 {object} models.ErrorResponse "Channel not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /channels/{id}/archive [post]
+func (h *ChannelHandler) ArchiveChannel(c *gin.Context) {
+	h.setArchived(c, true)
+}
+
+// UnarchiveChannel godoc
+// @Summary Unarchive a channel
+// @Description Reopen an archived channel for new messages (only channel owner)
+// @Tags channels
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Channel ID"
+// @Success 200 {object} models.ChannelResponse "Channel unarchived"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - only channel owner can unarchive"
+// @Failure 404 {object} models.ErrorResponse "Channel not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /channels/{id}/unarchive [post]
+func (h *ChannelHandler) UnarchiveChannel(c *gin.Context) {
+	h.setArchived(c, false)
+}
+
+func (h *ChannelHandler) setArchived(c *gin.Context, archived bool) {
+	userID := c.MustGet("user_id").(uint)
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+
+	channel, err := h.channelService.SetArchived(userID, uint(id), archived)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrChannelNotFound):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "Channel not found",
+				Details: err.Error(),
+			})
+		case errors.Is(err, services.ErrInsufficientRole):
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "Forbidden",
+				Details: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to update channel",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+	h.hub.InvalidateChannelSettings(channel.ID)
+
+	c.JSON(http.StatusOK, models.ChannelResponse{
+		ID:      channel.ID,
+		Name:    channel.Name,
+		Type:    channel.Type,
+		OwnerID: channel.OwnerID,
+
+		SlowModeSeconds: channel.SlowModeSeconds,
+		Archived:        channel.Archived,
+	})
+}
+
 // GetChannelByID godoc
 // @Summary Get channel by ID
 // @Description Get detailed information about a specific channel
@@ -220,14 +317,23 @@ func (h *ChannelHandler) DeleteChannel(c *gin.Context) {
 // @Success 200 {object} models.ChannelDetailResponse "Channel details retrieved successfully"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
 // @Failure 404 {object} models.ErrorResponse "Channel not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /channels/{id} [get]
 func (h *ChannelHandler) GetChannelByID(c *gin.Context) {
 	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
 	channel, err := h.channelService.GetChannelByID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.ErrorResponse{
-			Code:    http.StatusNotFound,
-			Message: "Channel not found",
+		if errors.Is(err, services.ErrChannelNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "Channel not found",
+				Details: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get channel",
 			Details: err.Error(),
 		})
 		return
@@ -247,13 +353,340 @@ func (h *ChannelHandler) GetChannelByID(c *gin.Context) {
 		CreatedAt: channel.CreatedAt,
 		OwnerID:   channel.OwnerID,
 		Members:   members,
+
+		SlowModeSeconds: channel.SlowModeSeconds,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// UpdateSlowMode godoc
+// @Summary Update channel slow mode
+// @Description Set the minimum number of seconds between messages per user (only channel owner or admin). 0 disables slow mode.
+// @Tags channels
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Channel ID"
+// @Param request body models.UpdateSlowModeRequest true "Slow mode interval"
+// @Success 200 {object} models.ChannelResponse "Updated channel"
+// @Failure 400 {object} models.ErrorResponse "Bad request - invalid input data or interval out of range"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - only channel owner can change slow mode"
+// @Failure 404 {object} models.ErrorResponse "Channel not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /channels/{id}/slow-mode [put]
+func (h *ChannelHandler) UpdateSlowMode(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+
+	var req models.UpdateSlowModeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, newBindingErrorResponse("Invalid input data", err))
+		return
+	}
+
+	channel, err := h.channelService.SetSlowMode(userID, uint(id), *req.Seconds)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrChannelNotFound):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "Channel not found",
+				Details: err.Error(),
+			})
+		case errors.Is(err, services.ErrInsufficientRole):
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "Forbidden",
+				Details: err.Error(),
+			})
+		case errors.Is(err, services.ErrInvalidSlowMode):
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid input data",
+				Details: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to update slow mode",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+	h.hub.InvalidateChannelSettings(channel.ID)
+
+	c.JSON(http.StatusOK, models.ChannelResponse{
+		ID:      channel.ID,
+		Name:    channel.Name,
+		Type:    channel.Type,
+		OwnerID: channel.OwnerID,
+
+		SlowModeSeconds: channel.SlowModeSeconds,
+	})
+}
+
+// MuteChannel godoc
+// @Summary Mute a channel
+// @Description Mute notifications from a channel until a time, or until unmuted when no time is given. Messages are still delivered over WebSocket with a "muted" hint.
+// @Tags channels
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Channel ID"
+// @Param request body models.MuteChannelRequest false "Mute end time"
+// @Success 200 {object} models.ChannelMute "Channel muted"
+// @Failure 400 {object} models.ErrorResponse "Bad request - invalid input data"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - not a channel member"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /channels/{id}/mute [post]
+func (h *ChannelHandler) MuteChannel(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+
+	var req models.MuteChannelRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, newBindingErrorResponse("Invalid input data", err))
+			return
+		}
+	}
+
+	mute, err := h.channelService.MuteChannel(userID, uint(id), req.Until)
+	if err != nil {
+		if errors.Is(err, services.ErrNotChannelMember) {
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "Forbidden",
+				Details: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to mute channel",
+			Details: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, mute)
+}
+
+// UnmuteChannel godoc
+// @Summary Unmute a channel
+// @Description Remove the caller's mute for a channel
+// @Tags channels
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Channel ID"
+// @Success 200 {object} map[string]string "Channel unmuted"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - not a channel member"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /channels/{id}/mute [delete]
+func (h *ChannelHandler) UnmuteChannel(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+
+	if err := h.channelService.UnmuteChannel(userID, uint(id)); err != nil {
+		if errors.Is(err, services.ErrNotChannelMember) {
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "Forbidden",
+				Details: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to unmute channel",
+			Details: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Channel unmuted"})
+}
+
+// GetChannelStats godoc
+// @Summary Get channel stats
+// @Description Get engagement analytics for a channel: message count, most active members, most used reactions and busiest hour (UTC). Only channel owner or admin. Results are cached briefly.
+// @Tags channels
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Channel ID"
+// @Success 200 {object} models.ChannelStatsResponse "Channel stats"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - only channel owner or admin can view stats"
+// @Failure 404 {object} models.ErrorResponse "Channel not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /channels/{id}/stats [get]
+func (h *ChannelHandler) GetChannelStats(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+
+	stats, err := h.statsService.GetChannelStats(c.Request.Context(), userID, uint(id))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrChannelNotFound):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "Channel not found",
+				Details: err.Error(),
+			})
+		case errors.Is(err, services.ErrStatsAccessDenied):
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "Forbidden",
+				Details: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to get channel stats",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// MarkReadByTime godoc
+// @Summary Mark a direct message thread as read up to a time
+// @Description Set the read pointer to the latest message sent at or before readAt. Useful when a client only knows when it viewed the conversation. The pointer never moves backwards.
+// @Tags channels
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Channel ID"
+// @Param request body models.MarkReadByTimeRequest true "Read time"
+// @Success 200 {object} models.ReadStateResponse "Current read pointer"
+// @Failure 400 {object} models.ErrorResponse "Bad request - invalid input data or not a direct channel"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - not a channel member"
+// @Failure 404 {object} models.ErrorResponse "Channel not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /channels/{id}/read [put]
+func (h *ChannelHandler) MarkReadByTime(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+
+	var req models.MarkReadByTimeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, newBindingErrorResponse("Invalid input data", err))
+		return
+	}
+
+	resp, err := h.readService.MarkDirectReadUpTo(userID, uint(id), req.ReadAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrChannelNotFound):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "Channel not found",
+				Details: err.Error(),
+			})
+		case errors.Is(err, services.ErrNotDirectChannel):
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid channel",
+				Details: err.Error(),
+			})
+		case errors.Is(err, services.ErrNotChannelMember):
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "Forbidden",
+				Details: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to mark as read",
+				Details: err.Error(),
+			})
+		}
+		return
 	}
 	c.JSON(http.StatusOK, resp)
 }
 
+// GetChannelReads godoc
+// @Summary List read pointers for a channel
+// @Description Get every member's last-read message ID so clients can render read markers. Members who have never read anything are omitted.
+// @Tags channels
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Channel ID"
+// @Success 200 {array} models.ChannelRead "Read pointers"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - not a channel member"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /channels/{id}/reads [get]
+func (h *ChannelHandler) GetChannelReads(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+
+	reads, err := h.readService.ListChannelReads(userID, uint(id))
+	if err != nil {
+		if errors.Is(err, services.ErrNotChannelMember) {
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "Forbidden",
+				Details: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get read pointers",
+			Details: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, reads)
+}
+
+// GetChannelSummary godoc
+// @Summary Get a channel summary
+// @Description Get the channel's latest message, its total message count and how many messages from other members arrived after the caller's read pointer. Works for group and direct channels.
+// @Tags channels
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Channel ID"
+// @Success 200 {object} models.ChannelSummaryResponse "Channel summary"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - not a channel member"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /channels/{id}/summary [get]
+func (h *ChannelHandler) GetChannelSummary(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+
+	summary, err := h.readService.ChannelSummary(userID, uint(id))
+	if err != nil {
+		if errors.Is(err, services.ErrNotChannelMember) {
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "Forbidden",
+				Details: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get channel summary",
+			Details: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
+
 // AddUserToChannel godoc
 // @Summary Add user to channel
-// @Description Add a user to a channel (only channel owner can add users)
+// @Description Add a user to a channel as a member (channel owner or admins only)
 // @Tags channels
 // @Accept json
 // @Produce json
@@ -263,6 +696,8 @@ func (h *ChannelHandler) GetChannelByID(c *gin.Context) {
 // @Success 200 {object} map[string]string "User added to channel successfully"
 // @Failure 400 {object} models.ErrorResponse "Bad request - invalid input data"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - channel owner or admin role required"
+// @Failure 404 {object} models.ErrorResponse "Channel or user not found"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /channels/{id}/user [post]
 func (h *ChannelHandler) AddUserToChannel(c *gin.Context) {
@@ -272,20 +707,37 @@ func (h *ChannelHandler) AddUserToChannel(c *gin.Context) {
 		TargetUserID uint `json:"userId"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Invalid input data",
-			Details: err.Error(),
-		})
+		c.JSON(http.StatusBadRequest, newBindingErrorResponse("Invalid input data", err))
 		return
 	}
 	err := h.channelService.AddUserToChannel(userID, uint(channelID), req.TargetUserID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: "Add user failed",
-			Details: err.Error(),
-		})
+		switch {
+		case errors.Is(err, services.ErrChannelNotFound):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "Channel not found",
+				Details: err.Error(),
+			})
+		case errors.Is(err, services.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "User not found",
+				Details: err.Error(),
+			})
+		case errors.Is(err, services.ErrInsufficientRole):
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "Forbidden",
+				Details: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Add user failed",
+				Details: err.Error(),
+			})
+		}
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "User added to channel"})
@@ -301,6 +753,7 @@ func (h *ChannelHandler) AddUserToChannel(c *gin.Context) {
 // @Param id path int true "Channel ID"
 // @Success 200 {object} map[string]string "User left channel successfully"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 404 {object} models.ErrorResponse "Channel not found"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /channels/{id}/user [put]
 func (h *ChannelHandler) LeaveChannel(c *gin.Context) {
@@ -308,6 +761,14 @@ func (h *ChannelHandler) LeaveChannel(c *gin.Context) {
 	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
 	err := h.channelService.LeaveChannel(uint(id), userID)
 	if err != nil {
+		if errors.Is(err, services.ErrChannelNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "Channel not found",
+				Details: err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Code:    http.StatusInternalServerError,
 			Message: "Failed to leave channel",
@@ -320,7 +781,7 @@ func (h *ChannelHandler) LeaveChannel(c *gin.Context) {
 
 // RemoveUserFromChannel godoc
 // @Summary Remove user from channel
-// @Description Remove a user from a channel (only channel owner can remove users)
+// @Description Remove a user from a channel. The owner may remove anyone but themselves; admins may remove members only.
 // @Tags channels
 // @Accept json
 // @Produce json
@@ -328,8 +789,10 @@ func (h *ChannelHandler) LeaveChannel(c *gin.Context) {
 // @Param id path int true "Channel ID"
 // @Param request body map[string]uint true "User removal data"
 // @Success 200 {object} map[string]string "User removed from channel successfully"
-// @Failure 400 {object} models.ErrorResponse "Bad request - invalid input data"
+// @Failure 400 {object} models.ErrorResponse "Bad request - invalid input data or target is the owner"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - role does not outrank the target"
+// @Failure 404 {object} models.ErrorResponse "Channel or user not found"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /channels/{id}/user [delete]
 func (h *ChannelHandler) RemoveUserFromChannel(c *gin.Context) {
@@ -339,21 +802,152 @@ func (h *ChannelHandler) RemoveUserFromChannel(c *gin.Context) {
 		UserID uint `json:"userId"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, newBindingErrorResponse("Invalid input data", err))
+		return
+	}
+	err := h.channelService.RemoveUserFromChannel(userID, uint(channelID), req.UserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrChannelNotFound):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "Channel not found",
+				Details: err.Error(),
+			})
+		case errors.Is(err, services.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "User not found",
+				Details: err.Error(),
+			})
+		case errors.Is(err, services.ErrCannotRemoveOwner):
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid target",
+				Details: err.Error(),
+			})
+		case errors.Is(err, services.ErrInsufficientRole):
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "Forbidden",
+				Details: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Remove user failed",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "User removed from channel"})
+}
+
+// UpdateMemberRole godoc
+// @Summary Change a member's role
+// @Description Promote a member to admin or demote an admin to member. Only the channel owner may change roles.
+// @Tags channels
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Channel ID"
+// @Param userId path int true "Member user ID"
+// @Param request body models.UpdateMemberRoleRequest true "New role"
+// @Success 200 {object} models.ChannelMember "Updated membership"
+// @Failure 400 {object} models.ErrorResponse "Bad request - invalid role or target is the owner"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - only channel owner can change roles"
+// @Failure 404 {object} models.ErrorResponse "Channel not found or user is not a member"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /channels/{id}/members/{userId}/role [put]
+func (h *ChannelHandler) UpdateMemberRole(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	channelID, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+	targetID, err := strconv.ParseUint(c.Param("userId"), 10, 64)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Code:    http.StatusBadRequest,
-			Message: "Invalid input data",
+			Message: "Invalid user ID",
 			Details: err.Error(),
 		})
 		return
 	}
-	err := h.channelService.RemoveUserFromChannel(userID, uint(channelID), req.UserID)
+
+	var req models.UpdateMemberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, newBindingErrorResponse("Invalid input data", err))
+		return
+	}
+
+	member, err := h.channelService.SetMemberRole(userID, uint(channelID), uint(targetID), req.Role)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrChannelNotFound):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "Channel not found",
+				Details: err.Error(),
+			})
+		case errors.Is(err, services.ErrNotChannelMember):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "Member not found",
+				Details: err.Error(),
+			})
+		case errors.Is(err, services.ErrOwnerRoleFixed):
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid target",
+				Details: err.Error(),
+			})
+		case errors.Is(err, services.ErrInsufficientRole):
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "Forbidden",
+				Details: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to update member role",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, member)
+}
+
+// BulkCreateChannels godoc
+// @Summary Provision channels in bulk
+// @Description Admin only. Creates many channels in a single transaction. Items that fail validation or creation are rolled back individually and reported; the rest are kept.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.BulkCreateChannelsRequest true "Channel definitions"
+// @Success 200 {object} models.BulkCreateChannelsResponse "Per-item results"
+// @Failure 400 {object} models.ErrorResponse "Bad request - invalid input data"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - admin privileges required"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /admin/channels/bulk [post]
+func (h *ChannelHandler) BulkCreateChannels(c *gin.Context) {
+	var req models.BulkCreateChannelsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, newBindingErrorResponse("Invalid input data", err))
+		return
+	}
+
+	resp, err := h.channelService.BulkCreateChannels(req.Channels)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Code:    http.StatusInternalServerError,
-			Message: "Remove user failed",
+			Message: "Failed to create channels",
 			Details: err.Error(),
 		})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "User removed from channel"})
+	c.JSON(http.StatusOK, resp)
 }
@@ -0,0 +1,337 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"chat-service/internal/models"
+	"chat-service/internal/services"
+	"chat-service/internal/websocket"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ChannelWebhookHandler struct {
+	webhookService *services.ChannelWebhookService
+	hub            *websocket.Hub
+}
+
+func NewChannelWebhookHandler(webhookService *services.ChannelWebhookService, hub *websocket.Hub) *ChannelWebhookHandler {
+	return &ChannelWebhookHandler{webhookService: webhookService, hub: hub}
+}
+
+// respondWebhookError maps channel webhook service errors to HTTP responses
+func respondWebhookError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrChannelNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "Channel not found",
+			Details: err.Error(),
+		})
+	case errors.Is(err, services.ErrWebhookNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "Webhook not found",
+			Details: err.Error(),
+		})
+	case errors.Is(err, services.ErrInvalidWebhookToken):
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Invalid webhook token",
+			Details: err.Error(),
+		})
+	case errors.Is(err, services.ErrInvalidWebhookURL):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid webhook URL",
+			Details: err.Error(),
+		})
+	case errors.Is(err, services.ErrInvalidWebhookMessage):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+	case errors.Is(err, services.ErrMessageTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, models.ErrorResponse{
+			Code:    http.StatusRequestEntityTooLarge,
+			Message: "Message too large",
+			Details: err.Error(),
+		})
+	case errors.Is(err, services.ErrWebhookRateLimited):
+		c.JSON(http.StatusTooManyRequests, models.ErrorResponse{
+			Code:    http.StatusTooManyRequests,
+			Message: "Rate limit exceeded",
+			Details: err.Error(),
+		})
+	case errors.Is(err, services.ErrChannelArchived):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Code:    http.StatusConflict,
+			Message: "Channel is archived",
+			Details: err.Error(),
+		})
+	case errors.Is(err, services.ErrWebhookAccessDenied):
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Code:    http.StatusForbidden,
+			Message: "Forbidden",
+			Details: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: message,
+			Details: err.Error(),
+		})
+	}
+}
+
+// CreateWebhook godoc
+// @Summary Add a channel webhook
+// @Description Register an https URL that receives every new message in the channel (only channel owner or admin). URLs resolving to loopback, private, link-local or other non-public addresses are rejected. Deliveries are POSTed asynchronously with retries and signed with X-Notify-Signature (HMAC-SHA256 of the body). The signing secret is only returned here.
+// @Tags channels
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Channel ID"
+// @Param request body models.CreateChannelWebhookRequest true "Webhook URL"
+// @Success 201 {object} models.ChannelWebhookCreatedResponse "Webhook created"
+// @Failure 400 {object} models.ErrorResponse "Bad request - invalid input data or webhook URL not https or not public"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - only channel owner or admin can manage webhooks"
+// @Failure 404 {object} models.ErrorResponse "Channel not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /channels/{id}/webhooks [post]
+func (h *ChannelWebhookHandler) CreateWebhook(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	channelID, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+
+	var req models.CreateChannelWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, newBindingErrorResponse("Invalid input data", err))
+		return
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(userID, uint(channelID), req.URL)
+	if err != nil {
+		respondWebhookError(c, "Failed to create webhook", err)
+		return
+	}
+	c.JSON(http.StatusCreated, models.ChannelWebhookCreatedResponse{ChannelWebhook: *webhook, Secret: webhook.Secret})
+}
+
+// ListWebhooks godoc
+// @Summary List channel webhooks
+// @Description List the channel's webhooks with their last delivery status (only channel owner or admin)
+// @Tags channels
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Channel ID"
+// @Success 200 {array} models.ChannelWebhook "Channel webhooks"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - only channel owner or admin can manage webhooks"
+// @Failure 404 {object} models.ErrorResponse "Channel not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /channels/{id}/webhooks [get]
+func (h *ChannelWebhookHandler) ListWebhooks(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	channelID, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+
+	webhooks, err := h.webhookService.ListWebhooks(userID, uint(channelID))
+	if err != nil {
+		respondWebhookError(c, "Failed to list webhooks", err)
+		return
+	}
+	c.JSON(http.StatusOK, webhooks)
+}
+
+// UpdateWebhook godoc
+// @Summary Enable or disable a channel webhook
+// @Description Toggle delivery to a channel webhook (only channel owner or admin)
+// @Tags channels
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Channel ID"
+// @Param webhookId path int true "Webhook ID"
+// @Param request body models.UpdateChannelWebhookRequest true "Enabled flag"
+// @Success 200 {object} models.ChannelWebhook "Updated webhook"
+// @Failure 400 {object} models.ErrorResponse "Bad request - invalid input data"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - only channel owner or admin can manage webhooks"
+// @Failure 404 {object} models.ErrorResponse "Channel or webhook not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /channels/{id}/webhooks/{webhookId} [put]
+func (h *ChannelWebhookHandler) UpdateWebhook(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	channelID, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+	webhookID, _ := strconv.ParseUint(c.Param("webhookId"), 10, 64)
+
+	var req models.UpdateChannelWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, newBindingErrorResponse("Invalid input data", err))
+		return
+	}
+
+	webhook, err := h.webhookService.SetWebhookEnabled(userID, uint(channelID), uint(webhookID), *req.Enabled)
+	if err != nil {
+		respondWebhookError(c, "Failed to update webhook", err)
+		return
+	}
+	c.JSON(http.StatusOK, webhook)
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a channel webhook
+// @Description Remove a channel webhook (only channel owner or admin)
+// @Tags channels
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Channel ID"
+// @Param webhookId path int true "Webhook ID"
+// @Success 200 {object} map[string]string "Webhook deleted"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - only channel owner or admin can manage webhooks"
+// @Failure 404 {object} models.ErrorResponse "Channel or webhook not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /channels/{id}/webhooks/{webhookId} [delete]
+func (h *ChannelWebhookHandler) DeleteWebhook(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	channelID, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+	webhookID, _ := strconv.ParseUint(c.Param("webhookId"), 10, 64)
+
+	if err := h.webhookService.DeleteWebhook(userID, uint(channelID), uint(webhookID)); err != nil {
+		respondWebhookError(c, "Failed to delete webhook", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted"})
+}
+
+// CreateInboundWebhook godoc
+// @Summary Add an inbound webhook
+// @Description Create a bot identity that external systems can post into the channel as, through POST /webhooks/{token} (only channel owner or admin). The token is only returned here.
+// @Tags channels
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Channel ID"
+// @Param request body models.CreateInboundWebhookRequest true "Bot name"
+// @Success 201 {object} models.InboundWebhookCreatedResponse "Inbound webhook created"
+// @Failure 400 {object} models.ErrorResponse "Bad request - invalid input data"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - only channel owner or admin can manage webhooks"
+// @Failure 404 {object} models.ErrorResponse "Channel not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /channels/{id}/inbound-webhooks [post]
+func (h *ChannelWebhookHandler) CreateInboundWebhook(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	channelID, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+
+	var req models.CreateInboundWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, newBindingErrorResponse("Invalid input data", err))
+		return
+	}
+
+	webhook, token, err := h.webhookService.CreateInboundWebhook(userID, uint(channelID), req.Name)
+	if err != nil {
+		respondWebhookError(c, "Failed to create inbound webhook", err)
+		return
+	}
+	c.JSON(http.StatusCreated, models.InboundWebhookCreatedResponse{InboundWebhook: *webhook, Token: token})
+}
+
+// ListInboundWebhooks godoc
+// @Summary List inbound webhooks
+// @Description List the channel's inbound webhooks (only channel owner or admin)
+// @Tags channels
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Channel ID"
+// @Success 200 {array} models.InboundWebhook "Inbound webhooks"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - only channel owner or admin can manage webhooks"
+// @Failure 404 {object} models.ErrorResponse "Channel not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /channels/{id}/inbound-webhooks [get]
+func (h *ChannelWebhookHandler) ListInboundWebhooks(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	channelID, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+
+	webhooks, err := h.webhookService.ListInboundWebhooks(userID, uint(channelID))
+	if err != nil {
+		respondWebhookError(c, "Failed to list inbound webhooks", err)
+		return
+	}
+	c.JSON(http.StatusOK, webhooks)
+}
+
+// DeleteInboundWebhook godoc
+// @Summary Delete an inbound webhook
+// @Description Revoke an inbound webhook token. Messages already posted stay attributed to the bot (only channel owner or admin).
+// @Tags channels
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Channel ID"
+// @Param webhookId path int true "Inbound webhook ID"
+// @Success 200 {object} map[string]string "Inbound webhook deleted"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - only channel owner or admin can manage webhooks"
+// @Failure 404 {object} models.ErrorResponse "Channel or webhook not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /channels/{id}/inbound-webhooks/{webhookId} [delete]
+func (h *ChannelWebhookHandler) DeleteInboundWebhook(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	channelID, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+	webhookID, _ := strconv.ParseUint(c.Param("webhookId"), 10, 64)
+
+	if err := h.webhookService.DeleteInboundWebhook(userID, uint(channelID), uint(webhookID)); err != nil {
+		respondWebhookError(c, "Failed to delete inbound webhook", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Inbound webhook deleted"})
+}
+
+// PostInboundMessage godoc
+// @Summary Post a message through an inbound webhook
+// @Description Post a message into the webhook's channel as its bot identity. No user account is needed; the token in the path authenticates the request. Limited to 30 messages per minute per webhook.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param token path string true "Inbound webhook token"
+// @Param request body models.InboundWebhookMessageRequest true "Message"
+// @Success 201 {object} models.ChatResponse "Message posted"
+// @Failure 400 {object} models.ErrorResponse "Bad request - invalid input data"
+// @Failure 401 {object} models.ErrorResponse "Invalid webhook token"
+// @Failure 409 {object} models.ErrorResponse "Channel is archived"
+// @Failure 413 {object} models.ErrorResponse "Message text exceeds the size limit"
+// @Failure 429 {object} models.ErrorResponse "Rate limit exceeded"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /webhooks/{token} [post]
+func (h *ChannelWebhookHandler) PostInboundMessage(c *gin.Context) {
+	var req models.InboundWebhookMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, newBindingErrorResponse("Invalid input data", err))
+		return
+	}
+
+	chat, err := h.webhookService.PostInboundMessage(c.Request.Context(), c.Param("token"), req.ChannelID, req.Text)
+	if err != nil {
+		respondWebhookError(c, "Failed to post message", err)
+		return
+	}
+
+	h.hub.DeliverChannelMessage(strconv.FormatUint(uint64(chat.SenderID), 10), chat)
+
+	c.JSON(http.StatusCreated, models.ChatResponse{
+		ID:         chat.ID,
+		UUID:       chat.UUID,
+		Type:       chat.GetType(),
+		SenderID:   chat.SenderID,
+		SenderName: chat.Sender.Username,
+		Text:       chat.Text,
+		ChannelID:  &chat.ChannelID,
+		CreatedAt:  chat.CreatedAt,
+	})
+}
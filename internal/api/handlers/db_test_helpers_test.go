@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"bytes"
+	"chat-service/internal/config"
+	"chat-service/internal/database"
+	"chat-service/internal/models"
+	"chat-service/internal/repositories/postgres"
+	"chat-service/internal/services"
+	"chat-service/internal/websocket"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// newTestDB connects to the Postgres database named by NOTIFY_TEST_DATABASE_URL,
+// migrating the schema, and skips the test when the variable is not set. Tests
+// create their own rows with unique names, so the database can be shared.
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("NOTIFY_TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("NOTIFY_TEST_DATABASE_URL not set")
+	}
+	db, err := database.NewPostgresConnection(dsn)
+	if err != nil {
+		t.Fatalf("connect to test database: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+// createTestUser stores a user with a unique username and email
+func createTestUser(t *testing.T, db *gorm.DB) *models.User {
+	t.Helper()
+	name := "test_" + uuid.New().String()[:8]
+	user := &models.User{Username: name, Email: name + "@example.com"}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user
+}
+
+// newTestHub returns a hub without Redis or background routines; broadcasts
+// reach only the clients tests register themselves
+func newTestHub() *websocket.Hub {
+	return websocket.NewHub(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), config.WebSocketConfig{})
+}
+
+// newTestChatHandler wires a ChatHandler to the test database
+func newTestChatHandler(db *gorm.DB) *ChatHandler {
+	chatRepo := postgres.NewChatRepository(db)
+	channelRepo := postgres.NewChannelRepository(db)
+	userRepo := postgres.NewUserRepository(db)
+	return NewChatHandler(
+		services.NewChannelService(channelRepo, userRepo, 0),
+		nil,
+		services.NewChatService(chatRepo, channelRepo, postgres.NewReactionRepository(db), userRepo, 0, 0),
+		chatRepo,
+		newTestHub(),
+	)
+}
+
+// serveAs runs one request through handler, mounted at route, as the given user
+func serveAs(t *testing.T, userID uint, method, route, path string, body interface{}, handler gin.HandlerFunc) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Handle(method, route, func(c *gin.Context) { c.Set("user_id", userID) }, handler)
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("encode body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
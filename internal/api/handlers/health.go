@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"chat-service/internal/websocket"
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// Upper bound for each dependency check in /readyz
+const readinessCheckTimeout = 2 * time.Second
+
+var errHubNotRunning = errors.New("hub run loop is not running")
+
+// HealthHandler serves liveness and readiness probes
+type HealthHandler struct {
+	hub         *websocket.Hub
+	db          *gorm.DB
+	redisClient *redis.Client
+}
+
+func NewHealthHandler(hub *websocket.Hub, db *gorm.DB, redisClient *redis.Client) *HealthHandler {
+	return &HealthHandler{hub: hub, db: db, redisClient: redisClient}
+}
+
+// GetHealthz godoc
+// @Summary WebSocket subsystem health
+// @Description Reports the hub's health (unhealthy while shedding load), active connections on this instance and hub errors in the load shedding window
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Health status"
+// @Router /healthz [get]
+func (h *HealthHandler) GetHealthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":            h.hub.Health(),
+		"activeConnections": h.hub.ActiveConnections(),
+		"recentErrors":      h.hub.RecentErrors(),
+		"redisCircuit":      h.hub.RedisBreakerState(),
+	})
+}
+
+// GetReadyz godoc
+// @Summary Readiness probe
+// @Description Checks Postgres, Redis and the hub run loop. Returns 503 when any of them is down so traffic is routed elsewhere.
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Ready"
+// @Failure 503 {object} map[string]interface{} "A dependency is down"
+// @Router /readyz [get]
+func (h *HealthHandler) GetReadyz(c *gin.Context) {
+	checks := gin.H{}
+	ready := true
+	record := func(name string, err error) {
+		if err != nil {
+			checks[name] = err.Error()
+			ready = false
+			return
+		}
+		checks[name] = "ok"
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessCheckTimeout)
+	defer cancel()
+
+	sqlDB, err := h.db.DB()
+	if err == nil {
+		err = sqlDB.PingContext(ctx)
+	}
+	record("postgres", err)
+	record("redis", h.redisClient.Ping(ctx).Err())
+	if h.hub.RunLoopAlive() {
+		record("hub", nil)
+	} else {
+		record("hub", errHubNotRunning)
+	}
+
+	status, code := "ready", http.StatusOK
+	if !ready {
+		status, code = "not ready", http.StatusServiceUnavailable
+	}
+	c.JSON(code, gin.H{"status": status, "checks": checks})
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"chat-service/internal/models"
+	"chat-service/internal/services"
+	"chat-service/internal/websocket"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type InviteHandler struct {
+	inviteService *services.InviteService
+	hub           *websocket.Hub
+}
+
+func NewInviteHandler(inviteService *services.InviteService, hub *websocket.Hub) *InviteHandler {
+	return &InviteHandler{inviteService: inviteService, hub: hub}
+}
+
+// respondInviteError maps invite service errors to HTTP responses
+func respondInviteError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrChannelNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "Channel not found",
+			Details: err.Error(),
+		})
+	case errors.Is(err, services.ErrInviteNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "Invite not found",
+			Details: err.Error(),
+		})
+	case errors.Is(err, services.ErrInviteExpired), errors.Is(err, services.ErrInviteExhausted):
+		c.JSON(http.StatusGone, models.ErrorResponse{
+			Code:    http.StatusGone,
+			Message: "Invite is no longer valid",
+			Details: err.Error(),
+		})
+	case errors.Is(err, services.ErrNotGroupChannel):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid channel",
+			Details: err.Error(),
+		})
+	case errors.Is(err, services.ErrChannelArchived):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Code:    http.StatusConflict,
+			Message: "Channel is archived",
+			Details: err.Error(),
+		})
+	case errors.Is(err, services.ErrInsufficientRole):
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Code:    http.StatusForbidden,
+			Message: "Forbidden",
+			Details: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: message,
+			Details: err.Error(),
+		})
+	}
+}
+
+// CreateInvite godoc
+// @Summary Create a channel invite link
+// @Description Mint an invite token that lets any signed-in user join the group channel (channel owner or admins only). The invite can expire after expiresIn seconds and stop after maxUses joins; omit either for no limit.
+// @Tags channels
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Channel ID"
+// @Param request body models.CreateInviteRequest false "Invite limits"
+// @Success 201 {object} models.Invite "Invite created"
+// @Failure 400 {object} models.ErrorResponse "Bad request - invalid input data or not a group channel"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - channel owner or admin role required"
+// @Failure 404 {object} models.ErrorResponse "Channel not found"
+// @Failure 409 {object} models.ErrorResponse "Channel is archived"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /channels/{id}/invites [post]
+func (h *InviteHandler) CreateInvite(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	channelID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid channel ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	var req models.CreateInviteRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, newBindingErrorResponse("Invalid input data", err))
+			return
+		}
+	}
+
+	invite, err := h.inviteService.CreateInvite(userID, uint(channelID), req)
+	if err != nil {
+		respondInviteError(c, "Failed to create invite", err)
+		return
+	}
+	c.JSON(http.StatusCreated, invite)
+}
+
+// AcceptInvite godoc
+// @Summary Join a channel through an invite
+// @Description Join the channel an invite token belongs to. Members already in the channel get joined=false and do not use up the invite. Other channel members are sent a channel.join event.
+// @Tags channels
+// @Produce json
+// @Security BearerAuth
+// @Param token path string true "Invite token"
+// @Success 200 {object} models.AcceptInviteResponse "Joined the channel"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 404 {object} models.ErrorResponse "Invite not found"
+// @Failure 409 {object} models.ErrorResponse "Channel is archived"
+// @Failure 410 {object} models.ErrorResponse "Invite has expired or reached its maximum uses"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /invites/{token}/accept [post]
+func (h *InviteHandler) AcceptInvite(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	resp, err := h.inviteService.AcceptInvite(userID, c.Param("token"))
+	if err != nil {
+		respondInviteError(c, "Failed to accept invite", err)
+		return
+	}
+
+	if resp.Joined {
+		channelID := strconv.FormatUint(uint64(resp.ChannelID), 10)
+		senderID := strconv.FormatUint(uint64(userID), 10)
+		h.hub.BroadcastToChannel(channelID, websocket.NewMemberJoinedMessage(uuid.New().String(), senderID, channelID))
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"chat-service/internal/models"
 	"chat-service/internal/repositories/postgres"
@@ -10,17 +12,19 @@ import (
 	"chat-service/internal/websocket"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type ChatHandler struct {
 	channelService *services.ChannelService
 	userService    *services.UserService
+	chatService    *services.ChatService
 	chatRepo       *postgres.ChatRepository
 	hub            *websocket.Hub
 }
 
-func NewChatHandler(chanSvc *services.ChannelService, usrSvc *services.UserService, chatRepo *postgres.ChatRepository, hub *websocket.Hub) *ChatHandler {
-	return &ChatHandler{channelService: chanSvc, userService: usrSvc, chatRepo: chatRepo, hub: hub}
+func NewChatHandler(chanSvc *services.ChannelService, usrSvc *services.UserService, chatSvc *services.ChatService, chatRepo *postgres.ChatRepository, hub *websocket.Hub) *ChatHandler {
+	return &ChatHandler{channelService: chanSvc, userService: usrSvc, chatService: chatSvc, chatRepo: chatRepo, hub: hub}
 }
 
 // GetChannelMessages godoc
@@ -75,6 +79,7 @@ func (h *ChatHandler) GetChannelMessages(c *gin.Context) {
 		channelIDPtr := uint(channelID)
 		responses = append(responses, models.ChatResponse{
 			ID:           m.ID,
+			UUID:         m.UUID,
 			Type:         string(models.ChatTypeChannel), // Set type for channel messages
 			SenderID:     m.SenderID,
 			SenderName:   m.SenderName,
@@ -84,6 +89,9 @@ func (h *ChatHandler) GetChannelMessages(c *gin.Context) {
 			FileName:     m.FileName,
 			CreatedAt:    m.CreatedAt,
 			ChannelID:    &channelIDPtr, // Set channel ID pointer
+
+			ForwardedFrom: m.ForwardedFrom,
+			EditedAt:      m.EditedAt,
 		})
 		unixTime := m.CreatedAt.Unix()
 		nextCursor = &unixTime // last message timestamp for infinite scroll
@@ -95,3 +103,751 @@ func (h *ChatHandler) GetChannelMessages(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, paginated)
 }
+
+// GetChannelHistory godoc
+// @Summary Get channel message history
+// @Description Cursor-paginated message history, newest first. Pass the returned nextCursor as "before" to load the next older page; nextCursor is null on the last page.
+// @Tags chats
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Channel ID"
+// @Param before query int false "Return messages older than this message ID"
+// @Param limit query int false "Page size (default 50, max 100)"
+// @Success 200 {object} models.ChatHistoryPage "Page of messages"
+// @Failure 400 {object} models.ErrorResponse "Bad request - invalid channel ID or cursor"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - not a member of the channel"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /channels/{id}/messages [get]
+func (h *ChatHandler) GetChannelHistory(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	channelID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid channel ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	var before *uint
+	if b := c.Query("before"); b != "" {
+		parsed, err := strconv.ParseUint(b, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid cursor",
+				Details: err.Error(),
+			})
+			return
+		}
+		id := uint(parsed)
+		before = &id
+	}
+
+	limit := 0
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil {
+			limit = parsed
+		}
+	}
+
+	page, err := h.chatService.GetChannelHistory(userID, uint(channelID), before, limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotChannelMember):
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "Forbidden",
+				Details: err.Error(),
+			})
+		case errors.Is(err, services.ErrInvalidCursor):
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid cursor",
+				Details: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to get messages",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// CatchUp godoc
+// @Summary Catch up on several channels
+// @Description For each channel, return the messages newer than the given last seen message ID, oldest first, so a reconnecting client can catch up in one request. Channels the user does not belong to are left out of the response. hasMore is set for a channel when the per-channel cap left messages out; page through those with the channel history.
+// @Tags chats
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CatchupRequest true "Last seen message ID per channel ID"
+// @Param limit query int false "Messages per channel (default 50, max 100)"
+// @Success 200 {object} models.CatchupResponse "New messages per channel"
+// @Failure 400 {object} models.ErrorResponse "Bad request - invalid input data or too many channels"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /messages/catchup [post]
+func (h *ChatHandler) CatchUp(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	var req models.CatchupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, newBindingErrorResponse("Invalid input data", err))
+		return
+	}
+
+	limit := 0
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil {
+			limit = parsed
+		}
+	}
+
+	resp, err := h.chatService.CatchUp(userID, req.Channels, limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrTooManyChannels):
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid input data",
+				Details: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to catch up",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetThread godoc
+// @Summary Get a message thread
+// @Description Get a message and its replies, oldest first. Passing a reply returns the thread it belongs to.
+// @Tags chats
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Message ID"
+// @Success 200 {object} models.ThreadResponse "Parent message and replies"
+// @Failure 400 {object} models.ErrorResponse "Bad request - invalid message ID"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - not a member of the channel"
+// @Failure 404 {object} models.ErrorResponse "Message not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /messages/{id}/thread [get]
+func (h *ChatHandler) GetThread(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	messageID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid message ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	thread, err := h.chatService.GetThread(userID, uint(messageID))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrMessageNotFound):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "Message not found",
+				Details: err.Error(),
+			})
+		case errors.Is(err, services.ErrNotChannelMember):
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "Forbidden",
+				Details: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to get thread",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, thread)
+}
+
+// ForwardMessage godoc
+// @Summary Forward a message to another channel
+// @Description Copy a message from this channel into another channel the user is a member of, keeping a reference to the original message
+// @Tags chats
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Source channel ID"
+// @Param request body models.ForwardMessageRequest true "Message and target channel"
+// @Success 200 {object} models.ChatResponse "Forwarded message"
+// @Failure 400 {object} models.ErrorResponse "Bad request - invalid input data"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - not a member of both channels or blocked by the recipient"
+// @Failure 404 {object} models.ErrorResponse "Message not found"
+// @Failure 409 {object} models.ErrorResponse "Target channel is archived"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /channels/{id}/forward [post]
+func (h *ChatHandler) ForwardMessage(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	sourceChannelID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid channel ID"})
+		return
+	}
+
+	var req models.ForwardMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, newBindingErrorResponse("Invalid input data", err))
+		return
+	}
+
+	chat, err := h.chatService.ForwardMessage(userID, uint(sourceChannelID), req.MessageID, req.TargetChannelID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotChannelMember), errors.Is(err, services.ErrSenderBlocked):
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "Forbidden",
+				Details: err.Error(),
+			})
+		case errors.Is(err, services.ErrMessageNotFound):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "Message not found",
+				Details: err.Error(),
+			})
+		case errors.Is(err, services.ErrChannelArchived):
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: "Channel is archived",
+				Details: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to forward message",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	// Deliver the forwarded copy to clients connected to the target channel
+	h.hub.DeliverChannelMessage(strconv.FormatUint(uint64(userID), 10), chat)
+
+	c.JSON(http.StatusOK, models.ChatResponse{
+		ID:            chat.ID,
+		UUID:          chat.UUID,
+		Type:          chat.GetType(),
+		SenderID:      chat.SenderID,
+		SenderName:    chat.Sender.Username,
+		SenderAvatar:  chat.Sender.Avatar,
+		Text:          chat.Text,
+		URL:           chat.URL,
+		FileName:      chat.FileName,
+		CreatedAt:     chat.CreatedAt,
+		ChannelID:     &chat.ChannelID,
+		ForwardedFrom: chat.ForwardedFrom,
+	})
+}
+
+// SearchMessages godoc
+// @Summary Search messages
+// @Description Full-text search over messages in the current user's channels. Each result carries a snippet with matched terms wrapped in <mark></mark>.
+// @Tags chats
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Search query"
+// @Param channelId query int false "Restrict the search to one channel"
+// @Param limit query int false "Maximum results (default 20, max 100)"
+// @Success 200 {array} models.MessageSearchResult "Matching messages"
+// @Failure 400 {object} models.ErrorResponse "Bad request - missing query or invalid channel ID"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - not a member of the channel"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /messages/search [get]
+func (h *ChatHandler) SearchMessages(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Search query is required",
+		})
+		return
+	}
+
+	var channelID *uint
+	if ch := c.Query("channelId"); ch != "" {
+		parsed, err := strconv.ParseUint(ch, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid channel ID",
+				Details: err.Error(),
+			})
+			return
+		}
+		id := uint(parsed)
+		channelID = &id
+	}
+
+	limit := 20
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil {
+			limit = parsed
+		}
+	}
+
+	results, err := h.chatService.SearchMessages(userID, query, channelID, limit)
+	if err != nil {
+		if errors.Is(err, services.ErrNotChannelMember) {
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "Forbidden",
+				Details: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to search messages",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	if results == nil {
+		results = []models.MessageSearchResult{}
+	}
+	c.JSON(http.StatusOK, results)
+}
+
+// AddReaction godoc
+// @Summary Add a reaction to a message
+// @Description Add the current user's emoji reaction to a message. Adding the same reaction twice is a no-op. Changes are broadcast to the channel over WebSocket.
+// @Tags chats
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Message ID"
+// @Param request body models.ReactionRequest true "Reaction emoji"
+// @Success 200 {object} models.ReactionEvent "Reaction state after the change"
+// @Failure 400 {object} models.ErrorResponse "Bad request - invalid input data"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - not a member of the channel"
+// @Failure 404 {object} models.ErrorResponse "Message not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /messages/{id}/reactions [post]
+func (h *ChatHandler) AddReaction(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	messageID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid message ID"})
+		return
+	}
+
+	var req models.ReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, newBindingErrorResponse("Invalid input data", err))
+		return
+	}
+
+	event, err := h.chatService.AddReaction(userID, uint(messageID), req.Emoji)
+	h.respondReaction(c, userID, event, err)
+}
+
+// RemoveReaction godoc
+// @Summary Remove a reaction from a message
+// @Description Remove the current user's emoji reaction from a message. Removing a missing reaction is a no-op. Changes are broadcast to the channel over WebSocket.
+// @Tags chats
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Message ID"
+// @Param emoji path string true "Reaction emoji (URL encoded)"
+// @Success 200 {object} models.ReactionEvent "Reaction state after the change"
+// @Failure 400 {object} models.ErrorResponse "Bad request - invalid input data"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - not a member of the channel"
+// @Failure 404 {object} models.ErrorResponse "Message not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /messages/{id}/reactions/{emoji} [delete]
+func (h *ChatHandler) RemoveReaction(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	messageID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid message ID"})
+		return
+	}
+
+	event, err := h.chatService.RemoveReaction(userID, uint(messageID), c.Param("emoji"))
+	h.respondReaction(c, userID, event, err)
+}
+
+// EditMessage godoc
+// @Summary Edit a message
+// @Description Replace the text of a message. Only the original sender may edit. The edit is broadcast to the channel over WebSocket so open clients update in place.
+// @Tags chats
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Message ID"
+// @Param request body models.EditMessageRequest true "New text"
+// @Success 200 {object} models.ChatResponse "Edited message"
+// @Failure 400 {object} models.ErrorResponse "Bad request - invalid input data"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - not the sender"
+// @Failure 404 {object} models.ErrorResponse "Message not found or deleted"
+// @Failure 409 {object} models.ErrorResponse "Channel is archived"
+// @Failure 413 {object} models.ErrorResponse "Message text exceeds the size limit"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /messages/{id} [put]
+func (h *ChatHandler) EditMessage(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	messageID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid message ID"})
+		return
+	}
+
+	var req models.EditMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, newBindingErrorResponse("Invalid input data", err))
+		return
+	}
+
+	chat, err := h.chatService.EditMessage(userID, uint(messageID), req.Text)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrMessageTooLarge):
+			c.JSON(http.StatusRequestEntityTooLarge, models.ErrorResponse{
+				Code:    http.StatusRequestEntityTooLarge,
+				Message: "Message too large",
+				Details: err.Error(),
+			})
+		case errors.Is(err, services.ErrMessageNotFound):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "Message not found",
+				Details: err.Error(),
+			})
+		case errors.Is(err, services.ErrNotMessageSender):
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "Forbidden",
+				Details: err.Error(),
+			})
+		case errors.Is(err, services.ErrChannelArchived):
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: "Channel is archived",
+				Details: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to edit message",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	if chat.ChannelID != 0 {
+		channelID := strconv.FormatUint(uint64(chat.ChannelID), 10)
+		senderID := strconv.FormatUint(uint64(userID), 10)
+		h.hub.BroadcastToChannel(channelID, websocket.NewMessageEditMessage(uuid.New().String(), senderID, chat))
+	}
+
+	c.JSON(http.StatusOK, models.ChatResponse{
+		ID:            chat.ID,
+		UUID:          chat.UUID,
+		Type:          chat.GetType(),
+		SenderID:      chat.SenderID,
+		SenderName:    chat.Sender.Username,
+		SenderAvatar:  chat.Sender.Avatar,
+		Text:          chat.Text,
+		URL:           chat.URL,
+		FileName:      chat.FileName,
+		CreatedAt:     chat.CreatedAt,
+		ChannelID:     &chat.ChannelID,
+		ForwardedFrom: chat.ForwardedFrom,
+		EditedAt:      chat.EditedAt,
+	})
+}
+
+// DeleteMessage godoc
+// @Summary Delete a message
+// @Description Soft-delete a message. The sender or a channel owner or admin may delete it. A tombstone event is broadcast to the channel and history shows a placeholder instead of the content. Deleting an already deleted message is a no-op.
+// @Tags chats
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Message ID"
+// @Success 200 {object} map[string]string "Message deleted"
+// @Failure 400 {object} models.ErrorResponse "Bad request - invalid message ID"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - not the sender or a channel owner or admin"
+// @Failure 404 {object} models.ErrorResponse "Message not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /messages/{id} [delete]
+func (h *ChatHandler) DeleteMessage(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	messageID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid message ID"})
+		return
+	}
+
+	chat, deleted, err := h.chatService.DeleteMessage(userID, uint(messageID))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrMessageNotFound):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "Message not found",
+				Details: err.Error(),
+			})
+		case errors.Is(err, services.ErrCannotDelete):
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "Forbidden",
+				Details: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to delete message",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	if deleted && chat.ChannelID != 0 {
+		channelID := strconv.FormatUint(uint64(chat.ChannelID), 10)
+		senderID := strconv.FormatUint(uint64(userID), 10)
+		h.hub.BroadcastToChannel(channelID, websocket.NewMessageDeleteMessage(uuid.New().String(), senderID, chat))
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Message deleted"})
+}
+
+// PinMessage godoc
+// @Summary Pin a message
+// @Description Pin a message in its channel. Only channel admins and owners may pin, and a channel holds at most 50 pins. A pin event is broadcast to the channel. Pinning an already pinned message is a no-op.
+// @Tags chats
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Channel ID"
+// @Param messageId path int true "Message ID"
+// @Success 200 {object} models.PinEvent "Pin result"
+// @Failure 400 {object} models.ErrorResponse "Bad request - invalid channel or message ID"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - not a channel owner or admin"
+// @Failure 404 {object} models.ErrorResponse "Channel or message not found"
+// @Failure 409 {object} models.ErrorResponse "Channel has reached its pin limit"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /channels/{id}/messages/{messageId}/pin [post]
+func (h *ChatHandler) PinMessage(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	channelID, messageID, ok := parsePinParams(c)
+	if !ok {
+		return
+	}
+	event, err := h.chatService.PinMessage(userID, channelID, messageID)
+	h.respondPin(c, userID, event, err)
+}
+
+// UnpinMessage godoc
+// @Summary Unpin a message
+// @Description Remove a message's pin. Only channel admins and owners may unpin. An unpin event is broadcast to the channel. Unpinning a message that is not pinned is a no-op.
+// @Tags chats
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Channel ID"
+// @Param messageId path int true "Message ID"
+// @Success 200 {object} models.PinEvent "Unpin result"
+// @Failure 400 {object} models.ErrorResponse "Bad request - invalid channel or message ID"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - not a channel owner or admin"
+// @Failure 404 {object} models.ErrorResponse "Channel not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /channels/{id}/messages/{messageId}/pin [delete]
+func (h *ChatHandler) UnpinMessage(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	channelID, messageID, ok := parsePinParams(c)
+	if !ok {
+		return
+	}
+	event, err := h.chatService.UnpinMessage(userID, channelID, messageID)
+	h.respondPin(c, userID, event, err)
+}
+
+// parsePinParams reads the channel and message IDs of a pin route
+func parsePinParams(c *gin.Context) (channelID, messageID uint, ok bool) {
+	cid, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid channel ID",
+			Details: err.Error(),
+		})
+		return 0, 0, false
+	}
+	mid, err := strconv.ParseUint(c.Param("messageId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid message ID",
+			Details: err.Error(),
+		})
+		return 0, 0, false
+	}
+	return uint(cid), uint(mid), true
+}
+
+// respondPin maps pin errors to HTTP responses and broadcasts real changes
+func (h *ChatHandler) respondPin(c *gin.Context, userID uint, event *models.PinEvent, err error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInsufficientRole):
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "Forbidden",
+				Details: err.Error(),
+			})
+		case errors.Is(err, services.ErrChannelNotFound):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "Channel not found",
+				Details: err.Error(),
+			})
+		case errors.Is(err, services.ErrMessageNotFound):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "Message not found",
+				Details: err.Error(),
+			})
+		case errors.Is(err, services.ErrPinLimitReached):
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: "Pin limit reached",
+				Details: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to update pin",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	if event.Changed {
+		channelID := strconv.FormatUint(uint64(event.ChannelID), 10)
+		senderID := strconv.FormatUint(uint64(userID), 10)
+		h.hub.BroadcastToChannel(channelID, websocket.NewPinMessage(uuid.New().String(), senderID, event))
+	}
+	c.JSON(http.StatusOK, event)
+}
+
+// ListPins godoc
+// @Summary List pinned messages
+// @Description List the channel's pinned messages, most recently pinned first
+// @Tags chats
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Channel ID"
+// @Success 200 {array} models.PinnedMessageResponse "Pinned messages"
+// @Failure 400 {object} models.ErrorResponse "Bad request - invalid channel ID"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - not a member of the channel"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /channels/{id}/pins [get]
+func (h *ChatHandler) ListPins(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	channelID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid channel ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	pins, err := h.chatService.ListPins(userID, uint(channelID))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotChannelMember):
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "Forbidden",
+				Details: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to list pinned messages",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, pins)
+}
+
+// respondReaction maps reaction errors to HTTP responses and broadcasts real changes
+func (h *ChatHandler) respondReaction(c *gin.Context, userID uint, event *models.ReactionEvent, err error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidEmoji):
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid input data",
+				Details: err.Error(),
+			})
+		case errors.Is(err, services.ErrNotChannelMember):
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "Forbidden",
+				Details: err.Error(),
+			})
+		case errors.Is(err, services.ErrMessageNotFound):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "Message not found",
+				Details: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to update reaction",
+				Details: err.Error(),
+			})
+		}
+		return
+	}
+
+	if event.Changed {
+		channelID := strconv.FormatUint(uint64(event.ChannelID), 10)
+		senderID := strconv.FormatUint(uint64(userID), 10)
+		h.hub.BroadcastToChannel(channelID, websocket.NewReactionMessage(uuid.New().String(), senderID, event))
+	}
+	c.JSON(http.StatusOK, event)
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"chat-service/internal/models"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// createTestMessage stores a text message from sender in a new group channel
+// owned by the sender
+func createTestMessage(t *testing.T, db *gorm.DB, sender uint, text string) (*models.Channel, *models.Chat) {
+	t.Helper()
+	channel := &models.Channel{Name: "handlers", OwnerID: sender, Type: models.ChannelTypeGroup}
+	if err := db.Create(channel).Error; err != nil {
+		t.Fatalf("create channel: %v", err)
+	}
+	if err := db.Create(&models.ChannelMember{ChannelID: channel.ID, UserID: sender}).Error; err != nil {
+		t.Fatalf("add member: %v", err)
+	}
+	chat := &models.Chat{SenderID: sender, ChannelID: channel.ID, Text: &text}
+	if err := db.Create(chat).Error; err != nil {
+		t.Fatalf("create message: %v", err)
+	}
+	return channel, chat
+}
+
+func TestEditMessage(t *testing.T) {
+	tests := []struct {
+		name       string
+		asOther    bool
+		prepare    func(t *testing.T, db *gorm.DB, channel *models.Channel, chat *models.Chat)
+		wantStatus int
+	}{
+		{"sender edits", false, nil, http.StatusOK},
+		{"other user is forbidden", true, nil, http.StatusForbidden},
+		{"deleted message", false, func(t *testing.T, db *gorm.DB, _ *models.Channel, chat *models.Chat) {
+			if err := db.Delete(chat).Error; err != nil {
+				t.Fatalf("delete message: %v", err)
+			}
+		}, http.StatusNotFound},
+		{"archived channel", false, func(t *testing.T, db *gorm.DB, channel *models.Channel, _ *models.Chat) {
+			if err := db.Model(channel).Update("archived", true).Error; err != nil {
+				t.Fatalf("archive channel: %v", err)
+			}
+		}, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			handler := newTestChatHandler(db)
+			sender := createTestUser(t, db)
+			channel, chat := createTestMessage(t, db, sender.ID, "before")
+			if tt.prepare != nil {
+				tt.prepare(t, db, channel, chat)
+			}
+			userID := sender.ID
+			if tt.asOther {
+				userID = createTestUser(t, db).ID
+			}
+
+			rec := serveAs(t, userID, http.MethodPut, "/messages/:id", fmt.Sprintf("/messages/%d", chat.ID),
+				models.EditMessageRequest{Text: "after"}, handler.EditMessage)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var resp models.ChatResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if resp.Text == nil || *resp.Text != "after" || resp.EditedAt == nil {
+				t.Fatalf("response = %+v, want edited text", resp)
+			}
+		})
+	}
+}
+
+func TestGetChannelHistoryPages(t *testing.T) {
+	db := newTestDB(t)
+	handler := newTestChatHandler(db)
+	sender := createTestUser(t, db)
+	channel, first := createTestMessage(t, db, sender.ID, "message 1")
+	ids := []uint{first.ID}
+	for i := 2; i <= 5; i++ {
+		text := fmt.Sprintf("message %d", i)
+		chat := &models.Chat{SenderID: sender.ID, ChannelID: channel.ID, Text: &text}
+		if err := db.Create(chat).Error; err != nil {
+			t.Fatalf("create message: %v", err)
+		}
+		ids = append(ids, chat.ID)
+	}
+	// Deleted messages keep their place in the history as redacted entries
+	if err := db.Delete(&models.Chat{}, ids[2]).Error; err != nil {
+		t.Fatalf("delete message: %v", err)
+	}
+
+	getPage := func(query string) models.ChatHistoryPage {
+		t.Helper()
+		rec := serveAs(t, sender.ID, http.MethodGet, "/channels/:id/messages",
+			fmt.Sprintf("/channels/%d/messages?%s", channel.ID, query), nil, handler.GetChannelHistory)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s: status = %d: %s", query, rec.Code, rec.Body.String())
+		}
+		var page models.ChatHistoryPage
+		if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+			t.Fatalf("decode page: %v", err)
+		}
+		return page
+	}
+	checkPage := func(page models.ChatHistoryPage, wantIDs []uint, wantCursor *uint) {
+		t.Helper()
+		got := make([]uint, len(page.Items))
+		for i, item := range page.Items {
+			got[i] = item.ID
+		}
+		if fmt.Sprint(got) != fmt.Sprint(wantIDs) {
+			t.Fatalf("page items = %v, want %v", got, wantIDs)
+		}
+		switch {
+		case wantCursor == nil && page.NextCursor != nil:
+			t.Fatalf("nextCursor = %d, want null", *page.NextCursor)
+		case wantCursor != nil && (page.NextCursor == nil || *page.NextCursor != *wantCursor):
+			t.Fatalf("nextCursor = %v, want %d", page.NextCursor, *wantCursor)
+		}
+	}
+
+	firstPage := getPage("limit=2")
+	checkPage(firstPage, []uint{ids[4], ids[3]}, &ids[3])
+
+	middle := getPage(fmt.Sprintf("limit=2&before=%d", *firstPage.NextCursor))
+	checkPage(middle, []uint{ids[2], ids[1]}, &ids[1])
+	if deleted := middle.Items[0]; !deleted.Deleted || deleted.Text != nil {
+		t.Fatalf("deleted message returned as %+v, want a redacted entry", deleted)
+	}
+
+	last := getPage(fmt.Sprintf("limit=2&before=%d", *middle.NextCursor))
+	checkPage(last, []uint{ids[0]}, nil)
+
+	empty := getPage(fmt.Sprintf("limit=2&before=%d", ids[0]))
+	checkPage(empty, []uint{}, nil)
+}
+
+func TestGetChannelHistoryRejects(t *testing.T) {
+	db := newTestDB(t)
+	handler := newTestChatHandler(db)
+	sender := createTestUser(t, db)
+	channel, _ := createTestMessage(t, db, sender.ID, "hello")
+	_, otherChat := createTestMessage(t, db, sender.ID, "elsewhere")
+
+	tests := []struct {
+		name       string
+		userID     uint
+		query      string
+		wantStatus int
+	}{
+		{"non-member", createTestUser(t, db).ID, "", http.StatusForbidden},
+		{"malformed cursor", sender.ID, "before=abc", http.StatusBadRequest},
+		{"cursor from another channel", sender.ID, fmt.Sprintf("before=%d", otherChat.ID), http.StatusBadRequest},
+		{"unknown cursor", sender.ID, "before=4294967295", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveAs(t, tt.userID, http.MethodGet, "/channels/:id/messages",
+				fmt.Sprintf("/channels/%d/messages?%s", channel.ID, tt.query), nil, handler.GetChannelHistory)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestEditMessageSizeBoundary(t *testing.T) {
+	tests := []struct {
+		name       string
+		size       int
+		wantStatus int
+	}{
+		{"at the limit", 4096, http.StatusOK},
+		{"over the limit", 4097, http.StatusRequestEntityTooLarge},
+	}
+	db := newTestDB(t)
+	handler := newTestChatHandler(db)
+	sender := createTestUser(t, db)
+	_, chat := createTestMessage(t, db, sender.ID, "before")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveAs(t, sender.ID, http.MethodPut, "/messages/:id", fmt.Sprintf("/messages/%d", chat.ID),
+				models.EditMessageRequest{Text: strings.Repeat("a", tt.size)}, handler.EditMessage)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+}
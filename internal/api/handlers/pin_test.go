@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"chat-service/internal/models"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// pinFixture is a channel with one message, its owner, an admin, a plain member
+// and a user outside the channel
+type pinFixture struct {
+	db      *gorm.DB
+	handler *ChatHandler
+	channel *models.Channel
+	chat    *models.Chat
+
+	owner, admin, member, outsider uint
+}
+
+func newPinFixture(t *testing.T) *pinFixture {
+	t.Helper()
+	db := newTestDB(t)
+	f := &pinFixture{db: db, handler: newTestChatHandler(db)}
+	f.owner = createTestUser(t, db).ID
+	f.admin = createTestUser(t, db).ID
+	f.member = createTestUser(t, db).ID
+	f.outsider = createTestUser(t, db).ID
+	f.channel, f.chat = createTestMessage(t, db, f.owner, "pin me")
+	for id, role := range map[uint]string{f.admin: models.ChannelRoleAdmin, f.member: models.ChannelRoleMember} {
+		if err := db.Create(&models.ChannelMember{ChannelID: f.channel.ID, UserID: id, Role: role}).Error; err != nil {
+			t.Fatalf("add member: %v", err)
+		}
+	}
+	return f
+}
+
+// addMessage stores another message from the owner in the fixture's channel
+func (f *pinFixture) addMessage(t *testing.T, text string) *models.Chat {
+	t.Helper()
+	chat := &models.Chat{SenderID: f.owner, ChannelID: f.channel.ID, Text: &text}
+	if err := f.db.Create(chat).Error; err != nil {
+		t.Fatalf("create message: %v", err)
+	}
+	return chat
+}
+
+// pin pins or, with method DELETE, unpins a message as the user
+func (f *pinFixture) pin(t *testing.T, method string, userID, messageID uint) (int, models.PinEvent) {
+	t.Helper()
+	handler := f.handler.PinMessage
+	if method == http.MethodDelete {
+		handler = f.handler.UnpinMessage
+	}
+	rec := serveAs(t, userID, method, "/channels/:id/messages/:messageId/pin",
+		fmt.Sprintf("/channels/%d/messages/%d/pin", f.channel.ID, messageID), nil, handler)
+	var event models.PinEvent
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &event); err != nil {
+			t.Fatalf("decode pin event: %v", err)
+		}
+	}
+	return rec.Code, event
+}
+
+func (f *pinFixture) listPins(t *testing.T, userID uint) (int, []models.PinnedMessageResponse) {
+	t.Helper()
+	rec := serveAs(t, userID, http.MethodGet, "/channels/:id/pins",
+		fmt.Sprintf("/channels/%d/pins", f.channel.ID), nil, f.handler.ListPins)
+	var pins []models.PinnedMessageResponse
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &pins); err != nil {
+			t.Fatalf("decode pins: %v", err)
+		}
+	}
+	return rec.Code, pins
+}
+
+func TestPinAndUnpin(t *testing.T) {
+	f := newPinFixture(t)
+	second := f.addMessage(t, "pin me too")
+
+	if code, event := f.pin(t, http.MethodPost, f.owner, f.chat.ID); code != http.StatusOK || !event.Changed {
+		t.Fatalf("owner pins: status %d changed %v", code, event.Changed)
+	}
+	if code, event := f.pin(t, http.MethodPost, f.owner, f.chat.ID); code != http.StatusOK || event.Changed {
+		t.Fatalf("pin again: status %d changed %v, want 200 unchanged", code, event.Changed)
+	}
+	if code, event := f.pin(t, http.MethodPost, f.admin, second.ID); code != http.StatusOK || !event.Changed {
+		t.Fatalf("admin pins: status %d changed %v", code, event.Changed)
+	}
+
+	code, pins := f.listPins(t, f.member)
+	if code != http.StatusOK {
+		t.Fatalf("member lists pins: status %d", code)
+	}
+	if len(pins) != 2 || pins[0].ID != second.ID || pins[1].ID != f.chat.ID {
+		t.Fatalf("pins = %+v, want messages %d then %d", pins, second.ID, f.chat.ID)
+	}
+	if pins[0].PinnedBy != f.admin {
+		t.Fatalf("pinnedBy = %d, want %d", pins[0].PinnedBy, f.admin)
+	}
+
+	if code, event := f.pin(t, http.MethodDelete, f.admin, f.chat.ID); code != http.StatusOK || !event.Changed {
+		t.Fatalf("admin unpins: status %d changed %v", code, event.Changed)
+	}
+	if code, event := f.pin(t, http.MethodDelete, f.admin, f.chat.ID); code != http.StatusOK || event.Changed {
+		t.Fatalf("unpin again: status %d changed %v, want 200 unchanged", code, event.Changed)
+	}
+	if _, pins := f.listPins(t, f.owner); len(pins) != 1 || pins[0].ID != second.ID {
+		t.Fatalf("pins after unpin = %+v, want only message %d", pins, second.ID)
+	}
+}
+
+func TestPinPermissions(t *testing.T) {
+	f := newPinFixture(t)
+	_, otherChat := createTestMessage(t, f.db, f.owner, "elsewhere")
+
+	tests := []struct {
+		name       string
+		method     string
+		userID     func(f *pinFixture) uint
+		messageID  uint
+		wantStatus int
+	}{
+		{"member cannot pin", http.MethodPost, func(f *pinFixture) uint { return f.member }, f.chat.ID, http.StatusForbidden},
+		{"outsider cannot pin", http.MethodPost, func(f *pinFixture) uint { return f.outsider }, f.chat.ID, http.StatusForbidden},
+		{"member cannot unpin", http.MethodDelete, func(f *pinFixture) uint { return f.member }, f.chat.ID, http.StatusForbidden},
+		{"message from another channel", http.MethodPost, func(f *pinFixture) uint { return f.owner }, otherChat.ID, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code, _ := f.pin(t, tt.method, tt.userID(f), tt.messageID); code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", code, tt.wantStatus)
+			}
+		})
+	}
+
+	if code, _ := f.listPins(t, f.outsider); code != http.StatusForbidden {
+		t.Fatalf("outsider lists pins: status %d, want %d", code, http.StatusForbidden)
+	}
+}
+
+func TestPinLimit(t *testing.T) {
+	f := newPinFixture(t)
+	for i := 0; i < models.MaxPinsPerChannel; i++ {
+		chat := f.addMessage(t, fmt.Sprintf("pinned %d", i))
+		if code, _ := f.pin(t, http.MethodPost, f.owner, chat.ID); code != http.StatusOK {
+			t.Fatalf("pin %d within the limit: status %d", i+1, code)
+		}
+	}
+
+	if code, _ := f.pin(t, http.MethodPost, f.owner, f.chat.ID); code != http.StatusConflict {
+		t.Fatalf("pin past the limit: status %d, want %d", code, http.StatusConflict)
+	}
+	// Re-pinning a pinned message does not count against the limit
+	if _, pins := f.listPins(t, f.owner); len(pins) != models.MaxPinsPerChannel {
+		t.Fatalf("%d pins, want %d", len(pins), models.MaxPinsPerChannel)
+	} else if code, event := f.pin(t, http.MethodPost, f.owner, pins[0].ID); code != http.StatusOK || event.Changed {
+		t.Fatalf("re-pin at the limit: status %d changed %v, want 200 unchanged", code, event.Changed)
+	}
+}
@@ -4,18 +4,21 @@ import (
 	"chat-service/internal/models"
 	"chat-service/internal/services"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
 )
 
 type UserHandler struct {
-	userService *services.UserService
-	redisClient *redis.Client
+	userService  *services.UserService
+	messageQuota *services.MessageQuota
+	redisClient  *redis.Client
 }
 
-func NewUserHandler(userService *services.UserService, redisClient *redis.Client) *UserHandler {
-	return &UserHandler{userService: userService, redisClient: redisClient}
+func NewUserHandler(userService *services.UserService, messageQuota *services.MessageQuota, redisClient *redis.Client) *UserHandler {
+	return &UserHandler{userService: userService, messageQuota: messageQuota, redisClient: redisClient}
 }
 
 // GetProfile godoc
@@ -62,6 +65,32 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 	c.JSON(http.StatusOK, profile)
 }
 
+// GetQuota godoc
+// @Summary Get daily message quota usage
+// @Description Get how many messages the current user has sent today against the daily quota. The quota resets at midnight UTC; a limit of 0 means no quota is configured.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.MessageQuotaUsage "Quota usage retrieved successfully"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /users/me/quota [get]
+func (h *UserHandler) GetQuota(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	usage, err := h.messageQuota.Usage(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get message quota",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
+
 // UpdateProfile godoc
 // @Summary Update user profile
 // @Description Update the current user's profile information (username, avatar, password)
@@ -98,11 +127,7 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 
 	var req models.UpdateProfileRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "Invalid request body",
-			Details: err.Error(),
-		})
+		c.JSON(http.StatusBadRequest, newBindingErrorResponse("Invalid request body", err))
 		return
 	}
 
@@ -137,6 +162,64 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	c.JSON(http.StatusOK, updatedProfile)
 }
 
+// ListUsers godoc
+// @Summary List and search users
+// @Description Page through the user directory. q matches usernames and emails case-insensitively, prefix matches first. Users you have blocked are not listed.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param q query string false "Text to match against username or email"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Number of users to skip"
+// @Success 200 {object} models.UserDirectoryPage "Page of users"
+// @Failure 400 {object} models.ErrorResponse "Bad request - invalid limit or offset"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /users [get]
+func (h *UserHandler) ListUsers(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	limit, ok := nonNegativeQuery(c, "limit")
+	if !ok {
+		return
+	}
+	offset, ok := nonNegativeQuery(c, "offset")
+	if !ok {
+		return
+	}
+
+	page, err := h.userService.SearchUsers(c.Request.Context(), userID, strings.TrimSpace(c.Query("q")), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to list users",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// nonNegativeQuery parses an optional non-negative integer query parameter,
+// answering 400 when it is malformed. A missing parameter is 0.
+func nonNegativeQuery(c *gin.Context, name string) (int, bool) {
+	v := c.Query(name)
+	if v == "" {
+		return 0, true
+	}
+	parsed, err := strconv.Atoi(v)
+	if err != nil || parsed < 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid " + name,
+			Details: name + " must be a non-negative integer",
+		})
+		return 0, false
+	}
+	return parsed, true
+}
+
 // SearchUsersByUsername godoc
 // @Summary Search users by username
 // @Description Search for users by username (partial match for channel creation)
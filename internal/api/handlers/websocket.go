@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"chat-service/internal/models"
+	"chat-service/internal/services"
 	"chat-service/internal/websocket"
 	"log/slog"
 	"net/http"
@@ -11,11 +13,13 @@ import (
 )
 
 type WSHandler struct {
-	hub *websocket.Hub
+	hub            *websocket.Hub
+	userService    *services.UserService
+	channelService *services.ChannelService
 }
 
-func NewWSHandler(hub *websocket.Hub) *WSHandler {
-	return &WSHandler{hub: hub}
+func NewWSHandler(hub *websocket.Hub, userService *services.UserService, channelService *services.ChannelService) *WSHandler {
+	return &WSHandler{hub: hub, userService: userService, channelService: channelService}
 }
 
 // validateUserID validates and sanitizes the user ID parameter
@@ -82,5 +86,303 @@ func (h *WSHandler) HandleWebSocket(c *gin.Context) {
 		return
 	}
 
+	if !h.hub.AcceptingConnections() {
+		slog.Warn("WebSocket connection shed: hub unhealthy",
+			"userID", validatedUserID,
+			"clientIP", clientIP)
+		c.Header("Retry-After", "30")
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Service temporarily unavailable, retry later"})
+		return
+	}
+
 	websocket.ServeWS(h.hub, c.Writer, c.Request, validatedUserID)
 }
+
+// GetConnectionState godoc
+// @Summary Get a user's WebSocket connection state
+// @Description Admin-only diagnostics: local connection metadata for the user and whether any instance reports them online
+// @Tags websocket
+// @Produce json
+// @Security BearerAuth
+// @Param userId path int true "User ID"
+// @Success 200 {object} websocket.ConnectionState "Connection state"
+// @Failure 400 {object} models.ErrorResponse "Bad request - invalid user ID"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - admin privileges required"
+// @Router /ws/connections/{userId} [get]
+func (h *WSHandler) GetConnectionState(c *gin.Context) {
+	userID, err := h.validateUserID(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid user ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.hub.GetConnectionState(c.Request.Context(), userID))
+}
+
+// GetChannelSizes godoc
+// @Summary List channel fan-out sizes
+// @Description Admin-only diagnostics: how many clients on this instance joined each channel, largest first
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} websocket.ChannelSize "Channel sizes"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - admin privileges required"
+// @Router /admin/channels/sizes [get]
+func (h *WSHandler) GetChannelSizes(c *gin.Context) {
+	c.JSON(http.StatusOK, h.hub.ChannelSizes())
+}
+
+// ListConnections godoc
+// @Summary List WebSocket connections
+// @Description Admin-only diagnostics: a snapshot of every client connected to this instance, ordered by user ID, for tracking down ghost connections
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param channel query string false "Only clients that joined this channel"
+// @Success 200 {array} websocket.ConnectionMetadata "Connections"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - admin privileges required"
+// @Router /admin/ws/connections [get]
+func (h *WSHandler) ListConnections(c *gin.Context) {
+	c.JSON(http.StatusOK, h.hub.ListConnections(c.Query("channel")))
+}
+
+// GetErrorHistory godoc
+// @Summary List recent hub errors
+// @Description Admin-only diagnostics: the latest hub errors on this instance, newest first, with all-time totals and per-minute rates by type
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param type query string false "Only errors of this type, e.g. persist or redis"
+// @Param severity query string false "Only errors of this severity (warning or error)"
+// @Param limit query int false "Maximum errors to return (default 100, max 500)"
+// @Success 200 {object} websocket.ErrorHistory "Error history"
+// @Failure 400 {object} models.ErrorResponse "Bad request - invalid severity or limit"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - admin privileges required"
+// @Router /admin/ws/errors [get]
+func (h *WSHandler) GetErrorHistory(c *gin.Context) {
+	severity := c.Query("severity")
+	if severity != "" && severity != websocket.SeverityWarning && severity != websocket.SeverityError {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid severity",
+			Details: "severity must be warning or error",
+		})
+		return
+	}
+
+	limit := 100
+	if l := c.Query("limit"); l != "" {
+		parsed, err := strconv.Atoi(l)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid limit",
+				Details: "limit must be a positive integer",
+			})
+			return
+		}
+		limit = min(parsed, 500)
+	}
+
+	c.JSON(http.StatusOK, h.hub.ErrorHistory(c.Query("type"), severity, limit))
+}
+
+// GetUserPresence godoc
+// @Summary Get a user's presence
+// @Description Whether the user is online on any instance, with last activity and joined channels when known. Offline users include lastSeen, when they were last connected. Unknown users are reported offline.
+// @Tags websocket
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} websocket.UserPresence "User presence"
+// @Failure 400 {object} models.ErrorResponse "Bad request - invalid user ID"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Router /users/{id}/presence [get]
+func (h *WSHandler) GetUserPresence(c *gin.Context) {
+	userID, err := h.validateUserID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid user ID",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.hub.GetUserPresence(c.Request.Context(), userID))
+}
+
+// GetChannelPresence godoc
+// @Summary Get a channel's online members
+// @Description IDs of the channel's members who are online on any instance
+// @Tags websocket
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Channel ID"
+// @Success 200 {object} websocket.ChannelPresence "Online members"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - not a channel member"
+// @Failure 404 {object} models.ErrorResponse "Channel not found"
+// @Router /channels/{id}/presence [get]
+func (h *WSHandler) GetChannelPresence(c *gin.Context) {
+	callerID := c.MustGet("user_id").(uint)
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+
+	channel, err := h.channelService.GetChannelByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "Channel not found",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	isMember := false
+	memberIDs := make([]string, 0, len(channel.Members))
+	for _, m := range channel.Members {
+		if m == nil {
+			continue
+		}
+		if m.ID == callerID {
+			isMember = true
+		}
+		memberIDs = append(memberIDs, strconv.FormatUint(uint64(m.ID), 10))
+	}
+	if !isMember {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Code:    http.StatusForbidden,
+			Message: "Forbidden",
+			Details: services.ErrNotChannelMember.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.hub.GetChannelPresence(c.Request.Context(), c.Param("id"), memberIDs))
+}
+
+// ForceDisconnect godoc
+// @Summary Forcibly disconnect a user's WebSocket connections
+// @Description Closes the user's connections on every instance with a connection.force_logout frame carrying the reason (e.g. after a password change or ban; moderators use the same frame, there is no separate disconnected frame) and revokes all of the user's refresh tokens, so no session can be renewed. Allowed for admins and for the user themselves.
+// @Tags websocket
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param request body models.ForceDisconnectRequest false "Disconnect reason"
+// @Success 200 {object} models.ForceDisconnectResponse "Disconnect issued"
+// @Failure 400 {object} models.ErrorResponse "Bad request - invalid user ID"
+// @Failure 401 {object} models.ErrorResponse "Unauthorized - invalid or missing token"
+// @Failure 403 {object} models.ErrorResponse "Forbidden - not allowed to disconnect this user"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /users/{id}/disconnect [post]
+// @Router /admin/users/{id}/disconnect [post]
+func (h *WSHandler) ForceDisconnect(c *gin.Context) {
+	targetID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || targetID == 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	var req models.ForceDisconnectRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, newBindingErrorResponse("Invalid request body", err))
+			return
+		}
+	}
+
+	callerID := c.MustGet("user_id").(uint)
+	if uint(targetID) != callerID {
+		isAdmin, err := h.userService.IsAdmin(callerID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to verify permissions",
+			})
+			return
+		}
+		if !isAdmin {
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "Not allowed to disconnect this user",
+			})
+			return
+		}
+	}
+
+	reason := req.Reason
+	if reason == "" {
+		reason = "session revoked"
+	}
+
+	// Revoke sessions first so a disconnected client cannot refresh and reconnect
+	if err := h.userService.RevokeAllSessions(uint(targetID)); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to revoke sessions",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	userID := strconv.FormatUint(targetID, 10)
+	closed := h.hub.ForceLogout(userID, reason)
+	slog.Info("Forced logout issued", "targetUserID", userID, "byUserID", callerID, "reason", reason)
+
+	c.JSON(http.StatusOK, models.ForceDisconnectResponse{
+		UserID:        uint(targetID),
+		ClosedLocally: closed,
+	})
+}
+
+// GetProtocol godoc
+// @Summary Describe the WebSocket protocol
+// @Description Machine-readable list of the actions clients may send and the events the server emits, generated from the hub's action registry
+// @Tags websocket
+// @Produce json
+// @Success 200 {object} websocket.ProtocolDescription "Protocol description"
+// @Router /ws/protocol [get]
+func (h *WSHandler) GetProtocol(c *gin.Context) {
+	c.JSON(http.StatusOK, websocket.DescribeProtocol())
+}
+
+// GetHealth godoc
+// @Summary Hub health
+// @Description Reports whether the hub is accepting connections and the state of its Redis circuit breaker (closed, open or half-open)
+// @Tags websocket
+// @Produce json
+// @Success 200 {object} map[string]string "Health status"
+// @Router /health [get]
+func (h *WSHandler) GetHealth(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status": h.hub.Health(),
+		"redis":  h.hub.RedisBreakerState(),
+	})
+}
+
+// GetMetrics godoc
+// @Summary Prometheus metrics
+// @Description WebSocket hub metrics in the Prometheus text exposition format: active and per-channel connections, broadcast counts and latency, and errors by type
+// @Tags websocket
+// @Produce plain
+// @Success 200 {string} string "Metrics"
+// @Router /metrics [get]
+func (h *WSHandler) GetMetrics(c *gin.Context) {
+	c.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.Status(http.StatusOK)
+	if err := h.hub.WritePrometheus(c.Writer); err != nil {
+		slog.Warn("Failed to write metrics", "error", err)
+	}
+}
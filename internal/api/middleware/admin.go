@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+
+	"chat-service/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminMiddleware struct {
+	userService *services.UserService
+}
+
+func NewAdminMiddleware(userService *services.UserService) *AdminMiddleware {
+	return &AdminMiddleware{
+		userService: userService,
+	}
+}
+
+// RequireAdmin rejects requests from non-admin users. Must run after RequireAuth.
+func (am *AdminMiddleware) RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			c.Abort()
+			return
+		}
+
+		isAdmin, err := am.userService.IsAdmin(userID.(uint))
+		if err != nil || !isAdmin {
+			c.JSON(http.StatusForbidden, gin.H{"error": "admin privileges required"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
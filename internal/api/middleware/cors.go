@@ -1,63 +1,71 @@
 package middleware
 
 import (
-	"os"
+	"net/http"
+	"strconv"
 	"strings"
 
+	"chat-service/internal/config"
+
 	"github.com/gin-gonic/gin"
 )
 
 // CORS middleware for handling cross-origin requests
-func CORS() gin.HandlerFunc {
+func CORS(cfg config.CORSConfig) gin.HandlerFunc {
+	allowedOrigins := make(map[string]struct{}, len(cfg.AllowedOrigins))
+	for _, origin := range cfg.AllowedOrigins {
+		allowedOrigins[origin] = struct{}{}
+	}
+	allowMethods := strings.Join(cfg.AllowedMethods, ", ")
+	allowHeaders := strings.Join(cfg.AllowedHeaders, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+
 	return func(c *gin.Context) {
 		origin := c.Request.Header.Get("Origin")
-		// Define allowed origins
-		allowedOrigins := []string{
-			"http://localhost:3000",           // Frontend dev server
-			"https://localhost:3000",          // Frontend dev server (HTTPS)
-			"http://localhost",                // Nginx proxy (Docker)
-			"https://localhost",               // Nginx proxy (HTTPS)
-			"http://127.0.0.1:3000",           // Alternative localhost
-			"http://127.0.0.1",                // Alternative localhost (Nginx)
-			"https://notify-chat.netlify.app", // Production deployment
-			"https://kaithhealth.com",         // Production deployment
-		}
-		// Add custom origins from environment variable if set
-		if customOrigins := os.Getenv("ALLOWED_ORIGINS"); customOrigins != "" {
-			for _, customOrigin := range strings.Split(customOrigins, ",") {
-				allowedOrigins = append(allowedOrigins, strings.TrimSpace(customOrigin))
-			}
-		}
-
-		// Check if origin is allowed
-		isAllowed := false
-		for _, allowedOrigin := range allowedOrigins {
-			if origin == allowedOrigin {
-				isAllowed = true
-				break
-			}
-		}
+		isAllowed := isOriginAllowed(origin, allowedOrigins, cfg.AllowLocalhost)
 
-		// Set CORS headers if origin is allowed
+		// Set CORS headers only for allowed origins
 		if isAllowed {
 			c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
-		} else {
-			// For development/testing, still allow localhost variations
-			if origin != "" && (strings.Contains(origin, "localhost") || strings.Contains(origin, "127.0.0.1")) {
-				c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
+			c.Writer.Header().Add("Vary", "Origin")
+			c.Writer.Header().Set("Access-Control-Allow-Methods", allowMethods)
+			c.Writer.Header().Set("Access-Control-Allow-Headers", allowHeaders)
+			c.Writer.Header().Set("Access-Control-Max-Age", maxAge)
+			if cfg.AllowCredentials {
+				c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 			}
 		}
 
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Max-Age", "24h")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
+		if c.Request.Method == http.MethodOptions {
+			if origin != "" && !isAllowed {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.AbortWithStatus(http.StatusNoContent)
 			return
 		}
 
 		c.Next()
 	}
 }
+
+// isOriginAllowed checks an origin against the configured allow list
+func isOriginAllowed(origin string, allowedOrigins map[string]struct{}, allowLocalhost bool) bool {
+	if origin == "" {
+		return false
+	}
+	if _, ok := allowedOrigins[origin]; ok {
+		return true
+	}
+	if allowLocalhost {
+		host := origin
+		if i := strings.Index(host, "://"); i >= 0 {
+			host = host[i+3:]
+		}
+		if i := strings.LastIndex(host, ":"); i >= 0 {
+			host = host[:i]
+		}
+		return host == "localhost" || host == "127.0.0.1"
+	}
+	return false
+}
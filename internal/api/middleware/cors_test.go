@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"chat-service/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+var testCORSConfig = config.CORSConfig{
+	AllowedOrigins:   []string{"https://app.example.com"},
+	AllowedMethods:   []string{"GET", "POST"},
+	AllowedHeaders:   []string{"Authorization", "Content-Type"},
+	AllowCredentials: true,
+	MaxAge:           time.Hour,
+}
+
+// serveCORS sends one request with the given origin through the CORS middleware
+func serveCORS(cfg config.CORSConfig, method, origin string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CORS(cfg))
+	router.Handle(method, "/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(method, "/ping", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCORS(t *testing.T) {
+	localhost := testCORSConfig
+	localhost.AllowLocalhost = true
+
+	tests := []struct {
+		name       string
+		cfg        config.CORSConfig
+		method     string
+		origin     string
+		wantStatus int
+		wantOrigin string
+	}{
+		{"allowed origin", testCORSConfig, http.MethodGet, "https://app.example.com", http.StatusOK, "https://app.example.com"},
+		{"allowed origin preflight", testCORSConfig, http.MethodOptions, "https://app.example.com", http.StatusNoContent, "https://app.example.com"},
+		{"disallowed origin", testCORSConfig, http.MethodGet, "https://evil.example.com", http.StatusOK, ""},
+		{"disallowed origin preflight", testCORSConfig, http.MethodOptions, "https://evil.example.com", http.StatusForbidden, ""},
+		{"no origin", testCORSConfig, http.MethodGet, "", http.StatusOK, ""},
+		{"no origin preflight", testCORSConfig, http.MethodOptions, "", http.StatusNoContent, ""},
+		{"localhost not allowed by default", testCORSConfig, http.MethodGet, "http://localhost:5173", http.StatusOK, ""},
+		{"localhost allowed", localhost, http.MethodGet, "http://localhost:5173", http.StatusOK, "http://localhost:5173"},
+		{"loopback allowed", localhost, http.MethodOptions, "http://127.0.0.1:8080", http.StatusNoContent, "http://127.0.0.1:8080"},
+		{"localhost suffix rejected", localhost, http.MethodOptions, "http://localhost.evil.com", http.StatusForbidden, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveCORS(tt.cfg, tt.method, tt.origin)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			header := rec.Header()
+			if got := header.Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Fatalf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantOrigin)
+			}
+			if tt.wantOrigin == "" {
+				if got := header.Get("Access-Control-Allow-Credentials"); got != "" {
+					t.Fatalf("Access-Control-Allow-Credentials = %q for a rejected origin", got)
+				}
+				return
+			}
+			if got := header.Get("Access-Control-Allow-Methods"); got != "GET, POST" {
+				t.Fatalf("Access-Control-Allow-Methods = %q", got)
+			}
+			if got := header.Get("Access-Control-Allow-Headers"); got != "Authorization, Content-Type" {
+				t.Fatalf("Access-Control-Allow-Headers = %q", got)
+			}
+			if got := header.Get("Access-Control-Max-Age"); got != "3600" {
+				t.Fatalf("Access-Control-Max-Age = %q, want 3600", got)
+			}
+			if got := header.Get("Access-Control-Allow-Credentials"); got != "true" {
+				t.Fatalf("Access-Control-Allow-Credentials = %q, want true", got)
+			}
+			if got := header.Get("Vary"); got != "Origin" {
+				t.Fatalf("Vary = %q, want Origin", got)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package routes
 import (
 	"chat-service/internal/api/handlers"
 	"chat-service/internal/api/middleware"
+	"chat-service/internal/config"
 	"chat-service/internal/repositories/postgres"
 	"chat-service/internal/services"
 	"chat-service/internal/websocket"
@@ -16,12 +17,16 @@ import (
 type Router struct {
 	engine         *gin.Engine
 	wsHandler      *handlers.WSHandler
+	healthHandler  *handlers.HealthHandler
 	channelHandler *handlers.ChannelHandler
+	webhookHandler *handlers.ChannelWebhookHandler
+	inviteHandler  *handlers.InviteHandler
 	messageHandler *handlers.ChatHandler
 	userHandler    *handlers.UserHandler
 	authHandler    *handlers.AuthHandler
 	rateLimitMW    *middleware.RateLimitMiddleware
 	authMW         *middleware.AuthMiddleware
+	adminMW        *middleware.AdminMiddleware
 }
 
 func NewRouter(
@@ -29,39 +34,54 @@ func NewRouter(
 	redisService *services.RedisService,
 	redisClient *redis.Client,
 	db *gorm.DB,
-	jwtSecret string,
+	cfg *config.Config,
 ) *Router {
 	gin.SetMode(gin.ReleaseMode)
 	engine := gin.New()
 
 	// Add middlewares
 	engine.Use(gin.Recovery())
-	engine.Use(middleware.CORS())
+	engine.Use(middleware.CORS(cfg.CORS))
 	engine.Use(middleware.LogApi())
 
 	// Initialize repositories
 	channelRepo := postgres.NewChannelRepository(db)
 	userRepo := postgres.NewUserRepository(db)
 	chatRepo := postgres.NewChatRepository(db)
+	reactionRepo := postgres.NewReactionRepository(db)
+	readRepo := postgres.NewReadStateRepository(db)
+	webhookRepo := postgres.NewChannelWebhookRepository(db)
+	refreshRepo := postgres.NewRefreshTokenRepository(db)
+	inviteRepo := postgres.NewInviteRepository(db)
 
 	// Initialize services
-	channelService := services.NewChannelService(channelRepo, userRepo)
-	userService := services.NewUserService(userRepo, jwtSecret, redisClient)
+	channelService := services.NewChannelService(channelRepo, userRepo, cfg.Channel.MaxOwnedPerUser)
+	userService := services.NewUserService(userRepo, refreshRepo, cfg.JWT.Secret, redisClient, cfg.JWT.ExpirationTime, cfg.JWT.RefreshExpirationTime)
+	chatService := services.NewChatService(chatRepo, channelRepo, reactionRepo, userRepo, cfg.Search.SnippetMaxWords, cfg.Message.MaxTextBytes)
+	messageQuota := services.NewMessageQuota(redisService, userRepo, cfg.Message.DailyQuota)
+	statsService := services.NewChannelStatsService(channelRepo, chatRepo, reactionRepo, redisService)
+	readService := services.NewReadStateService(readRepo, chatRepo, channelRepo)
+	webhookService := services.NewChannelWebhookService(webhookRepo, channelRepo, userRepo, chatRepo, chatService, redisService, cfg.ChannelWebhook.TokenSecret)
+	inviteService := services.NewInviteService(inviteRepo, channelRepo)
 
 	// Initialize handlers
-	wsHandler := handlers.NewWSHandler(hub)
+	wsHandler := handlers.NewWSHandler(hub, userService, channelService)
 	rateLimitMW := middleware.NewRateLimitMiddleware(redisService)
-	authMW := middleware.NewAuthMiddleware(jwtSecret)
+	authMW := middleware.NewAuthMiddleware(cfg.JWT.Secret)
 
 	return &Router{
 		engine:         engine,
 		wsHandler:      wsHandler,
-		channelHandler: handlers.NewChannelHandler(channelService),
-		messageHandler: handlers.NewChatHandler(channelService, userService, chatRepo, hub),
-		userHandler:    handlers.NewUserHandler(userService, redisClient),
+		healthHandler:  handlers.NewHealthHandler(hub, db, redisClient),
+		channelHandler: handlers.NewChannelHandler(channelService, statsService, readService, hub),
+		webhookHandler: handlers.NewChannelWebhookHandler(webhookService, hub),
+		inviteHandler:  handlers.NewInviteHandler(inviteService, hub),
+		messageHandler: handlers.NewChatHandler(channelService, userService, chatService, chatRepo, hub),
+		userHandler:    handlers.NewUserHandler(userService, messageQuota, redisClient),
 		authHandler:    handlers.NewAuthHandler(userService, redisClient),
 		rateLimitMW:    rateLimitMW,
 		authMW:         authMW,
+		adminMW:        middleware.NewAdminMiddleware(userService),
 	}
 }
 
@@ -70,6 +90,16 @@ func (r *Router) SetupRoutes() {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
 
+	// Hub and Redis circuit breaker status
+	r.engine.GET("/health", r.wsHandler.GetHealth)
+
+	// Liveness and readiness probes
+	r.engine.GET("/healthz", r.healthHandler.GetHealthz)
+	r.engine.GET("/readyz", r.healthHandler.GetReadyz)
+
+	// Prometheus scrape endpoint
+	r.engine.GET("/metrics", r.wsHandler.GetMetrics)
+
 	api := r.engine.Group("/api/v1")
 
 	// WebSocket endpoint with authentication and rate limiting
@@ -79,6 +109,16 @@ func (r *Router) SetupRoutes() {
 		r.wsHandler.HandleWebSocket,
 	)
 
+	// WebSocket protocol description for clients and SDKs
+	api.GET("/ws/protocol", r.wsHandler.GetProtocol)
+
+	// WebSocket diagnostics (admin only)
+	api.GET("/ws/connections/:userId",
+		r.authMW.RequireAuth(),
+		r.adminMW.RequireAdmin(),
+		r.wsHandler.GetConnectionState,
+	)
+
 	// Authenticated routes
 	auth := api.Group("/")
 	auth.Use(r.authMW.RequireAuth())
@@ -87,9 +127,13 @@ func (r *Router) SetupRoutes() {
 		users := auth.Group("/users")
 		users.Use(r.rateLimitMW.RateLimit(100, time.Minute)) // 100 requests per minute
 		{
+			users.GET("", r.userHandler.ListUsers)
 			users.GET("/profile", r.userHandler.GetProfile)
+			users.GET("/me/quota", r.userHandler.GetQuota)
 			users.PUT("/profile", r.userHandler.UpdateProfile)
 			users.GET("/search", r.userHandler.SearchUsersByUsername)
+			users.POST("/:id/disconnect", r.wsHandler.ForceDisconnect)
+			users.GET("/:id/presence", r.wsHandler.GetUserPresence)
 		}
 
 		// Channel routes
@@ -103,10 +147,46 @@ func (r *Router) SetupRoutes() {
 			channels.GET("/:id", r.channelHandler.GetChannelByID)
 			channels.PUT("/:id", r.channelHandler.UpdateChannel)
 			channels.DELETE("/:id", r.channelHandler.DeleteChannel)
+			channels.POST("/:id/archive", r.channelHandler.ArchiveChannel)
+			channels.POST("/:id/unarchive", r.channelHandler.UnarchiveChannel)
 			// user-channel relation logic
 			channels.POST(channelUserRoute, r.channelHandler.AddUserToChannel)
 			channels.PUT(channelUserRoute, r.channelHandler.LeaveChannel)
 			channels.DELETE(channelUserRoute, r.channelHandler.RemoveUserFromChannel)
+			channels.PUT("/:id/members/:userId/role", r.channelHandler.UpdateMemberRole)
+			channels.PUT("/:id/slow-mode", r.channelHandler.UpdateSlowMode)
+			channels.GET("/:id/stats", r.channelHandler.GetChannelStats)
+			channels.POST("/:id/mute", r.channelHandler.MuteChannel)
+			channels.DELETE("/:id/mute", r.channelHandler.UnmuteChannel)
+			channels.PUT("/:id/read", r.channelHandler.MarkReadByTime)
+			channels.GET("/:id/reads", r.channelHandler.GetChannelReads)
+			channels.GET("/:id/summary", r.channelHandler.GetChannelSummary)
+			channels.GET("/:id/presence", r.wsHandler.GetChannelPresence)
+			channels.POST("/:id/webhooks", r.webhookHandler.CreateWebhook)
+			channels.GET("/:id/webhooks", r.webhookHandler.ListWebhooks)
+			channels.PUT("/:id/webhooks/:webhookId", r.webhookHandler.UpdateWebhook)
+			channels.DELETE("/:id/webhooks/:webhookId", r.webhookHandler.DeleteWebhook)
+			channels.POST("/:id/inbound-webhooks", r.webhookHandler.CreateInboundWebhook)
+			channels.GET("/:id/inbound-webhooks", r.webhookHandler.ListInboundWebhooks)
+			channels.DELETE("/:id/inbound-webhooks/:webhookId", r.webhookHandler.DeleteInboundWebhook)
+			// message forwarding
+			channels.GET("/:id/messages", r.messageHandler.GetChannelHistory)
+			channels.POST("/:id/forward", r.messageHandler.ForwardMessage)
+			channels.POST("/:id/messages/:messageId/pin", r.messageHandler.PinMessage)
+			channels.DELETE("/:id/messages/:messageId/pin", r.messageHandler.UnpinMessage)
+			channels.GET("/:id/pins", r.messageHandler.ListPins)
+			channels.POST("/:id/invites", r.inviteHandler.CreateInvite)
+		}
+
+		// Admin routes
+		admin := auth.Group("/admin")
+		admin.Use(r.adminMW.RequireAdmin())
+		{
+			admin.POST("/channels/bulk", r.channelHandler.BulkCreateChannels)
+			admin.GET("/channels/sizes", r.wsHandler.GetChannelSizes)
+			admin.POST("/users/:id/disconnect", r.wsHandler.ForceDisconnect)
+			admin.GET("/ws/errors", r.wsHandler.GetErrorHistory)
+			admin.GET("/ws/connections", r.wsHandler.ListConnections)
 		}
 
 		// Message routes
@@ -114,8 +194,20 @@ func (r *Router) SetupRoutes() {
 		messages.Use(r.rateLimitMW.RateLimit(200, time.Minute)) // 200 requests per minute
 		{
 			messages.GET("/channel/:id", r.messageHandler.GetChannelMessages)
-			// messages.PUT("/:id", r.messageHandler.UpdateMessage)
-			// messages.DELETE("/:id", r.messageHandler.DeleteMessage)
+			messages.GET("/search", r.messageHandler.SearchMessages)
+			messages.POST("/catchup", r.messageHandler.CatchUp)
+			messages.GET("/:id/thread", r.messageHandler.GetThread)
+			messages.POST("/:id/reactions", r.messageHandler.AddReaction)
+			messages.DELETE("/:id/reactions/:emoji", r.messageHandler.RemoveReaction)
+			messages.PUT("/:id", r.messageHandler.EditMessage)
+			messages.DELETE("/:id", r.messageHandler.DeleteMessage)
+		}
+
+		// Invite routes, limited tighter to slow down token guessing
+		invites := auth.Group("/invites")
+		invites.Use(r.rateLimitMW.RateLimit(30, time.Minute)) // 30 requests per minute
+		{
+			invites.POST("/:token/accept", r.inviteHandler.AcceptInvite)
 		}
 	}
 
@@ -128,6 +220,15 @@ func (r *Router) SetupRoutes() {
 		{
 			authRoutes.POST("/register", r.authHandler.Register)
 			authRoutes.POST("/login", r.authHandler.Login)
+			authRoutes.POST("/refresh", r.authHandler.Refresh)
+			authRoutes.POST("/logout", r.authHandler.Logout)
+		}
+
+		// Inbound integration webhooks, authenticated by the token in the path
+		webhookRoutes := public.Group("/webhooks")
+		webhookRoutes.Use(r.rateLimitMW.RateLimitIP(120, time.Minute)) // 120 requests per minute per IP
+		{
+			webhookRoutes.POST("/:token", r.webhookHandler.PostInboundMessage)
 		}
 	}
 }
@@ -41,7 +41,21 @@ func NewPostgresConnection(dburi string) (*gorm.DB, error) {
 	err = db.AutoMigrate(
 		&models.User{},
 		&models.Channel{},
+		&models.ChannelMember{},
+		&models.ChannelMute{},
 		&models.Chat{},
+		&models.Attachment{},
+		&models.Reaction{},
+		&models.PinnedMessage{},
+		&models.ChannelRead{},
+		&models.ChannelWebhook{},
+		&models.InboundWebhook{},
+		&models.BlockedUser{},
+		&models.RefreshToken{},
+		&models.Invite{},
+		&models.UserPresence{},
+		&models.Mention{},
+		&models.MentionNotification{},
 	)
 	if err != nil {
 		// Check if the error is about existing tables
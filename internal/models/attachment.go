@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+/** --------------------ENTITIES-------------------- */
+// Attachment describes a file attached to a chat message. Only metadata is
+// stored; the file itself lives at URL.
+type Attachment struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	ChatID    uint      `gorm:"not null;index" json:"messageId"`
+	URL       string    `gorm:"not null" json:"url"`
+	MimeType  string    `gorm:"not null;type:varchar(100)" json:"mimeType"`
+	Size      int64     `gorm:"not null;default:0" json:"size"` // bytes
+	Width     *int      `json:"width,omitempty"`                // images and video only
+	Height    *int      `json:"height,omitempty"`               // images and video only
+	CreatedAt time.Time `json:"createdAt"`
+}
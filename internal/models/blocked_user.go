@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+/** --------------------ENTITIES-------------------- */
+// BlockedUser records that BlockerID does not want direct messages from BlockedID.
+// Blocking is one-way.
+type BlockedUser struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	BlockerID uint      `gorm:"not null;uniqueIndex:idx_blocked_users_pair" json:"blockerId"`
+	BlockedID uint      `gorm:"not null;uniqueIndex:idx_blocked_users_pair;index" json:"blockedId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -19,15 +19,66 @@ type Channel struct {
 	OwnerID uint   `gorm:"not null;type:uint" json:"ownerId"`                                       // ID of the channel owner
 	Type    string `gorm:"not null;type:varchar(20);check:type IN ('direct', 'group')" json:"type"` // Type of channel, either 'direct' or 'group'
 
+	SlowModeSeconds int `gorm:"not null;default:0" json:"slowModeSeconds"` // Minimum seconds between messages per user, 0 disables
+
+	Archived bool `gorm:"not null;default:false" json:"archived"` // Read-only: members and history are kept but no new messages are accepted
+
 	Members []*User `gorm:"many2many:channel_members" json:"members"`
 }
 
+// Channel member role constants. The owner role is not stored: it follows
+// Channel.OwnerID so ownership keeps a single source of truth.
+const (
+	ChannelRoleOwner  = "owner"
+	ChannelRoleAdmin  = "admin"
+	ChannelRoleMember = "member"
+)
+
+// ChannelMember is a row of the channel_members join table
+type ChannelMember struct {
+	ChannelID uint   `gorm:"primaryKey" json:"channelId"`
+	UserID    uint   `gorm:"primaryKey" json:"userId"`
+	Role      string `gorm:"not null;type:varchar(20);default:'member';check:role IN ('admin', 'member')" json:"role"`
+}
+
+func (ChannelMember) TableName() string {
+	return "channel_members"
+}
+
+// ChannelMute silences notifications from a channel for one user. Messages are
+// still delivered, flagged so the client can skip the notification. A nil
+// MutedUntil mutes until explicitly unmuted; a time in the past means unmuted.
+type ChannelMute struct {
+	ChannelID  uint       `gorm:"primaryKey" json:"channelId"`
+	UserID     uint       `gorm:"primaryKey" json:"userId"`
+	MutedUntil *time.Time `json:"mutedUntil,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
 /** -------------------- DTOs -------------------- */
 
+// MuteChannelRequest represents the request for muting a channel
+type MuteChannelRequest struct {
+	Until *time.Time `json:"until"` // RFC 3339, omit to mute until unmuted
+}
+
+// UpdateMemberRoleRequest represents the request for changing a member's role
+type UpdateMemberRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=admin member"`
+}
+
 type UpdateChannelRequest struct {
 	Name string `json:"name" binding:"required"`
 }
 
+// Upper bound for channel slow mode
+const MaxSlowModeSeconds = 6 * 60 * 60
+
+// UpdateSlowModeRequest represents the request for changing a channel's slow mode
+type UpdateSlowModeRequest struct {
+	Seconds *int `json:"seconds" binding:"required,min=0,max=21600"` // 0 disables slow mode
+}
+
 // CreateChannelRequest represents the request for creating a new channel with user selection
 type CreateChannelRequest struct {
 	Name    string `json:"name" binding:"omitempty"` // Optional for direct messages, required for group
@@ -35,6 +86,34 @@ type CreateChannelRequest struct {
 	UserIDs []uint `json:"userIds" binding:"required,min=2,max=4"` // Minimum 2, maximum 4 users
 }
 
+// BulkChannelItem is one channel definition in a bulk provisioning request
+type BulkChannelItem struct {
+	Name    string `json:"name"` // required for group channels
+	Type    string `json:"type" binding:"required,oneof=direct group"`
+	OwnerID uint   `json:"ownerId" binding:"required"`
+	UserIDs []uint `json:"userIds" binding:"required,min=2,max=4"` // must include the owner
+}
+
+// BulkCreateChannelsRequest represents the request for provisioning many channels at once
+type BulkCreateChannelsRequest struct {
+	Channels []BulkChannelItem `json:"channels" binding:"required,min=1,max=100,dive"`
+}
+
+// BulkChannelResult reports the outcome of one item of a bulk request
+type BulkChannelResult struct {
+	Index   int              `json:"index"`
+	Success bool             `json:"success"`
+	Channel *ChannelResponse `json:"channel,omitempty"`
+	Error   string           `json:"error,omitempty"`
+}
+
+// BulkCreateChannelsResponse reports per-item results of a bulk request
+type BulkCreateChannelsResponse struct {
+	Results   []BulkChannelResult `json:"results"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+}
+
 type ChannelDetailResponse struct {
 	ID        uint      `json:"id"`
 	Name      string    `json:"name"`
@@ -42,6 +121,8 @@ type ChannelDetailResponse struct {
 	CreatedAt time.Time `json:"createdAt"`
 	OwnerID   uint      `json:"ownerId"`
 	Members   []User    `json:"members"` // List of members in the channel
+
+	SlowModeSeconds int `json:"slowModeSeconds"`
 }
 
 type ChannelResponse struct {
@@ -49,6 +130,9 @@ type ChannelResponse struct {
 	Name    string `json:"name"`
 	Type    string `json:"type"`
 	OwnerID uint   `json:"ownerId"`
+
+	SlowModeSeconds int  `json:"slowModeSeconds"`
+	Archived        bool `json:"archived,omitempty"`
 }
 
 type DirectChannelResponse struct {
@@ -57,6 +141,9 @@ type DirectChannelResponse struct {
 	Avatar  string `json:"avatar,omitempty"` // Optional avatar for direct channels
 	Type    string `json:"type"`
 	OwnerID uint   `json:"ownerId"`
+
+	SlowModeSeconds int  `json:"slowModeSeconds"`
+	Archived        bool `json:"archived,omitempty"`
 }
 
 // UserChannelsResponse represents the response for user's channels separated by type
@@ -64,3 +151,27 @@ type UserChannelsResponse struct {
 	Direct []DirectChannelResponse `json:"direct"` // List of channels of type 'direct'
 	Group  []ChannelResponse       `json:"group"`  // List of channels of type 'group'
 }
+
+// MemberActivity is a member's message count within a channel
+type MemberActivity struct {
+	UserID       uint   `json:"userId"`
+	Username     string `json:"username"`
+	MessageCount int64  `json:"messageCount"`
+}
+
+// ReactionUsage is how often an emoji has been used in a channel
+type ReactionUsage struct {
+	Emoji string `json:"emoji"`
+	Count int64  `json:"count"`
+}
+
+// ChannelStatsResponse holds lightweight engagement analytics for a channel
+type ChannelStatsResponse struct {
+	ChannelID           uint             `json:"channelId"`
+	MessageCount        int64            `json:"messageCount"`
+	TopMembers          []MemberActivity `json:"topMembers"`
+	TopReactions        []ReactionUsage  `json:"topReactions"`
+	BusiestHour         *int             `json:"busiestHour,omitempty"` // hour of day in UTC, 0-23
+	BusiestHourMessages int64            `json:"busiestHourMessages"`
+	GeneratedAt         time.Time        `json:"generatedAt"`
+}
@@ -2,7 +2,16 @@ package models
 
 // ErrorResponse is a standardized error response for API
 type ErrorResponse struct {
-	Code    int    `json:"code"`
+	Code    int          `json:"code"`
+	Message string       `json:"message"`
+	Details string       `json:"details,omitempty"`
+	Errors  []FieldError `json:"errors,omitempty"` // field-level validation failures
+}
+
+// FieldError describes a single request field that failed validation
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
 	Message string `json:"message"`
-	Details string `json:"details,omitempty"`
 }
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+/** --------------------ENTITIES-------------------- */
+// Invite is a shareable link that lets whoever holds its token join a channel.
+// It stops working once ExpiresAt passes or it has been used MaxUses times.
+type Invite struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	ChannelID uint       `gorm:"not null;index" json:"channelId"`
+	Token     string     `gorm:"not null;uniqueIndex;type:varchar(64)" json:"token"`
+	CreatedBy uint       `gorm:"not null" json:"createdBy"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`               // nil never expires
+	MaxUses   int        `gorm:"not null;default:0" json:"maxUses"` // 0 is unlimited
+	Uses      int        `gorm:"not null;default:0" json:"uses"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+/** -------------------- DTOs -------------------- */
+// CreateInviteRequest represents the request for creating a channel invite
+type CreateInviteRequest struct {
+	ExpiresIn int `json:"expiresIn" binding:"omitempty,min=60,max=2592000"` // seconds, omit for no expiry
+	MaxUses   int `json:"maxUses" binding:"omitempty,min=1,max=10000"`      // omit for unlimited
+}
+
+// AcceptInviteResponse reports the channel joined through an invite
+type AcceptInviteResponse struct {
+	ChannelID uint `json:"channelId"`
+	Joined    bool `json:"joined"` // false when the user was already a member
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+/** --------------------ENTITIES-------------------- */
+// Mention records that a channel message mentioned a user with @username
+type Mention struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ChatID    uint      `gorm:"not null;uniqueIndex:idx_mentions_chat_user" json:"chatId"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_mentions_chat_user;index" json:"userId"`
+	ChannelID uint      `gorm:"not null" json:"channelId"`
+	SenderID  uint      `gorm:"not null" json:"senderId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// MentionNotification is a queued notice for a user who was offline when they
+// were mentioned. Consumers such as push or email workers pick up rows with no
+// ConsumedAt and set it once the notice has been sent.
+type MentionNotification struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	UserID     uint       `gorm:"not null;index" json:"userId"`
+	ChatID     uint       `gorm:"not null" json:"chatId"`
+	ChannelID  uint       `gorm:"not null" json:"channelId"`
+	SenderID   uint       `gorm:"not null" json:"senderId"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ConsumedAt *time.Time `gorm:"index" json:"consumedAt,omitempty"`
+}
@@ -1,9 +1,10 @@
 package models
 
 import (
-	"fmt"
+	"errors"
 	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -14,10 +15,59 @@ type PaginatedChatResponse struct {
 	NextCursor *int64         `json:"nextCursor,omitempty"`
 }
 
+// ChatHistoryPage is one page of a channel's message history, newest first.
+// NextCursor is the ID to pass as "before" for the next page, null on the last page.
+type ChatHistoryPage struct {
+	Items      []ChatResponse `json:"items"`
+	NextCursor *uint          `json:"nextCursor"`
+}
+
+// CatchupRequest maps each channel ID to the last message ID the client has seen
+// there. Use 0 for a channel the client has no messages of.
+type CatchupRequest struct {
+	Channels map[uint]uint `json:"channels" binding:"required"`
+}
+
+// CatchupChannel holds the messages of one channel newer than the client's
+// cursor, oldest first. HasMore is set when more were left out by the cap.
+type CatchupChannel struct {
+	Items   []ChatResponse `json:"items"`
+	HasMore bool           `json:"hasMore"`
+}
+
+// CatchupResponse maps each requested channel the user belongs to to its new messages
+type CatchupResponse struct {
+	Channels map[uint]CatchupChannel `json:"channels"`
+}
+
+// ThreadResponse is a message with its replies, oldest first
+type ThreadResponse struct {
+	Parent  ChatResponse   `json:"parent"`
+	Replies []ChatResponse `json:"replies"`
+}
+
+// ErrInvalidChatTarget is returned for a chat that is addressed to both or neither
+// of a receiver and a channel
+var ErrInvalidChatTarget = errors.New("exactly one of ReceiverID or ChannelID must be set")
+
 // Validate checks that exactly one of ReceiverID or ChannelID is set for a Chat
 func (c *Chat) Validate() error {
 	if (c.ReceiverID == nil && c.ChannelID == 0) || (c.ReceiverID != nil && c.ChannelID != 0) {
-		return fmt.Errorf("exactly one of ReceiverID or ChannelID must be set")
+		return ErrInvalidChatTarget
+	}
+	return nil
+}
+
+// BeforeCreate rejects chats without exactly one target and assigns a UUID to
+// chats that were not given one by the caller. The chat type is never stored; it
+// follows from the target, see GetType.
+func (c *Chat) BeforeCreate(tx *gorm.DB) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	if c.UUID == nil {
+		id := uuid.New().String()
+		c.UUID = &id
 	}
 	return nil
 }
@@ -45,16 +95,34 @@ const (
 // Chat represents a chat message
 type Chat struct {
 	gorm.Model
+	// UUID is a public message ID that can be known before the row is persisted
+	// (client-generated or assigned at broadcast time). Integer IDs remain the primary key.
+	UUID *string `gorm:"type:uuid;not null;uniqueIndex" json:"uuid,omitempty"`
 
-	SenderID   uint  `gorm:"not null" json:"senderId"`
+	SenderID   uint  `gorm:"not null;uniqueIndex:idx_chats_sender_client_msg_id" json:"senderId"`
 	ReceiverID *uint `gorm:"type:uint" json:"receiverId"` // for direct messages
 
+	// ClientMsgID is the sender's own reference for the message. It is unique per
+	// sender so a message resent after a dropped connection is not stored twice.
+	ClientMsgID *string `gorm:"type:varchar(128);uniqueIndex:idx_chats_sender_client_msg_id" json:"-"`
+
 	ChannelID uint `gorm:"type:uint" json:"channelId"` // only if type == channel
 
 	Text     *string `json:"text,omitempty"`     // optional
 	URL      *string `json:"url,omitempty"`      // optional
 	FileName *string `json:"fileName,omitempty"` // optional
 
+	ForwardedFrom *uint `gorm:"type:uint" json:"forwardedFrom,omitempty"` // ID of the original message when forwarded
+	ParentID      *uint `gorm:"index" json:"parentId,omitempty"`          // message this one replies to; threads are one level deep
+
+	EditedAt *time.Time `json:"editedAt,omitempty"` // set when the sender last edited the text
+
+	Attachments []Attachment `gorm:"foreignKey:ChatID" json:"attachments,omitempty"`
+
+	// Mentions holds the IDs of the members mentioned with @username. It is filled
+	// when the message is delivered and is not a column; see Mention.
+	Mentions []uint `gorm:"-" json:"mentions,omitempty"`
+
 	Sender   User    `gorm:"foreignKey:SenderID"`
 	Receiver *User   `gorm:"foreignKey:ReceiverID"` // pointer to allow null
 	Channel  Channel `gorm:"foreignKey:ChannelID"`
@@ -69,9 +137,34 @@ type ChatRequest struct {
 	FileName  *string `json:"fileName,omitempty"`
 }
 
+// EditMessageRequest represents the request for editing a message's text
+type EditMessageRequest struct {
+	Text string `json:"text" binding:"required"` // size limit enforced by the service (413)
+}
+
+// ForwardMessageRequest represents the request for forwarding a message into another channel
+type ForwardMessageRequest struct {
+	MessageID       uint `json:"messageId" binding:"required"`
+	TargetChannelID uint `json:"targetChannelId" binding:"required"`
+}
+
 // Response
+// MessageSearchResult is a message matching a search query. Snippet contains the
+// surrounding text with matched terms wrapped in <mark></mark>.
+type MessageSearchResult struct {
+	ID         uint      `json:"id"`
+	UUID       *string   `json:"uuid,omitempty"`
+	ChannelID  uint      `json:"channelId"`
+	SenderID   uint      `json:"senderId"`
+	SenderName string    `json:"senderName"`
+	Snippet    string    `json:"snippet"`
+	Rank       float64   `json:"rank"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
 type ChatResponse struct {
 	ID           uint      `json:"id"`
+	UUID         *string   `json:"uuid,omitempty"`         // public message ID
 	Type         string    `json:"type"`                   // "direct" | "group"
 	SenderID     uint      `json:"senderId"`               // ID of the user who sent the message
 	SenderName   string    `json:"senderName"`             // Username of the sender
@@ -81,6 +174,13 @@ type ChatResponse struct {
 	FileName     *string   `json:"fileName,omitempty"`     // optional file name for media
 	CreatedAt    time.Time `json:"createdAt"`              // timestamp of when the message was created
 
+	ForwardedFrom *uint      `json:"forwardedFrom,omitempty"` // original message ID when forwarded
+	ParentID      *uint      `json:"parentId,omitempty"`      // thread parent when this is a reply
+	EditedAt      *time.Time `json:"editedAt,omitempty"`      // last edit time, absent if never edited
+	Deleted       bool       `json:"deleted,omitempty"`       // tombstone: content is withheld
+
+	Attachments []Attachment `gorm:"-" json:"attachments,omitempty"`
+
 	// Relate to type message
 	ReceiverID *uint `json:"receiverId,omitempty"` // direct
 	ChannelID  *uint `json:"channelId,omitempty"`  // channel
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestChatValidate(t *testing.T) {
+	receiver := uint(7)
+
+	tests := []struct {
+		name     string
+		chat     Chat
+		wantErr  bool
+		wantType string
+	}{
+		{"channel message", Chat{SenderID: 1, ChannelID: 3}, false, string(ChatTypeChannel)},
+		{"direct message", Chat{SenderID: 1, ReceiverID: &receiver}, false, string(ChatTypeDirect)},
+		{"no target", Chat{SenderID: 1}, true, ""},
+		{"both targets", Chat{SenderID: 1, ChannelID: 3, ReceiverID: &receiver}, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.chat.Validate()
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidChatTarget) {
+					t.Fatalf("Validate() error = %v, want ErrInvalidChatTarget", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if got := tt.chat.GetType(); got != tt.wantType {
+				t.Errorf("GetType() = %q, want %q", got, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestChatBeforeCreate(t *testing.T) {
+	chat := Chat{SenderID: 1, ChannelID: 3}
+	if err := chat.BeforeCreate(nil); err != nil {
+		t.Fatalf("BeforeCreate() error = %v", err)
+	}
+	if chat.UUID == nil || *chat.UUID == "" {
+		t.Fatal("BeforeCreate did not assign a UUID")
+	}
+
+	given := "6f1c3c3e-8a59-4d57-9d3e-2f0b6a6d8c11"
+	chat = Chat{SenderID: 1, ChannelID: 3, UUID: &given}
+	if err := chat.BeforeCreate(nil); err != nil {
+		t.Fatalf("BeforeCreate() error = %v", err)
+	}
+	if *chat.UUID != given {
+		t.Errorf("UUID = %q, want the caller's %q", *chat.UUID, given)
+	}
+
+	invalid := Chat{SenderID: 1}
+	if err := invalid.BeforeCreate(nil); !errors.Is(err, ErrInvalidChatTarget) {
+		t.Errorf("BeforeCreate() error = %v, want ErrInvalidChatTarget", err)
+	}
+}
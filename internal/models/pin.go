@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Most messages a channel may have pinned at once
+const MaxPinsPerChannel = 50
+
+/** --------------------ENTITIES-------------------- */
+// PinnedMessage marks a message as pinned in its channel. A message is pinned
+// at most once; pinning it again keeps the original pin.
+type PinnedMessage struct {
+	ChannelID uint      `gorm:"primaryKey" json:"channelId"`
+	ChatID    uint      `gorm:"primaryKey" json:"messageId"`
+	PinnedBy  uint      `gorm:"not null" json:"pinnedBy"`
+	PinnedAt  time.Time `gorm:"not null;index" json:"pinnedAt"`
+}
+
+/** -------------------- DTOs -------------------- */
+// PinnedMessageResponse is a pinned message with who pinned it and when
+type PinnedMessageResponse struct {
+	ChatResponse
+	PinnedBy uint      `json:"pinnedBy"`
+	PinnedAt time.Time `json:"pinnedAt"`
+}
+
+// PinEvent describes a pin or unpin for broadcasting
+type PinEvent struct {
+	ChannelID uint       `json:"channelId"`
+	MessageID uint       `json:"messageId"`
+	UserID    uint       `json:"userId"`             // who pinned, or who unpinned
+	PinnedAt  *time.Time `json:"pinnedAt,omitempty"` // absent for unpins
+	Changed   bool       `json:"changed"`            // false when the message was already pinned or unpinned
+}
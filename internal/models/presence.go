@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+/** --------------------ENTITIES-------------------- */
+// UserPresence keeps when a user was last connected, so offline users can be
+// shown as last seen long after their Redis status has expired
+type UserPresence struct {
+	UserID     uint      `gorm:"primaryKey" json:"userId"`
+	LastSeenAt time.Time `gorm:"not null" json:"lastSeenAt"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Reaction operations
+const (
+	ReactionOpAdd    = "add"
+	ReactionOpRemove = "remove"
+)
+
+// Maximum length of a reaction emoji (multi-codepoint emoji included)
+const MaxEmojiLength = 32
+
+/** --------------------ENTITIES-------------------- */
+// Reaction is a single user's emoji reaction to a chat message.
+// A user can react with the same emoji only once per message.
+type Reaction struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	ChatID    uint      `gorm:"not null;uniqueIndex:idx_reactions_chat_user_emoji" json:"messageId"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_reactions_chat_user_emoji" json:"userId"`
+	Emoji     string    `gorm:"not null;type:varchar(32);uniqueIndex:idx_reactions_chat_user_emoji" json:"emoji"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+/** -------------------- DTOs -------------------- */
+// ReactionRequest represents the request for adding a reaction
+type ReactionRequest struct {
+	Emoji string `json:"emoji" binding:"required,max=32"`
+}
+
+// ReactionEvent describes a reaction change and the message's reactions after it
+type ReactionEvent struct {
+	MessageID uint              `json:"messageId"`
+	ChannelID uint              `json:"channelId"`
+	UserID    uint              `json:"userId"`
+	Emoji     string            `json:"emoji"`
+	Op        string            `json:"op"`        // "add" | "remove"
+	Count     int64             `json:"count"`     // total reactions with this emoji after the change
+	Changed   bool              `json:"changed"`   // false when the operation was a no-op
+	Reactions []ReactionSummary `json:"reactions"` // every emoji on the message after the change
+}
+
+// ReactionSummary aggregates one emoji's reactions on a message
+type ReactionSummary struct {
+	Emoji   string `json:"emoji"`
+	Count   int64  `json:"count"`
+	UserIDs []uint `json:"userIds"` // in reaction order
+}
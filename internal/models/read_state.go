@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+/** --------------------ENTITIES-------------------- */
+// ChannelRead is a user's read pointer in a channel: the newest message they have read.
+// The pointer only ever moves forward.
+type ChannelRead struct {
+	ID                uint      `gorm:"primarykey" json:"id"`
+	ChannelID         uint      `gorm:"not null;uniqueIndex:idx_channel_reads_channel_user" json:"channelId"`
+	UserID            uint      `gorm:"not null;uniqueIndex:idx_channel_reads_channel_user" json:"userId"`
+	LastReadMessageID uint      `gorm:"not null" json:"lastReadMessageId"`
+	LastReadAt        time.Time `gorm:"not null" json:"lastReadAt"` // created_at of the last read message
+	UpdatedAt         time.Time `json:"updatedAt"`
+}
+
+/** -------------------- DTOs -------------------- */
+// MarkReadByTimeRequest represents the request for marking everything up to a time as read
+type MarkReadByTimeRequest struct {
+	ReadAt time.Time `json:"readAt" binding:"required"` // RFC 3339
+}
+
+// ReadStateResponse reports a user's read pointer in a channel
+type ReadStateResponse struct {
+	ChannelID         uint       `json:"channelId"`
+	LastReadMessageID *uint      `json:"lastReadMessageId,omitempty"`
+	LastReadAt        *time.Time `json:"lastReadAt,omitempty"`
+	Advanced          bool       `json:"advanced"` // false when the pointer was already at or past the requested point
+}
+
+// ChannelSummaryResponse is a channel's latest message with the caller's unread count
+type ChannelSummaryResponse struct {
+	ChannelID         uint          `json:"channelId"`
+	LatestMessage     *ChatResponse `json:"latestMessage"` // null for an empty channel
+	TotalMessages     int64         `json:"totalMessages"`
+	UnreadCount       int64         `json:"unreadCount"` // messages from others after the caller's read pointer
+	LastReadMessageID *uint         `json:"lastReadMessageId,omitempty"`
+}
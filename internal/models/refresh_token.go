@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+/** --------------------ENTITIES-------------------- */
+// RefreshToken is a long-lived token that can be exchanged for a new access token.
+// Only the SHA-256 hash of the token is stored. Each token is single use: a refresh
+// revokes it and issues a replacement.
+type RefreshToken struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	UserID    uint       `gorm:"not null;index" json:"userId"`
+	TokenHash string     `gorm:"type:char(64);not null;uniqueIndex" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expiresAt"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+
+	// Why the token was revoked, one of the RefreshTokenRevoked* values
+	RevokedReason string `gorm:"type:varchar(16);not null;default:''" json:"revokedReason,omitempty"`
+}
+
+// Reasons a refresh token was revoked. Only a rotated token presented again is
+// treated as leaked; tokens revoked for other reasons are simply invalid.
+const (
+	RefreshTokenRevokedRotated = "rotated" // exchanged by a refresh
+	RefreshTokenRevokedLogout  = "logout"  // the client logged out
+	RefreshTokenRevokedAll     = "revoked" // every session was revoked, e.g. on reuse or a forced logout
+)
+
+/** -------------------- DTOs -------------------- */
+// RefreshTokenRequest carries a refresh token for /auth/refresh and /auth/logout
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+// TokenResponse is a new access token together with its replacement refresh token
+type TokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+}
@@ -16,6 +16,10 @@ type User struct {
 	// Avatar is optional and can be used to store a profile picture URL
 	// It is not mandatory for the user to have an avatar.
 	Avatar string `json:"avatar,omitempty"`
+	// IsAdmin grants access to operational and moderation endpoints
+	IsAdmin bool `gorm:"not null;default:false" json:"-"`
+	// IsBot marks integration identities that post through inbound webhooks and cannot log in
+	IsBot bool `gorm:"not null;default:false" json:"isBot,omitempty"`
 
 	Channels []*Channel `gorm:"many2many:channel_members" json:"channels"`
 }
@@ -43,11 +47,20 @@ type UserResponse struct {
 	Avatar    string    `json:"avatar,omitempty"`
 }
 
+// UserDirectoryPage is one page of the user directory
+type UserDirectoryPage struct {
+	Items   []UserResponse `json:"items"`
+	Limit   int            `json:"limit"`
+	Offset  int            `json:"offset"`
+	HasMore bool           `json:"hasMore"` // another page follows at offset+limit
+}
+
 // LoginResponse represents the response for a successful login
 // swagger:model
 type LoginResponse struct {
-	Token string       `json:"token"`
-	User  UserResponse `json:"user"`
+	Token        string       `json:"token"`
+	RefreshToken string       `json:"refreshToken"` // exchange at /auth/refresh when the token expires
+	User         UserResponse `json:"user"`
 }
 
 // Update user request
@@ -66,3 +79,22 @@ type UpdateProfileRequest struct {
 	Password        *string `json:"password,omitempty" binding:"omitempty,min=6"`
 	CurrentPassword string  `json:"current_password" binding:"required"` // Required current password for verification
 }
+
+// ForceDisconnectRequest represents an optional reason for forcibly disconnecting a user
+type ForceDisconnectRequest struct {
+	Reason string `json:"reason" binding:"omitempty,max=255"`
+}
+
+// MessageQuotaUsage reports a user's message count against the daily quota
+type MessageQuotaUsage struct {
+	Limit    int       `json:"limit"` // 0 when no quota is configured
+	Used     int64     `json:"used"`
+	ResetsAt time.Time `json:"resetsAt"` // next UTC midnight
+	Exempt   bool      `json:"exempt"`   // admins are not limited
+}
+
+// ForceDisconnectResponse reports the result of a forced disconnect
+type ForceDisconnectResponse struct {
+	UserID        uint `json:"userId"`
+	ClosedLocally bool `json:"closedLocally"` // other instances are notified asynchronously
+}
@@ -0,0 +1,86 @@
+package models
+
+import "time"
+
+/** --------------------ENTITIES-------------------- */
+// ChannelWebhook is an outbound integration that receives every new message
+// posted to a channel. Deliveries are signed with Secret.
+type ChannelWebhook struct {
+	ID              uint       `gorm:"primarykey" json:"id"`
+	ChannelID       uint       `gorm:"not null;index" json:"channelId"`
+	URL             string     `gorm:"not null;type:varchar(2048)" json:"url"`
+	Secret          string     `gorm:"not null;type:varchar(128)" json:"-"`
+	Enabled         bool       `gorm:"not null;default:true" json:"enabled"`
+	CreatedBy       uint       `gorm:"not null" json:"createdBy"`
+	LastDeliveredAt *time.Time `json:"lastDeliveredAt,omitempty"`
+	LastError       *string    `gorm:"type:text" json:"lastError,omitempty"`
+	LastErrorAt     *time.Time `json:"lastErrorAt,omitempty"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+}
+
+// InboundWebhook lets an external system post into a channel as a bot user.
+// Its token is signed with the server secret over the webhook, channel and salt;
+// regenerating the salt revokes the old token.
+type InboundWebhook struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	ChannelID uint      `gorm:"not null;index" json:"channelId"`
+	Name      string    `gorm:"not null;type:varchar(50)" json:"name"`
+	BotUserID uint      `gorm:"not null" json:"botUserId"`
+	Salt      string    `gorm:"not null;type:varchar(64)" json:"-"`
+	Enabled   bool      `gorm:"not null;default:true" json:"enabled"`
+	CreatedBy uint      `gorm:"not null" json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+/** -------------------- DTOs -------------------- */
+// CreateChannelWebhookRequest represents the request for adding a channel webhook
+type CreateChannelWebhookRequest struct {
+	URL string `json:"url" binding:"required,url,max=2048"` // https, public hosts only
+}
+
+// UpdateChannelWebhookRequest represents the request for enabling or disabling a webhook
+type UpdateChannelWebhookRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// ChannelWebhookCreatedResponse is returned once on creation and is the only
+// time the signing secret is shown
+type ChannelWebhookCreatedResponse struct {
+	ChannelWebhook
+	Secret string `json:"secret"`
+}
+
+// ChannelMessageWebhookEvent is the payload POSTed to channel webhooks for each new message
+type ChannelMessageWebhookEvent struct {
+	Event       string    `json:"event"`
+	WebhookID   uint      `json:"webhookId"`
+	ChannelID   uint      `json:"channelId"`
+	MessageID   uint      `json:"messageId"`
+	MessageUUID string    `json:"messageUuid,omitempty"`
+	SenderID    uint      `json:"senderId"`
+	SenderName  string    `json:"senderName,omitempty"`
+	Text        *string   `json:"text,omitempty"`
+	URL         *string   `json:"url,omitempty"`
+	FileName    *string   `json:"fileName,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// CreateInboundWebhookRequest represents the request for adding an inbound webhook
+type CreateInboundWebhookRequest struct {
+	Name string `json:"name" binding:"required,min=3,max=40"` // display name of the bot
+}
+
+// InboundWebhookCreatedResponse is returned once on creation and is the only
+// time the token is shown
+type InboundWebhookCreatedResponse struct {
+	InboundWebhook
+	Token string `json:"token"`
+}
+
+// InboundWebhookMessageRequest is the body external systems POST to /webhooks/{token}
+type InboundWebhookMessageRequest struct {
+	ChannelID uint   `json:"channelId" binding:"required"`
+	Text      string `json:"text" binding:"required"` // limited to the configured message size
+}
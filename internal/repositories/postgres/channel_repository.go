@@ -2,8 +2,10 @@ package postgres
 
 import (
 	"chat-service/internal/models"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ChannelRepository struct {
@@ -14,6 +16,11 @@ func NewChannelRepository(db *gorm.DB) *ChannelRepository {
 	return &ChannelRepository{db}
 }
 
+// Transaction runs fn in a database transaction
+func (r *ChannelRepository) Transaction(fn func(tx *gorm.DB) error) error {
+	return r.db.Transaction(fn)
+}
+
 func (r *ChannelRepository) Create(channel *models.Channel) error {
 	return r.db.Create(channel).Error
 }
@@ -53,6 +60,13 @@ func (r *ChannelRepository) GetAllUserChannels(userID uint) ([]models.Channel, e
 	return c, err
 }
 
+// IsArchived reports whether the channel is archived
+func (r *ChannelRepository) IsArchived(channelID uint) (bool, error) {
+	var archived bool
+	err := r.db.Model(&models.Channel{}).Select("archived").Where("id = ?", channelID).Scan(&archived).Error
+	return archived, err
+}
+
 func (r *ChannelRepository) GetByID(channelID uint) (*models.Channel, error) {
 	var c models.Channel
 	err := r.db.Preload("Members", func(db *gorm.DB) *gorm.DB {
@@ -65,6 +79,75 @@ func (r *ChannelRepository) AddUser(channelID uint, userID uint) error {
 	return r.db.Model(&models.Channel{Model: gorm.Model{ID: channelID}}).Association("Members").Append(&models.User{Model: gorm.Model{ID: userID}})
 }
 
+// CountOwnedChannels counts the channels owned by the user, excluding deleted ones
+func (r *ChannelRepository) CountOwnedChannels(ownerID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Channel{}).Where("owner_id = ?", ownerID).Count(&count).Error
+	return count, err
+}
+
+// IsMember reports whether the user belongs to the channel
+func (r *ChannelRepository) IsMember(channelID uint, userID uint) (bool, error) {
+	var count int64
+	err := r.db.Table("channel_members").
+		Where("channel_id = ? AND user_id = ?", channelID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// MemberChannelIDs returns which of the given channels the user belongs to
+func (r *ChannelRepository) MemberChannelIDs(userID uint, channelIDs []uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Table("channel_members").
+		Where("user_id = ? AND channel_id IN ?", userID, channelIDs).
+		Pluck("channel_id", &ids).Error
+	return ids, err
+}
+
+// GetMemberRole returns the stored role of a channel member, or gorm.ErrRecordNotFound
+// if the user is not a member
+func (r *ChannelRepository) GetMemberRole(channelID uint, userID uint) (string, error) {
+	var member models.ChannelMember
+	err := r.db.Where("channel_id = ? AND user_id = ?", channelID, userID).First(&member).Error
+	return member.Role, err
+}
+
+// SetMemberRole updates the stored role of a channel member
+func (r *ChannelRepository) SetMemberRole(channelID uint, userID uint, role string) error {
+	result := r.db.Model(&models.ChannelMember{}).
+		Where("channel_id = ? AND user_id = ?", channelID, userID).
+		Update("role", role)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// SetMute creates or replaces the user's mute for a channel
+func (r *ChannelRepository) SetMute(mute *models.ChannelMute) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "channel_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"muted_until"}),
+	}).Create(mute).Error
+}
+
+// DeleteMute removes the user's mute for a channel
+func (r *ChannelRepository) DeleteMute(channelID uint, userID uint) error {
+	return r.db.Where("channel_id = ? AND user_id = ?", channelID, userID).Delete(&models.ChannelMute{}).Error
+}
+
+// MutedUserIDs returns the users whose mute of the channel is in effect at now
+func (r *ChannelRepository) MutedUserIDs(channelID uint, now time.Time) ([]uint, error) {
+	var userIDs []uint
+	err := r.db.Model(&models.ChannelMute{}).
+		Where("channel_id = ? AND (muted_until IS NULL OR muted_until > ?)", channelID, now).
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
 func (r *ChannelRepository) RemoveUser(channelID uint, userID uint) error {
 	return r.db.Model(&models.Channel{Model: gorm.Model{ID: channelID}}).Association("Members").Delete(&models.User{Model: gorm.Model{ID: userID}})
 }
@@ -78,11 +161,12 @@ func (r *ChannelRepository) GetChatMessages(channelID uint) ([]models.Chat, erro
 	return messages, err
 }
 
-// GetChatMessagesWithPagination returns chat messages for a channel with pagination and time-based infinite scroll
+// GetChatMessagesWithPagination returns chat messages for a channel with pagination and time-based infinite scroll.
+// Deleted messages are included with Deleted set so callers can show a placeholder.
 func (r *ChannelRepository) GetChatMessagesWithPagination(channelID uint, limit int, before *int64) ([]models.ChatResponse, error) {
 	var chatResponses []models.ChatResponse
 	db := r.db.Table("chats").
-		Select(`chats.id, chats.text, chats.sender_id, users.username as sender_name, users.avatar as sender_avatar, chats.url, chats.file_name, chats.created_at, chats.channel_id`).
+		Select(chatResponseColumns).
 		Joins("JOIN users ON users.id = chats.sender_id").
 		Where("chats.channel_id = ?", channelID)
 
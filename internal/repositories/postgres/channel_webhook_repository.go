@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"chat-service/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ChannelWebhookRepository struct {
+	db *gorm.DB
+}
+
+func NewChannelWebhookRepository(db *gorm.DB) *ChannelWebhookRepository {
+	return &ChannelWebhookRepository{db}
+}
+
+func (r *ChannelWebhookRepository) Create(webhook *models.ChannelWebhook) error {
+	return r.db.Create(webhook).Error
+}
+
+func (r *ChannelWebhookRepository) FindByID(id uint) (*models.ChannelWebhook, error) {
+	var webhook models.ChannelWebhook
+	err := r.db.First(&webhook, "id = ?", id).Error
+	return &webhook, err
+}
+
+func (r *ChannelWebhookRepository) ListByChannel(channelID uint) ([]models.ChannelWebhook, error) {
+	var webhooks []models.ChannelWebhook
+	err := r.db.Where("channel_id = ?", channelID).Order("id").Find(&webhooks).Error
+	return webhooks, err
+}
+
+// ListEnabledByChannel returns the webhooks that should receive the channel's messages
+func (r *ChannelWebhookRepository) ListEnabledByChannel(channelID uint) ([]models.ChannelWebhook, error) {
+	var webhooks []models.ChannelWebhook
+	err := r.db.Where("channel_id = ? AND enabled = ?", channelID, true).Find(&webhooks).Error
+	return webhooks, err
+}
+
+func (r *ChannelWebhookRepository) SetEnabled(id uint, enabled bool) error {
+	return r.db.Model(&models.ChannelWebhook{}).Where("id = ?", id).Update("enabled", enabled).Error
+}
+
+func (r *ChannelWebhookRepository) Delete(id uint) error {
+	return r.db.Delete(&models.ChannelWebhook{}, "id = ?", id).Error
+}
+
+// RecordDelivery stores the outcome of the latest delivery attempt
+func (r *ChannelWebhookRepository) RecordDelivery(id uint, deliveryErr error) error {
+	now := time.Now()
+	updates := map[string]interface{}{"last_delivered_at": now}
+	if deliveryErr != nil {
+		updates = map[string]interface{}{"last_error": deliveryErr.Error(), "last_error_at": now}
+	}
+	return r.db.Model(&models.ChannelWebhook{}).Where("id = ?", id).Updates(updates).Error
+}
+
+func (r *ChannelWebhookRepository) CreateInbound(webhook *models.InboundWebhook) error {
+	return r.db.Create(webhook).Error
+}
+
+func (r *ChannelWebhookRepository) FindInboundByID(id uint) (*models.InboundWebhook, error) {
+	var webhook models.InboundWebhook
+	err := r.db.First(&webhook, "id = ?", id).Error
+	return &webhook, err
+}
+
+func (r *ChannelWebhookRepository) ListInboundByChannel(channelID uint) ([]models.InboundWebhook, error) {
+	var webhooks []models.InboundWebhook
+	err := r.db.Where("channel_id = ?", channelID).Order("id").Find(&webhooks).Error
+	return webhooks, err
+}
+
+func (r *ChannelWebhookRepository) DeleteInbound(id uint) error {
+	return r.db.Delete(&models.InboundWebhook{}, "id = ?", id).Error
+}
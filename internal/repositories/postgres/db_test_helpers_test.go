@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"chat-service/internal/database"
+	"chat-service/internal/models"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// newTestDB connects to the Postgres database named by NOTIFY_TEST_DATABASE_URL,
+// migrating the schema, and skips the test when the variable is not set. Tests
+// create their own rows with unique names, so the database can be shared.
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("NOTIFY_TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("NOTIFY_TEST_DATABASE_URL not set")
+	}
+	db, err := database.NewPostgresConnection(dsn)
+	if err != nil {
+		t.Fatalf("connect to test database: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+// createTestUser stores a user with a unique username and email
+func createTestUser(t *testing.T, db *gorm.DB) *models.User {
+	t.Helper()
+	name := "test_" + uuid.New().String()[:8]
+	user := &models.User{Username: name, Email: name + "@example.com"}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user
+}
@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"chat-service/internal/models"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Invite redemption errors
+var (
+	ErrInviteExpired   = errors.New("invite has expired")
+	ErrInviteExhausted = errors.New("invite has reached its maximum uses")
+)
+
+type InviteRepository struct {
+	db *gorm.DB
+}
+
+func NewInviteRepository(db *gorm.DB) *InviteRepository {
+	return &InviteRepository{db}
+}
+
+func (r *InviteRepository) Create(invite *models.Invite) error {
+	return r.db.Create(invite).Error
+}
+
+// FindByToken returns the invite with the token, or gorm.ErrRecordNotFound
+func (r *InviteRepository) FindByToken(token string) (*models.Invite, error) {
+	var invite models.Invite
+	err := r.db.Where("token = ?", token).Take(&invite).Error
+	return &invite, err
+}
+
+// Accept adds the user to the invite's channel as a member and counts the use,
+// reporting whether the user joined. A user who is already a member uses nothing.
+// The use is claimed with a conditional update, so concurrent accepts cannot take
+// an invite past MaxUses or after ExpiresAt.
+func (r *InviteRepository) Accept(invite *models.Invite, userID uint, now time.Time) (bool, error) {
+	joined := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		member := models.ChannelMember{ChannelID: invite.ChannelID, UserID: userID, Role: models.ChannelRoleMember}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&member)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		result = tx.Model(&models.Invite{}).
+			Where("id = ? AND (max_uses = 0 OR uses < max_uses) AND (expires_at IS NULL OR expires_at > ?)", invite.ID, now).
+			UpdateColumn("uses", gorm.Expr("uses + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			// Rolls back the membership
+			if invite.ExpiresAt != nil && !now.Before(*invite.ExpiresAt) {
+				return ErrInviteExpired
+			}
+			return ErrInviteExhausted
+		}
+		joined = true
+		return nil
+	})
+	return joined, err
+}
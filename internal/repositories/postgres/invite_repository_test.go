@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"chat-service/internal/models"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func createTestInvite(t *testing.T, repo *InviteRepository, channelID, createdBy uint, maxUses int, expiresAt *time.Time) *models.Invite {
+	t.Helper()
+	invite := &models.Invite{ChannelID: channelID, Token: uuid.New().String(), CreatedBy: createdBy, MaxUses: maxUses, ExpiresAt: expiresAt}
+	if err := repo.Create(invite); err != nil {
+		t.Fatalf("create invite: %v", err)
+	}
+	return invite
+}
+
+func TestInviteAcceptMaxUsesRace(t *testing.T) {
+	const maxUses, accepters = 3, 12
+
+	db := newTestDB(t)
+	repo := NewInviteRepository(db)
+	owner := createTestUser(t, db)
+	channel := &models.Channel{Name: "invite-race", OwnerID: owner.ID, Type: models.ChannelTypeGroup}
+	if err := db.Create(channel).Error; err != nil {
+		t.Fatalf("create channel: %v", err)
+	}
+	invite := createTestInvite(t, repo, channel.ID, owner.ID, maxUses, nil)
+
+	users := make([]*models.User, accepters)
+	for i := range users {
+		users[i] = createTestUser(t, db)
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		joined    int
+		exhausted int
+		start     = make(chan struct{})
+	)
+	for _, user := range users {
+		wg.Add(1)
+		go func(userID uint) {
+			defer wg.Done()
+			<-start
+			ok, err := repo.Accept(invite, userID, time.Now())
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case errors.Is(err, ErrInviteExhausted):
+				exhausted++
+			case err != nil:
+				t.Errorf("accept: %v", err)
+			case ok:
+				joined++
+			}
+		}(user.ID)
+	}
+	close(start)
+	wg.Wait()
+
+	if joined != maxUses || exhausted != accepters-maxUses {
+		t.Errorf("joined %d and exhausted %d, want %d and %d", joined, exhausted, maxUses, accepters-maxUses)
+	}
+
+	stored, err := repo.FindByToken(invite.Token)
+	if err != nil {
+		t.Fatalf("reload invite: %v", err)
+	}
+	if stored.Uses != maxUses {
+		t.Errorf("uses = %d, want %d", stored.Uses, maxUses)
+	}
+
+	// Rejected accepts must not leave a membership behind
+	var members int64
+	db.Model(&models.ChannelMember{}).Where("channel_id = ?", channel.ID).Count(&members)
+	if members != maxUses {
+		t.Errorf("%d members joined through the invite, want %d", members, maxUses)
+	}
+}
+
+func TestInviteAccept(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewInviteRepository(db)
+	owner := createTestUser(t, db)
+	channel := &models.Channel{Name: "invite", OwnerID: owner.ID, Type: models.ChannelTypeGroup}
+	if err := db.Create(channel).Error; err != nil {
+		t.Fatalf("create channel: %v", err)
+	}
+	past := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name       string
+		maxUses    int
+		expiresAt  *time.Time
+		acceptTwo  bool // the same user accepts twice
+		wantJoined bool
+		wantErr    error
+		wantUses   int
+	}{
+		{"unlimited", 0, nil, false, true, nil, 1},
+		{"expired", 0, &past, false, false, ErrInviteExpired, 0},
+		{"already a member uses nothing", 1, nil, true, false, nil, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invite := createTestInvite(t, repo, channel.ID, owner.ID, tt.maxUses, tt.expiresAt)
+			user := createTestUser(t, db)
+
+			if tt.acceptTwo {
+				if _, err := repo.Accept(invite, user.ID, time.Now()); err != nil {
+					t.Fatalf("first accept: %v", err)
+				}
+			}
+			joined, err := repo.Accept(invite, user.ID, time.Now())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if joined != tt.wantJoined {
+				t.Errorf("joined = %v, want %v", joined, tt.wantJoined)
+			}
+
+			stored, err := repo.FindByToken(invite.Token)
+			if err != nil {
+				t.Fatalf("reload invite: %v", err)
+			}
+			if stored.Uses != tt.wantUses {
+				t.Errorf("uses = %d, want %d", stored.Uses, tt.wantUses)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"chat-service/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MentionRepository struct {
+	db *gorm.DB
+}
+
+func NewMentionRepository(db *gorm.DB) *MentionRepository {
+	return &MentionRepository{db}
+}
+
+// Create stores a message's mentions together with the notifications queued for
+// offline users. Mentions that were already recorded are left as they are.
+func (r *MentionRepository) Create(mentions []models.Mention, notifications []models.MentionNotification) error {
+	if len(mentions) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&mentions).Error; err != nil {
+			return err
+		}
+		if len(notifications) == 0 {
+			return nil
+		}
+		return tx.Create(&notifications).Error
+	})
+}
@@ -2,8 +2,14 @@ package postgres
 
 import (
 	"chat-service/internal/models"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ChatRepository struct {
@@ -18,6 +24,272 @@ func (r *ChatRepository) Create(chat *models.Chat) error {
 	return r.db.Create(chat).Error
 }
 
+// CreateBatch inserts several chats with a single multi-row insert and returns
+// the IDs of the chats that were stored. Chats must carry pre-reserved IDs (see
+// ReserveIDs). A message resent to another instance before the first copy was
+// flushed conflicts on its client_msg_id and is skipped rather than failing the
+// batch; its attachments are skipped with it.
+func (r *ChatRepository) CreateBatch(chats []*models.Chat) (map[uint]bool, error) {
+	if len(chats) == 0 {
+		return map[uint]bool{}, nil
+	}
+
+	ids := make([]uint, len(chats))
+	for i, chat := range chats {
+		ids[i] = chat.ID
+	}
+
+	var inserted map[uint]bool
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(&chats).Error; err != nil {
+			return err
+		}
+
+		// Reserved IDs belong to this batch alone, so any that exist were just inserted
+		var stored []uint
+		if err := tx.Model(&models.Chat{}).Where("id IN ?", ids).Pluck("id", &stored).Error; err != nil {
+			return err
+		}
+		inserted = make(map[uint]bool, len(stored))
+		for _, id := range stored {
+			inserted[id] = true
+		}
+
+		var attachments []models.Attachment
+		for _, chat := range chats {
+			if !inserted[chat.ID] {
+				continue
+			}
+			for _, attachment := range chat.Attachments {
+				attachment.ChatID = chat.ID
+				attachments = append(attachments, attachment)
+			}
+		}
+		if len(attachments) == 0 {
+			return nil
+		}
+		return tx.Create(&attachments).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return inserted, nil
+}
+
+// ReserveIDs allocates n chat IDs from the table sequence so a message can be
+// broadcast with its final ID before it is persisted
+func (r *ChatRepository) ReserveIDs(n int) ([]uint, error) {
+	var ids []uint
+	err := r.db.Raw("SELECT nextval(pg_get_serial_sequence('chats', 'id')) FROM generate_series(1, ?)", n).
+		Scan(&ids).Error
+	return ids, err
+}
+
+// LoadSender populates the Sender association of an unsaved chat
+func (r *ChatRepository) LoadSender(chat *models.Chat) error {
+	return r.db.First(&chat.Sender, chat.SenderID).Error
+}
+
+// Sentinels ts_headline puts around matches. They are control characters that
+// are stripped from the message text first, so only real matches carry them.
+const (
+	snippetStartSel = "\x02"
+	snippetStopSel  = "\x03"
+)
+
+var snippetMarks = strings.NewReplacer(snippetStartSel, "<mark>", snippetStopSel, "</mark>")
+
+// highlightSnippet HTML-escapes a ts_headline snippet and turns the match
+// sentinels into <mark> tags, so message text cannot inject markup
+func highlightSnippet(snippet string) string {
+	return snippetMarks.Replace(html.EscapeString(snippet))
+}
+
+// Search runs a full-text search over the messages of channels the user belongs to.
+// Matches are highlighted with ts_headline, limited to maxWords per snippet.
+// Snippets are HTML-escaped, with matches wrapped in <mark> tags.
+func (r *ChatRepository) Search(userID uint, query string, channelID *uint, limit, maxWords int) ([]models.MessageSearchResult, error) {
+	minWords := maxWords / 2
+	if minWords < 1 {
+		minWords = 1
+	}
+	headlineOpts := fmt.Sprintf(`StartSel="%s", StopSel="%s", MaxWords=%d, MinWords=%d, MaxFragments=1`,
+		snippetStartSel, snippetStopSel, maxWords, minWords)
+
+	db := r.db.Table("chats").
+		Select(`chats.id, chats.uuid, chats.channel_id, chats.sender_id, users.username AS sender_name, chats.created_at,
+			ts_headline('simple', translate(chats.text, ?, ''), plainto_tsquery('simple', ?), ?) AS snippet,
+			ts_rank(to_tsvector('simple', coalesce(chats.text, '')), plainto_tsquery('simple', ?)) AS rank`,
+			snippetStartSel+snippetStopSel, query, headlineOpts, query).
+		Joins("JOIN users ON users.id = chats.sender_id").
+		Joins("JOIN channel_members ON channel_members.channel_id = chats.channel_id AND channel_members.user_id = ?", userID).
+		Where("chats.deleted_at IS NULL").
+		Where("to_tsvector('simple', coalesce(chats.text, '')) @@ plainto_tsquery('simple', ?)", query)
+
+	if channelID != nil {
+		db = db.Where("chats.channel_id = ?", *channelID)
+	}
+
+	var results []models.MessageSearchResult
+	if err := db.Order("rank DESC, chats.created_at DESC").Limit(limit).Scan(&results).Error; err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i].Snippet = highlightSnippet(results[i].Snippet)
+	}
+	return results, nil
+}
+
+// CountByChannel returns the number of messages in a channel
+func (r *ChatRepository) CountByChannel(channelID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Chat{}).Where("channel_id = ?", channelID).Count(&count).Error
+	return count, err
+}
+
+// CountTotalAndUnread counts a channel's messages in one aggregate query. Unread
+// messages are those after afterID that were not sent by userID.
+func (r *ChatRepository) CountTotalAndUnread(channelID, userID, afterID uint) (total, unread int64, err error) {
+	var row struct {
+		Total  int64
+		Unread int64
+	}
+	err = r.db.Table("chats").
+		Select("COUNT(*) AS total, COUNT(*) FILTER (WHERE id > ? AND sender_id <> ?) AS unread", afterID, userID).
+		Where("channel_id = ? AND deleted_at IS NULL", channelID).
+		Scan(&row).Error
+	return row.Total, row.Unread, err
+}
+
+// TopSenders returns the channel members who sent the most messages
+func (r *ChatRepository) TopSenders(channelID uint, limit int) ([]models.MemberActivity, error) {
+	var results []models.MemberActivity
+	err := r.db.Table("chats").
+		Select("chats.sender_id AS user_id, users.username, COUNT(*) AS message_count").
+		Joins("JOIN users ON users.id = chats.sender_id").
+		Where("chats.channel_id = ? AND chats.deleted_at IS NULL", channelID).
+		Group("chats.sender_id, users.username").
+		Order("message_count DESC, chats.sender_id").
+		Limit(limit).
+		Scan(&results).Error
+	return results, err
+}
+
+// BusiestHour returns the UTC hour of day with the most messages in a channel.
+// ok is false when the channel has no messages.
+func (r *ChatRepository) BusiestHour(channelID uint) (hour int, count int64, ok bool, err error) {
+	var row struct {
+		Hour  int
+		Count int64
+	}
+	res := r.db.Table("chats").
+		Select("EXTRACT(HOUR FROM created_at AT TIME ZONE 'UTC')::int AS hour, COUNT(*) AS count").
+		Where("channel_id = ? AND deleted_at IS NULL", channelID).
+		Group("hour").
+		Order("count DESC, hour").
+		Limit(1).
+		Scan(&row)
+	if res.Error != nil {
+		return 0, 0, false, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return 0, 0, false, nil
+	}
+	return row.Hour, row.Count, true, nil
+}
+
+// LatestAtOrBefore returns the newest message in a channel created at or before t
+func (r *ChatRepository) LatestAtOrBefore(channelID uint, t time.Time) (*models.Chat, error) {
+	var chat models.Chat
+	err := r.db.Where("channel_id = ? AND created_at <= ?", channelID, t).
+		Order("created_at DESC, id DESC").
+		First(&chat).Error
+	return &chat, err
+}
+
+// Columns selected into models.ChatResponse, joined with the sender
+const chatResponseColumns = `chats.id, chats.uuid, chats.text, chats.sender_id, users.username as sender_name, users.avatar as sender_avatar, chats.url, chats.file_name, chats.created_at, chats.channel_id, chats.forwarded_from, chats.parent_id, chats.edited_at, chats.deleted_at IS NOT NULL as deleted`
+
+// ListThread returns a message and its replies, oldest first. Deleted messages
+// are included with Deleted set, like ListChannelPage.
+func (r *ChatRepository) ListThread(parentID uint) ([]models.ChatResponse, error) {
+	var thread []models.ChatResponse
+	err := r.db.Unscoped().Model(&models.Chat{}).
+		Select(chatResponseColumns).
+		Joins("JOIN users ON users.id = chats.sender_id").
+		Where("chats.id = ? OR chats.parent_id = ?", parentID, parentID).
+		Order("chats.created_at, chats.id").
+		Scan(&thread).Error
+	if err != nil {
+		return nil, err
+	}
+	return thread, r.loadAttachments(thread)
+}
+
+// ListChannelAfter returns up to limit messages in a channel with an ID above
+// afterID, oldest first. Deleted messages are included with Deleted set, like
+// ListChannelPage.
+func (r *ChatRepository) ListChannelAfter(channelID, afterID uint, limit int) ([]models.ChatResponse, error) {
+	var items []models.ChatResponse
+	err := r.db.Unscoped().Model(&models.Chat{}).
+		Select(chatResponseColumns).
+		Joins("JOIN users ON users.id = chats.sender_id").
+		Where("chats.channel_id = ? AND chats.id > ?", channelID, afterID).
+		Order("chats.id").
+		Limit(limit).
+		Scan(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, r.loadAttachments(items)
+}
+
+// loadAttachments fills in the attachments of the listed messages with one query
+func (r *ChatRepository) loadAttachments(items []models.ChatResponse) error {
+	if len(items) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+
+	var attachments []models.Attachment
+	if err := r.db.Where("chat_id IN ?", ids).Order("id").Find(&attachments).Error; err != nil {
+		return err
+	}
+	byChat := make(map[uint][]models.Attachment)
+	for _, attachment := range attachments {
+		byChat[attachment.ChatID] = append(byChat[attachment.ChatID], attachment)
+	}
+	for i := range items {
+		items[i].Attachments = byChat[items[i].ID]
+	}
+	return nil
+}
+
+// ListChannelPage returns up to limit messages in a channel, newest first. When
+// before is set only messages older than it are returned; ties on created_at are
+// broken by ID so pages never overlap. Deleted messages are included with Deleted
+// set so callers can show a placeholder in their place.
+func (r *ChatRepository) ListChannelPage(channelID uint, before *models.Chat, limit int) ([]models.ChatResponse, error) {
+	var page []models.ChatResponse
+	db := r.db.Unscoped().Model(&models.Chat{}).
+		Select(chatResponseColumns).
+		Joins("JOIN users ON users.id = chats.sender_id").
+		Where("chats.channel_id = ?", channelID)
+	if before != nil {
+		db = db.Where("(chats.created_at, chats.id) < (?, ?)", before.CreatedAt, before.ID)
+	}
+	err := db.Order("chats.created_at DESC, chats.id DESC").
+		Limit(limit).
+		Scan(&page).Error
+	if err != nil {
+		return nil, err
+	}
+	return page, r.loadAttachments(page)
+}
+
 func (r *ChatRepository) GetFriendMessages(userID, friendID uint) ([]*models.Chat, error) {
 	var chats []*models.Chat
 	err := r.db.Where("(sender_id = ? AND receiver_id = ?) OR (sender_id = ? AND receiver_id = ?)",
@@ -27,9 +299,24 @@ func (r *ChatRepository) GetFriendMessages(userID, friendID uint) ([]*models.Cha
 	return chats, err
 }
 
+// FindByClientMsgID finds the message a sender tagged with the given client_msg_id
+func (r *ChatRepository) FindByClientMsgID(senderID uint, clientMsgID string) (*models.Chat, error) {
+	var chat models.Chat
+	err := r.db.Preload("Sender").Preload("Attachments").
+		First(&chat, "sender_id = ? AND client_msg_id = ?", senderID, clientMsgID).Error
+	return &chat, err
+}
+
 func (r *ChatRepository) FindByID(id uint) (*models.Chat, error) {
 	var chat models.Chat
-	err := r.db.Preload("Sender").First(&chat, "id = ?", id).Error
+	err := r.db.Preload("Sender").Preload("Attachments").First(&chat, "id = ?", id).Error
+	return &chat, err
+}
+
+// FindByIDWithDeleted loads a message even if it was soft-deleted
+func (r *ChatRepository) FindByIDWithDeleted(id uint) (*models.Chat, error) {
+	var chat models.Chat
+	err := r.db.Unscoped().First(&chat, "id = ?", id).Error
 	return &chat, err
 }
 
@@ -51,6 +338,93 @@ func (r *ChatRepository) FindByFriendID(friendID uint) ([]*models.Chat, error) {
 	return chats, err
 }
 
+// UpdateText replaces a message's text and stamps edited_at
+func (r *ChatRepository) UpdateText(chat *models.Chat, text string, editedAt time.Time) error {
+	return r.db.Model(chat).Updates(map[string]interface{}{
+		"text":      text,
+		"edited_at": editedAt,
+	}).Error
+}
+
 func (r *ChatRepository) Delete(id uint) error {
 	return r.db.Delete(&models.Chat{}, "id = ?", id).Error
 }
+
+// ErrPinLimitReached is returned when pinning would exceed the channel's pin limit
+var ErrPinLimitReached = errors.New("channel has reached its pin limit")
+
+// PinMessage pins a message in its channel and reports whether a new pin was
+// created. Pinning an already pinned message is a no-op that fills pin with the
+// existing pin. The channel row is locked so concurrent pins cannot exceed limit.
+// Pins of deleted messages do not count against the limit.
+func (r *ChatRepository) PinMessage(pin *models.PinnedMessage, limit int) (bool, error) {
+	var pinned bool
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT id FROM channels WHERE id = ? FOR UPDATE", pin.ChannelID).Error; err != nil {
+			return err
+		}
+
+		var existing models.PinnedMessage
+		err := tx.Where("channel_id = ? AND chat_id = ?", pin.ChannelID, pin.ChatID).Take(&existing).Error
+		if err == nil {
+			*pin = existing
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		var count int64
+		err = tx.Model(&models.PinnedMessage{}).
+			Joins("JOIN chats ON chats.id = pinned_messages.chat_id AND chats.deleted_at IS NULL").
+			Where("pinned_messages.channel_id = ?", pin.ChannelID).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count >= int64(limit) {
+			return ErrPinLimitReached
+		}
+
+		if err := tx.Create(pin).Error; err != nil {
+			return err
+		}
+		pinned = true
+		return nil
+	})
+	return pinned, err
+}
+
+// UnpinMessage removes a message's pin and reports whether it was pinned
+func (r *ChatRepository) UnpinMessage(channelID, chatID uint) (bool, error) {
+	result := r.db.Where("channel_id = ? AND chat_id = ?", channelID, chatID).Delete(&models.PinnedMessage{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ListPins returns the channel's pinned messages, most recently pinned first.
+// Pins of deleted messages are left out.
+func (r *ChatRepository) ListPins(channelID uint) ([]models.PinnedMessageResponse, error) {
+	var pins []models.PinnedMessageResponse
+	err := r.db.Model(&models.Chat{}).
+		Select(chatResponseColumns+", pinned_messages.pinned_by, pinned_messages.pinned_at").
+		Joins("JOIN pinned_messages ON pinned_messages.chat_id = chats.id").
+		Joins("JOIN users ON users.id = chats.sender_id").
+		Where("pinned_messages.channel_id = ?", channelID).
+		Order("pinned_messages.pinned_at DESC, chats.id DESC").
+		Scan(&pins).Error
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]models.ChatResponse, len(pins))
+	for i := range pins {
+		items[i] = pins[i].ChatResponse
+	}
+	if err := r.loadAttachments(items); err != nil {
+		return nil, err
+	}
+	for i := range pins {
+		pins[i].Attachments = items[i].Attachments
+	}
+	return pins, nil
+}
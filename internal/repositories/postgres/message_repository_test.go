@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"chat-service/internal/models"
+	"strings"
+	"testing"
+)
+
+func TestHighlightSnippet(t *testing.T) {
+	tests := []struct {
+		name    string
+		snippet string
+		want    string
+	}{
+		{"match", "say \x02hello\x03 world", "say <mark>hello</mark> world"},
+		{"markup escaped", "<script>\x02hello\x03</script>", "&lt;script&gt;<mark>hello</mark>&lt;/script&gt;"},
+		{"literal mark tags escaped", "<mark>x</mark> \x02hello\x03", "&lt;mark&gt;x&lt;/mark&gt; <mark>hello</mark>"},
+		{"no match", "a & b", "a &amp; b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlightSnippet(tt.snippet); got != tt.want {
+				t.Fatalf("highlightSnippet(%q) = %q, want %q", tt.snippet, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchSnippetMarksQueryTerm(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewChatRepository(db)
+	user := createTestUser(t, db)
+	channel := &models.Channel{Name: "search", OwnerID: user.ID, Type: models.ChannelTypeGroup}
+	if err := db.Create(channel).Error; err != nil {
+		t.Fatalf("create channel: %v", err)
+	}
+	if err := db.Create(&models.ChannelMember{ChannelID: channel.ID, UserID: user.ID}).Error; err != nil {
+		t.Fatalf("add member: %v", err)
+	}
+	text := `<img src=x onerror=alert(1)> needle here`
+	if err := db.Create(&models.Chat{SenderID: user.ID, ChannelID: channel.ID, Text: &text}).Error; err != nil {
+		t.Fatalf("create message: %v", err)
+	}
+
+	results, err := repo.Search(user.ID, "needle", &channel.ID, 10, 20)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	snippet := results[0].Snippet
+	if !strings.Contains(snippet, "<mark>needle</mark>") {
+		t.Fatalf("snippet %q does not mark the query term", snippet)
+	}
+	if strings.Contains(snippet, "<img") {
+		t.Fatalf("snippet %q contains unescaped markup", snippet)
+	}
+}
@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"chat-service/internal/models"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Rows per INSERT when recording last seen for many users
+const lastSeenBatchSize = 500
+
+type PresenceRepository struct {
+	db *gorm.DB
+}
+
+func NewPresenceRepository(db *gorm.DB) *PresenceRepository {
+	return &PresenceRepository{db}
+}
+
+// TouchLastSeen records that the users were seen at the given time. A stored
+// time is never moved backwards, so a late write cannot hide a newer one.
+func (r *PresenceRepository) TouchLastSeen(userIDs []uint, seenAt time.Time) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	rows := make([]models.UserPresence, len(userIDs))
+	for i, userID := range userIDs {
+		rows[i] = models.UserPresence{UserID: userID, LastSeenAt: seenAt}
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"last_seen_at": gorm.Expr("GREATEST(user_presences.last_seen_at, excluded.last_seen_at)"),
+		}),
+	}).CreateInBatches(&rows, lastSeenBatchSize).Error
+}
+
+// GetLastSeen returns when the user was last seen, or nil if never recorded
+func (r *PresenceRepository) GetLastSeen(userID uint) (*time.Time, error) {
+	var presence models.UserPresence
+	err := r.db.Where("user_id = ?", userID).Take(&presence).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &presence.LastSeenAt, nil
+}
@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"chat-service/internal/models"
+	"sort"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReactionRepository struct {
+	db *gorm.DB
+}
+
+func NewReactionRepository(db *gorm.DB) *ReactionRepository {
+	return &ReactionRepository{db}
+}
+
+// Apply adds or removes the reaction and returns whether anything changed along
+// with the message's reactions afterwards. The message row is locked for the
+// duration, so concurrent reactions to one message apply one at a time and each
+// summary reflects exactly the state its own change produced. Adding an existing
+// reaction or removing a missing one is a no-op.
+func (r *ReactionRepository) Apply(op string, reaction *models.Reaction) (bool, []models.ReactionSummary, error) {
+	var changed bool
+	var summary []models.ReactionSummary
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT id FROM chats WHERE id = ? FOR UPDATE", reaction.ChatID).Error; err != nil {
+			return err
+		}
+
+		var result *gorm.DB
+		if op == models.ReactionOpAdd {
+			result = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(reaction)
+		} else {
+			result = tx.Where("chat_id = ? AND user_id = ? AND emoji = ?", reaction.ChatID, reaction.UserID, reaction.Emoji).
+				Delete(&models.Reaction{})
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+		changed = result.RowsAffected > 0
+
+		var err error
+		summary, err = reactionSummary(tx, reaction.ChatID)
+		return err
+	})
+	return changed, summary, err
+}
+
+// GetReactionSummary returns the count and reacting users of every emoji on a
+// message, most used first
+func (r *ReactionRepository) GetReactionSummary(chatID uint) ([]models.ReactionSummary, error) {
+	return reactionSummary(r.db, chatID)
+}
+
+func reactionSummary(db *gorm.DB, chatID uint) ([]models.ReactionSummary, error) {
+	var reactions []models.Reaction
+	err := db.Select("emoji", "user_id").
+		Where("chat_id = ?", chatID).
+		Order("created_at, id").
+		Find(&reactions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	summary := []models.ReactionSummary{}
+	index := make(map[string]int)
+	for _, reaction := range reactions {
+		i, ok := index[reaction.Emoji]
+		if !ok {
+			i = len(summary)
+			index[reaction.Emoji] = i
+			summary = append(summary, models.ReactionSummary{Emoji: reaction.Emoji})
+		}
+		summary[i].Count++
+		summary[i].UserIDs = append(summary[i].UserIDs, reaction.UserID)
+	}
+	sort.SliceStable(summary, func(i, j int) bool {
+		return summary[i].Count > summary[j].Count
+	})
+	return summary, nil
+}
+
+// TopEmojisByChannel returns the most used reaction emojis on a channel's messages
+func (r *ReactionRepository) TopEmojisByChannel(channelID uint, limit int) ([]models.ReactionUsage, error) {
+	var results []models.ReactionUsage
+	err := r.db.Table("reactions").
+		Select("reactions.emoji, COUNT(*) AS count").
+		Joins("JOIN chats ON chats.id = reactions.chat_id").
+		Where("chats.channel_id = ? AND chats.deleted_at IS NULL", channelID).
+		Group("reactions.emoji").
+		Order("count DESC, reactions.emoji").
+		Limit(limit).
+		Scan(&results).Error
+	return results, err
+}
@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"chat-service/internal/models"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReadStateRepository struct {
+	db *gorm.DB
+}
+
+func NewReadStateRepository(db *gorm.DB) *ReadStateRepository {
+	return &ReadStateRepository{db}
+}
+
+// Advance moves the user's read pointer to the given message, creating it if needed.
+// The pointer is never moved backwards; the result reports whether it changed.
+func (r *ReadStateRepository) Advance(read *models.ChannelRead) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "channel_id"}, {Name: "user_id"}},
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "channel_reads.last_read_message_id < excluded.last_read_message_id"},
+		}},
+		DoUpdates: clause.AssignmentColumns([]string{"last_read_message_id", "last_read_at", "updated_at"}),
+	}).Create(read)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Get returns the user's read pointer in a channel, or nil if they have not read anything
+func (r *ReadStateRepository) Get(channelID, userID uint) (*models.ChannelRead, error) {
+	var read models.ChannelRead
+	err := r.db.Where("channel_id = ? AND user_id = ?", channelID, userID).First(&read).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &read, nil
+}
+
+// ListByChannel returns every user's read pointer in a channel
+func (r *ReadStateRepository) ListByChannel(channelID uint) ([]models.ChannelRead, error) {
+	var reads []models.ChannelRead
+	err := r.db.Where("channel_id = ?", channelID).Order("user_id").Find(&reads).Error
+	return reads, err
+}
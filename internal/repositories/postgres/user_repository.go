@@ -2,12 +2,16 @@ package postgres
 
 import (
 	"chat-service/internal/models"
+	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository struct {
@@ -179,6 +183,20 @@ func (r *UserRepository) GetFriendsByChannelID(channelID uint, userId uint) ([]m
 	return users, nil
 }
 
+// FindChannelMemberIDsByUsernames returns the IDs of the channel's members whose
+// username is one of usernames. Names that match no member are skipped.
+func (r *UserRepository) FindChannelMemberIDsByUsernames(channelID uint, usernames []string) ([]uint, error) {
+	var ids []uint
+	err := r.db.Table("users").
+		Joins("JOIN channel_members ON channel_members.user_id = users.id").
+		Where("channel_members.channel_id = ? AND users.username IN ? AND users.deleted_at IS NULL", channelID, usernames).
+		Pluck("users.id", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find channel members by username: %w", err)
+	}
+	return ids, nil
+}
+
 // SearchUsersByUsername searches for users by username (partial match)
 func (r *UserRepository) SearchUsersByUsername(username string) ([]models.User, error) {
 	var users []models.User
@@ -190,3 +208,153 @@ func (r *UserRepository) SearchUsersByUsername(username string) ([]models.User,
 	}
 	return users, nil
 }
+
+// SearchUsers lists users whose username or email contains query, ignoring case.
+// Prefix matches come first. Users the caller has blocked are left out; an empty
+// query lists everyone.
+func (r *UserRepository) SearchUsers(ctx context.Context, callerID uint, query string, limit, offset int) ([]models.User, error) {
+	pattern := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(query)
+
+	var users []models.User
+	err := r.db.WithContext(ctx).
+		Where("username ILIKE ? OR email ILIKE ?", "%"+pattern+"%", "%"+pattern+"%").
+		Where("id NOT IN (SELECT blocked_id FROM blocked_users WHERE blocker_id = ?)", callerID).
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:  "CASE WHEN username ILIKE ? OR email ILIKE ? THEN 0 ELSE 1 END, username, id",
+			Vars: []interface{}{pattern + "%", pattern + "%"},
+		}}).
+		Limit(limit).
+		Offset(offset).
+		Find(&users).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to search users: %w", err)
+	}
+	return users, nil
+}
+
+// ErrSelfBlock is returned when a user tries to block themselves
+var ErrSelfBlock = errors.New("users cannot block themselves")
+
+// BlockUser records that the blocker does not want direct messages from the blocked
+// user. Blocking someone who is already blocked is a no-op.
+func (r *UserRepository) BlockUser(ctx context.Context, blockerEmail, blockedEmail string) error {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	tx, err := sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	blockerID, err := userIDByEmail(ctx, tx, blockerEmail)
+	if err != nil {
+		return err
+	}
+	blockedID, err := userIDByEmail(ctx, tx, blockedEmail)
+	if err != nil {
+		return err
+	}
+	if blockerID == blockedID {
+		return ErrSelfBlock
+	}
+
+	query := `
+		INSERT INTO blocked_users (blocker_id, blocked_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (blocker_id, blocked_id) DO NOTHING
+	`
+	if _, err := tx.ExecContext(ctx, query, blockerID, blockedID, time.Now()); err != nil {
+		return fmt.Errorf("failed to block user: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// UnblockUser removes a block. Unblocking someone who is not blocked is a no-op.
+func (r *UserRepository) UnblockUser(ctx context.Context, blockerEmail, blockedEmail string) error {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	tx, err := sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	blockerID, err := userIDByEmail(ctx, tx, blockerEmail)
+	if err != nil {
+		return err
+	}
+	blockedID, err := userIDByEmail(ctx, tx, blockedEmail)
+	if err != nil {
+		return err
+	}
+
+	query := `DELETE FROM blocked_users WHERE blocker_id = $1 AND blocked_id = $2`
+	if _, err := tx.ExecContext(ctx, query, blockerID, blockedID); err != nil {
+		return fmt.Errorf("failed to unblock user: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// IsBlocked reports whether blockerID has blocked blockedID
+func (r *UserRepository) IsBlocked(ctx context.Context, blockerID, blockedID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.BlockedUser{}).
+		Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// IsBlockedInDirectChannel reports whether another member of a direct channel has
+// blocked the sender. Group channels are never affected by blocks.
+func (r *UserRepository) IsBlockedInDirectChannel(ctx context.Context, channelID, senderID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Table("blocked_users").
+		Joins("JOIN channel_members ON channel_members.user_id = blocked_users.blocker_id").
+		Joins("JOIN channels ON channels.id = channel_members.channel_id").
+		Where("channels.id = ? AND channels.type = ? AND channels.deleted_at IS NULL", channelID, models.ChannelTypeDirect).
+		Where("blocked_users.blocked_id = ?", senderID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ListBlocked returns the users the given user has blocked, most recent first
+func (r *UserRepository) ListBlocked(ctx context.Context, blockerEmail string) ([]models.User, error) {
+	var users []models.User
+	err := r.db.WithContext(ctx).Table("users").
+		Joins("JOIN blocked_users ON blocked_users.blocked_id = users.id").
+		Joins("JOIN users AS blockers ON blockers.id = blocked_users.blocker_id").
+		Where("blockers.email = ? AND blockers.deleted_at IS NULL AND users.deleted_at IS NULL", blockerEmail).
+		Order("blocked_users.created_at DESC").
+		Find(&users).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blocked users: %w", err)
+	}
+	return users, nil
+}
+
+// userIDByEmail resolves an active user's ID inside a transaction
+func userIDByEmail(ctx context.Context, tx *sql.Tx, email string) (uint, error) {
+	var id uint
+	err := tx.QueryRowContext(ctx, `SELECT id FROM users WHERE email = $1 AND deleted_at IS NULL`, email).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, gorm.ErrRecordNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to find user: %w", err)
+	}
+	return id, nil
+}
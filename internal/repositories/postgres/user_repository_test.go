@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"chat-service/internal/models"
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestBlockUserIdempotent(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+	alice, bob := createTestUser(t, db), createTestUser(t, db)
+
+	blockCount := func() int64 {
+		var n int64
+		db.Model(&models.BlockedUser{}).Where("blocker_id = ? AND blocked_id = ?", alice.ID, bob.ID).Count(&n)
+		return n
+	}
+
+	steps := []struct {
+		name      string
+		run       func() error
+		wantCount int64
+	}{
+		{"block", func() error { return repo.BlockUser(ctx, alice.Email, bob.Email) }, 1},
+		{"block again", func() error { return repo.BlockUser(ctx, alice.Email, bob.Email) }, 1},
+		{"unblock", func() error { return repo.UnblockUser(ctx, alice.Email, bob.Email) }, 0},
+		{"unblock again", func() error { return repo.UnblockUser(ctx, alice.Email, bob.Email) }, 0},
+		{"block after unblock", func() error { return repo.BlockUser(ctx, alice.Email, bob.Email) }, 1},
+	}
+
+	// Steps build on each other, so they run in order and stop at the first failure
+	for _, step := range steps {
+		if err := step.run(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if got := blockCount(); got != step.wantCount {
+			t.Fatalf("%s: %d block rows, want %d", step.name, got, step.wantCount)
+		}
+	}
+
+	// Blocks are one-way
+	if blocked, err := repo.IsBlocked(ctx, alice.ID, bob.ID); err != nil || !blocked {
+		t.Errorf("IsBlocked(alice, bob) = %v, %v; want true", blocked, err)
+	}
+	if blocked, err := repo.IsBlocked(ctx, bob.ID, alice.ID); err != nil || blocked {
+		t.Errorf("IsBlocked(bob, alice) = %v, %v; want false", blocked, err)
+	}
+}
+
+func TestBlockUserErrors(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+	alice := createTestUser(t, db)
+
+	tests := []struct {
+		name    string
+		run     func() error
+		wantErr error
+	}{
+		{"block self", func() error { return repo.BlockUser(ctx, alice.Email, alice.Email) }, ErrSelfBlock},
+		{"block unknown user", func() error { return repo.BlockUser(ctx, alice.Email, "nobody@example.invalid") }, gorm.ErrRecordNotFound},
+		{"unblock unknown user", func() error { return repo.UnblockUser(ctx, alice.Email, "nobody@example.invalid") }, gorm.ErrRecordNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
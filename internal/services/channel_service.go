@@ -5,26 +5,107 @@ import (
 	"chat-service/internal/repositories/postgres"
 	"errors"
 	"fmt"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+// Channel errors
+var (
+	ErrChannelLimitReached = errors.New("channel limit reached")
+	ErrInsufficientRole    = errors.New("insufficient channel role")
+	ErrCannotRemoveOwner   = errors.New("cannot remove channel owner")
+	ErrOwnerRoleFixed      = errors.New("channel owner's role cannot be changed")
+	ErrChannelArchived     = errors.New("channel is archived")
+	ErrInvalidSlowMode     = errors.New("invalid slow mode interval")
+)
+
+// Rank of each channel role, higher outranks lower. Non-members rank 0.
+var channelRoleRank = map[string]int{
+	models.ChannelRoleMember: 1,
+	models.ChannelRoleAdmin:  2,
+	models.ChannelRoleOwner:  3,
+}
+
+// memberRole returns the user's effective role in the channel, or "" if they are not a member
+func memberRole(repo *postgres.ChannelRepository, channel *models.Channel, userID uint) (string, error) {
+	if channel.OwnerID == userID {
+		return models.ChannelRoleOwner, nil
+	}
+	role, err := repo.GetMemberRole(channel.ID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get member role: %w", err)
+	}
+	return role, nil
+}
+
+// requireRole loads the channel and checks the user holds at least the given role
+func (s *ChannelService) requireRole(channelID, userID uint, role string) (*models.Channel, string, error) {
+	return requireChannelRole(s.repo, channelID, userID, role)
+}
+
+// requireChannelRole loads the channel and checks the user holds at least role in
+// it, returning ErrInsufficientRole otherwise. It is shared by the services that
+// gate channel settings on the channel's own roles.
+func requireChannelRole(repo *postgres.ChannelRepository, channelID, userID uint, role string) (*models.Channel, string, error) {
+	channel, err := repo.GetByID(channelID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", ErrChannelNotFound
+		}
+		return nil, "", fmt.Errorf("failed to find channel: %w", err)
+	}
+	userRole, err := memberRole(repo, channel, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if channelRoleRank[userRole] < channelRoleRank[role] {
+		return nil, "", ErrInsufficientRole
+	}
+	return channel, userRole, nil
+}
+
 type ChannelService struct {
 	repo     *postgres.ChannelRepository
 	userRepo *postgres.UserRepository
+
+	// Maximum channels a user may own, 0 for unlimited
+	maxOwnedChannels int
+}
+
+func NewChannelService(repo *postgres.ChannelRepository, userRepo *postgres.UserRepository, maxOwnedChannels int) *ChannelService {
+	return &ChannelService{repo, userRepo, maxOwnedChannels}
 }
 
-func NewChannelService(repo *postgres.ChannelRepository, userRepo *postgres.UserRepository) *ChannelService {
-	return &ChannelService{repo, userRepo}
+// checkOwnedChannelLimit rejects channel creation once the owner reached the configured limit
+func (s *ChannelService) checkOwnedChannelLimit(ownerID uint) error {
+	if s.maxOwnedChannels <= 0 {
+		return nil
+	}
+	count, err := s.repo.CountOwnedChannels(ownerID)
+	if err != nil {
+		return fmt.Errorf("failed to count owned channels: %w", err)
+	}
+	if count >= int64(s.maxOwnedChannels) {
+		return fmt.Errorf("%w: a user can own at most %d channels", ErrChannelLimitReached, s.maxOwnedChannels)
+	}
+	return nil
 }
 
-// Refactored: GetAllChannel returns user's channels separated by type (direct/group)
-func (s *ChannelService) GetAllChannel(userID uint) (direct []models.DirectChannelResponse, group []models.ChannelResponse, err error) {
+// Refactored: GetAllChannel returns user's channels separated by type (direct/group).
+// Archived channels are left out unless includeArchived is set.
+func (s *ChannelService) GetAllChannel(userID uint, includeArchived bool) (direct []models.DirectChannelResponse, group []models.ChannelResponse, err error) {
 	channels, err := s.repo.GetAllUserChannels(userID)
 	if err != nil {
 		return nil, nil, err
 	}
 	for _, channel := range channels {
+		if channel.Archived && !includeArchived {
+			continue
+		}
 		if channel.Type == models.ChannelTypeDirect {
 			resp, err := s.buildDirectChannelResponse(&channel, userID)
 			if err != nil {
@@ -37,6 +118,9 @@ func (s *ChannelService) GetAllChannel(userID uint) (direct []models.DirectChann
 				Name:    channel.Name,
 				Type:    channel.Type,
 				OwnerID: channel.OwnerID,
+
+				SlowModeSeconds: channel.SlowModeSeconds,
+				Archived:        channel.Archived,
 			}
 			group = append(group, resp)
 		}
@@ -75,6 +159,9 @@ func (s *ChannelService) buildDirectChannelResponse(channel *models.Channel, use
 		Avatar:  avatar,
 		Type:    channel.Type,
 		OwnerID: channel.OwnerID,
+
+		SlowModeSeconds: channel.SlowModeSeconds,
+		Archived:        channel.Archived,
 	}
 	return resp, nil
 }
@@ -83,9 +170,12 @@ func (s *ChannelService) CreateChannel(name string, ownerID uint, chanType strin
 	owner, err := s.userRepo.FindByID(ownerID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("owner not found")
+			return nil, ErrUserNotFound
 		}
-		return nil, errors.New("failed to find owner: " + err.Error())
+		return nil, fmt.Errorf("failed to find owner: %w", err)
+	}
+	if err := s.checkOwnedChannelLimit(ownerID); err != nil {
+		return nil, err
 	}
 	channel := &models.Channel{
 		Name:    name,
@@ -103,9 +193,12 @@ func (s *ChannelService) CreateChannelWithUsers(name string, ownerID uint, chanT
 	_, err := s.userRepo.FindByID(ownerID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("owner not found")
+			return nil, ErrUserNotFound
 		}
-		return nil, errors.New("failed to find owner: " + err.Error())
+		return nil, fmt.Errorf("failed to find owner: %w", err)
+	}
+	if err := s.checkOwnedChannelLimit(ownerID); err != nil {
+		return nil, err
 	}
 
 	// Validate all users exist
@@ -114,7 +207,7 @@ func (s *ChannelService) CreateChannelWithUsers(name string, ownerID uint, chanT
 		user, err := s.userRepo.FindByID(userID)
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return nil, fmt.Errorf("user with ID %d not found", userID)
+				return nil, fmt.Errorf("%w: id %d", ErrUserNotFound, userID)
 			}
 			return nil, fmt.Errorf("failed to find user %d: %w", userID, err)
 		}
@@ -149,28 +242,158 @@ func (s *ChannelService) CreateChannelWithUsers(name string, ownerID uint, chanT
 	return channel, err
 }
 
+// BulkCreateChannels provisions several channels in one transaction. Each item
+// runs under its own savepoint, so a failing item is rolled back and reported
+// while the others are kept.
+func (s *ChannelService) BulkCreateChannels(items []models.BulkChannelItem) (*models.BulkCreateChannelsResponse, error) {
+	resp := &models.BulkCreateChannelsResponse{Results: make([]models.BulkChannelResult, 0, len(items))}
+
+	err := s.repo.Transaction(func(tx *gorm.DB) error {
+		txService := NewChannelService(postgres.NewChannelRepository(tx), postgres.NewUserRepository(tx), s.maxOwnedChannels)
+
+		for i, item := range items {
+			result := models.BulkChannelResult{Index: i}
+
+			if err := validateBulkChannelItem(item); err != nil {
+				result.Error = err.Error()
+				resp.Results = append(resp.Results, result)
+				resp.Failed++
+				continue
+			}
+
+			savepoint := fmt.Sprintf("bulk_channel_%d", i)
+			if err := tx.SavePoint(savepoint).Error; err != nil {
+				return fmt.Errorf("failed to create savepoint: %w", err)
+			}
+
+			channel, err := txService.CreateChannelWithUsers(item.Name, item.OwnerID, item.Type, item.UserIDs)
+			if err != nil {
+				if rbErr := tx.RollbackTo(savepoint).Error; rbErr != nil {
+					return fmt.Errorf("failed to roll back item %d: %w", i, rbErr)
+				}
+				result.Error = err.Error()
+				resp.Results = append(resp.Results, result)
+				resp.Failed++
+				continue
+			}
+
+			result.Success = true
+			result.Channel = &models.ChannelResponse{
+				ID:      channel.ID,
+				Name:    channel.Name,
+				Type:    channel.Type,
+				OwnerID: channel.OwnerID,
+			}
+			resp.Results = append(resp.Results, result)
+			resp.Succeeded++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// validateBulkChannelItem applies the same member rules as interactive channel creation
+func validateBulkChannelItem(item models.BulkChannelItem) error {
+	if item.Type == models.ChannelTypeGroup && item.Name == "" {
+		return errors.New("name is required for group channels")
+	}
+	for _, id := range item.UserIDs {
+		if id == item.OwnerID {
+			return nil
+		}
+	}
+	return errors.New("owner must be included in userIds")
+}
+
 func (s *ChannelService) UpdateChannel(channelID uint, name string) error {
 	channel, err := s.repo.GetByID(channelID)
 	if err != nil {
-		return err
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrChannelNotFound
+		}
+		return fmt.Errorf("failed to find channel: %w", err)
 	}
 	channel.Name = name
 	return s.repo.Update(channel)
 }
 
-func (s *ChannelService) DeleteChannel(ownerId, channelID uint) error {
-	// Check if channel exists and get channel details
-	channel, err := s.repo.GetByID(channelID)
+// SetSlowMode sets the minimum interval between messages per user (channel owner or admin only)
+func (s *ChannelService) SetSlowMode(userID, channelID uint, seconds int) (*models.Channel, error) {
+	if seconds < 0 || seconds > models.MaxSlowModeSeconds {
+		return nil, fmt.Errorf("%w: must be between 0 and %d seconds", ErrInvalidSlowMode, models.MaxSlowModeSeconds)
+	}
+
+	channel, _, err := s.requireRole(channelID, userID, models.ChannelRoleAdmin)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("channel not found")
-		}
-		return errors.New("failed to find channel: " + err.Error())
+		return nil, err
 	}
 
-	// Check if the user is the owner of the channel
-	if channel.OwnerID != ownerId {
-		return errors.New("only channel owner can delete channel")
+	channel.SlowModeSeconds = seconds
+	if err := s.repo.Update(channel); err != nil {
+		return nil, err
+	}
+	return channel, nil
+}
+
+// MuteChannel mutes notifications from a channel for a member until the given
+// time, or until unmuted when until is nil
+func (s *ChannelService) MuteChannel(userID, channelID uint, until *time.Time) (*models.ChannelMute, error) {
+	isMember, err := s.repo.IsMember(channelID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check channel membership: %w", err)
+	}
+	if !isMember {
+		return nil, ErrNotChannelMember
+	}
+
+	mute := &models.ChannelMute{ChannelID: channelID, UserID: userID, MutedUntil: until}
+	if err := s.repo.SetMute(mute); err != nil {
+		return nil, fmt.Errorf("failed to mute channel: %w", err)
+	}
+	return mute, nil
+}
+
+// UnmuteChannel removes the member's mute for a channel. Unmuting a channel
+// that is not muted is a no-op.
+func (s *ChannelService) UnmuteChannel(userID, channelID uint) error {
+	isMember, err := s.repo.IsMember(channelID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check channel membership: %w", err)
+	}
+	if !isMember {
+		return ErrNotChannelMember
+	}
+	if err := s.repo.DeleteMute(channelID, userID); err != nil {
+		return fmt.Errorf("failed to unmute channel: %w", err)
+	}
+	return nil
+}
+
+// SetArchived archives or unarchives a channel (owner only). Archived channels
+// keep their members and history but accept no new messages.
+func (s *ChannelService) SetArchived(userID, channelID uint, archived bool) (*models.Channel, error) {
+	channel, _, err := s.requireRole(channelID, userID, models.ChannelRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+	if channel.Archived == archived {
+		return channel, nil
+	}
+
+	channel.Archived = archived
+	if err := s.repo.Update(channel); err != nil {
+		return nil, fmt.Errorf("failed to update channel: %w", err)
+	}
+	return channel, nil
+}
+
+func (s *ChannelService) DeleteChannel(ownerId, channelID uint) error {
+	// Only the owner can delete a channel
+	if _, _, err := s.requireRole(channelID, ownerId, models.ChannelRoleOwner); err != nil {
+		return err
 	}
 
 	// Delete channel (cascade deletion will be handled by GORM)
@@ -178,7 +401,14 @@ func (s *ChannelService) DeleteChannel(ownerId, channelID uint) error {
 }
 
 func (s *ChannelService) GetChannelByID(channelID uint) (*models.Channel, error) {
-	return s.repo.GetByID(channelID)
+	channel, err := s.repo.GetByID(channelID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrChannelNotFound
+		}
+		return nil, fmt.Errorf("failed to find channel: %w", err)
+	}
+	return channel, nil
 }
 
 func (s *ChannelService) JoinChannel(channelID, userID uint) error {
@@ -186,18 +416,18 @@ func (s *ChannelService) JoinChannel(channelID, userID uint) error {
 	_, err := s.repo.GetByID(channelID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("channel not found")
+			return ErrChannelNotFound
 		}
-		return errors.New("failed to find channel: " + err.Error())
+		return fmt.Errorf("failed to find channel: %w", err)
 	}
 
 	// Check if user exists
 	_, err = s.userRepo.FindByID(userID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("user not found")
+			return ErrUserNotFound
 		}
-		return errors.New("failed to find user: " + err.Error())
+		return fmt.Errorf("failed to find user: %w", err)
 	}
 
 	// Add user to channel
@@ -209,89 +439,107 @@ func (s *ChannelService) LeaveChannel(channelID, userID uint) error {
 	_, err := s.repo.GetByID(channelID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("channel not found")
+			return ErrChannelNotFound
 		}
-		return errors.New("failed to find channel: " + err.Error())
+		return fmt.Errorf("failed to find channel: %w", err)
 	}
 
 	// Check if user exists
 	_, err = s.userRepo.FindByID(userID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("user not found")
+			return ErrUserNotFound
 		}
-		return errors.New("failed to find user: " + err.Error())
+		return fmt.Errorf("failed to find user: %w", err)
 	}
 
 	// Remove user from channel
 	return s.repo.RemoveUser(channelID, userID)
 }
 
-func (s *ChannelService) RemoveUserFromChannel(ownerId, channelID, targetUserID uint) error {
-	// Check if channel exists and get channel details
-	channel, err := s.repo.GetByID(channelID)
+// RemoveUserFromChannel removes a member. Owners and admins may remove members,
+// but only the owner may remove an admin and nobody may remove the owner.
+func (s *ChannelService) RemoveUserFromChannel(callerID, channelID, targetUserID uint) error {
+	channel, callerRole, err := s.requireRole(channelID, callerID, models.ChannelRoleAdmin)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("channel not found")
-		}
-		return errors.New("failed to find channel: " + err.Error())
-	}
-
-	// Check if the user is the owner of the channel
-	if channel.OwnerID != ownerId {
-		return errors.New("only channel owner can remove users")
+		return err
 	}
 
 	// Check if target user exists
 	_, err = s.userRepo.FindByID(targetUserID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("target user not found")
+			return ErrUserNotFound
 		}
-		return errors.New("failed to find target user: " + err.Error())
+		return fmt.Errorf("failed to find target user: %w", err)
 	}
 
 	// Check if trying to remove the owner
-	if targetUserID == ownerId {
-		return errors.New("cannot remove channel owner")
+	if targetUserID == channel.OwnerID {
+		return ErrCannotRemoveOwner
+	}
+
+	targetRole, err := memberRole(s.repo, channel, targetUserID)
+	if err != nil {
+		return err
+	}
+	if channelRoleRank[targetRole] >= channelRoleRank[callerRole] {
+		return ErrInsufficientRole
 	}
 
 	// Remove user from channel
 	return s.repo.RemoveUser(channelID, targetUserID)
 }
 
-func (s *ChannelService) AddUserToChannel(ownerId, channelID, targetUserID uint) error {
-	// Check if channel exists and get channel details
-	channel, err := s.repo.GetByID(channelID)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("channel not found")
-		}
-		return errors.New("failed to find channel: " + err.Error())
-	}
-
-	// Check if the user is the owner of the channel
-	if channel.OwnerID != ownerId {
-		return errors.New("only channel owner can add users")
+// AddUserToChannel adds a user to the channel as a member. Owners and admins may add users.
+func (s *ChannelService) AddUserToChannel(callerID, channelID, targetUserID uint) error {
+	if _, _, err := s.requireRole(channelID, callerID, models.ChannelRoleAdmin); err != nil {
+		return err
 	}
 
 	// Check if target user exists
-	_, err = s.userRepo.FindByID(targetUserID)
+	_, err := s.userRepo.FindByID(targetUserID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("target user not found")
+			return ErrUserNotFound
 		}
-		return errors.New("failed to find target user: " + err.Error())
+		return fmt.Errorf("failed to find target user: %w", err)
 	}
 
 	// Add user to channel
 	return s.repo.AddUser(channelID, targetUserID)
 }
 
+// SetMemberRole promotes a member to admin or demotes an admin to member. Only the owner may change roles.
+func (s *ChannelService) SetMemberRole(callerID, channelID, targetUserID uint, role string) (*models.ChannelMember, error) {
+	channel, _, err := s.requireRole(channelID, callerID, models.ChannelRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+	if targetUserID == channel.OwnerID {
+		return nil, ErrOwnerRoleFixed
+	}
+
+	if err := s.repo.SetMemberRole(channelID, targetUserID, role); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotChannelMember
+		}
+		return nil, fmt.Errorf("failed to set member role: %w", err)
+	}
+	return &models.ChannelMember{ChannelID: channelID, UserID: targetUserID, Role: role}, nil
+}
+
 func (s *ChannelService) GetChatMessagesByChannel(channelID uint) ([]models.Chat, error) {
 	return s.repo.GetChatMessages(channelID)
 }
 
 func (s *ChannelService) GetChatMessagesByChannelWithPagination(channelID uint, limit int, before *int64) ([]models.ChatResponse, error) {
-	return s.repo.GetChatMessagesWithPagination(channelID, limit, before)
+	items, err := s.repo.GetChatMessagesWithPagination(channelID, limit, before)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		redactDeleted(&items[i])
+	}
+	return items, nil
 }
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"chat-service/internal/models"
+	"chat-service/internal/repositories/postgres"
+	"errors"
+	"testing"
+)
+
+// roleFixture is a channel with an owner, two admins, two members and a user
+// outside the channel
+type roleFixture struct {
+	service *ChannelService
+	channel *models.Channel
+
+	owner, admin, admin2, member, member2, outsider uint
+}
+
+func newRoleFixture(t *testing.T) *roleFixture {
+	t.Helper()
+	db := newTestDB(t)
+	service := NewChannelService(postgres.NewChannelRepository(db), postgres.NewUserRepository(db), 0)
+
+	f := &roleFixture{service: service}
+	f.owner = createTestUser(t, db, false).ID
+	f.admin = createTestUser(t, db, false).ID
+	f.admin2 = createTestUser(t, db, false).ID
+	f.member = createTestUser(t, db, false).ID
+	f.member2 = createTestUser(t, db, false).ID
+	f.outsider = createTestUser(t, db, false).ID
+
+	channel, err := service.CreateChannel("roles", f.owner, models.ChannelTypeGroup)
+	if err != nil {
+		t.Fatalf("create channel: %v", err)
+	}
+	f.channel = channel
+
+	for _, id := range []uint{f.admin, f.admin2, f.member, f.member2} {
+		if err := service.AddUserToChannel(f.owner, channel.ID, id); err != nil {
+			t.Fatalf("add member: %v", err)
+		}
+	}
+	for _, id := range []uint{f.admin, f.admin2} {
+		if _, err := service.SetMemberRole(f.owner, channel.ID, id, models.ChannelRoleAdmin); err != nil {
+			t.Fatalf("promote admin: %v", err)
+		}
+	}
+	return f
+}
+
+func TestChannelRoleTransitions(t *testing.T) {
+	tests := []struct {
+		name    string
+		run     func(f *roleFixture) error
+		wantErr error
+	}{
+		{"owner promotes member", func(f *roleFixture) error {
+			_, err := f.service.SetMemberRole(f.owner, f.channel.ID, f.member, models.ChannelRoleAdmin)
+			return err
+		}, nil},
+		{"owner demotes admin", func(f *roleFixture) error {
+			_, err := f.service.SetMemberRole(f.owner, f.channel.ID, f.admin, models.ChannelRoleMember)
+			return err
+		}, nil},
+		{"admin cannot change roles", func(f *roleFixture) error {
+			_, err := f.service.SetMemberRole(f.admin, f.channel.ID, f.member, models.ChannelRoleAdmin)
+			return err
+		}, ErrInsufficientRole},
+		{"owner role is fixed", func(f *roleFixture) error {
+			_, err := f.service.SetMemberRole(f.owner, f.channel.ID, f.owner, models.ChannelRoleMember)
+			return err
+		}, ErrOwnerRoleFixed},
+		{"non-member cannot be given a role", func(f *roleFixture) error {
+			_, err := f.service.SetMemberRole(f.owner, f.channel.ID, f.outsider, models.ChannelRoleAdmin)
+			return err
+		}, ErrNotChannelMember},
+		{"admin removes member", func(f *roleFixture) error {
+			return f.service.RemoveUserFromChannel(f.admin, f.channel.ID, f.member)
+		}, nil},
+		{"admin cannot remove admin", func(f *roleFixture) error {
+			return f.service.RemoveUserFromChannel(f.admin, f.channel.ID, f.admin2)
+		}, ErrInsufficientRole},
+		{"owner removes admin", func(f *roleFixture) error {
+			return f.service.RemoveUserFromChannel(f.owner, f.channel.ID, f.admin)
+		}, nil},
+		{"nobody removes the owner", func(f *roleFixture) error {
+			return f.service.RemoveUserFromChannel(f.admin, f.channel.ID, f.owner)
+		}, ErrCannotRemoveOwner},
+		{"member cannot remove member", func(f *roleFixture) error {
+			return f.service.RemoveUserFromChannel(f.member, f.channel.ID, f.member2)
+		}, ErrInsufficientRole},
+		{"admin adds user", func(f *roleFixture) error {
+			return f.service.AddUserToChannel(f.admin, f.channel.ID, f.outsider)
+		}, nil},
+		{"member cannot add user", func(f *roleFixture) error {
+			return f.service.AddUserToChannel(f.member, f.channel.ID, f.outsider)
+		}, ErrInsufficientRole},
+		{"admin sets slow mode", func(f *roleFixture) error {
+			_, err := f.service.SetSlowMode(f.admin, f.channel.ID, 30)
+			return err
+		}, nil},
+		{"member cannot set slow mode", func(f *roleFixture) error {
+			_, err := f.service.SetSlowMode(f.member, f.channel.ID, 30)
+			return err
+		}, ErrInsufficientRole},
+		{"outsider cannot set slow mode", func(f *roleFixture) error {
+			_, err := f.service.SetSlowMode(f.outsider, f.channel.ID, 30)
+			return err
+		}, ErrInsufficientRole},
+		{"demoted admin loses admin rights", func(f *roleFixture) error {
+			if _, err := f.service.SetMemberRole(f.owner, f.channel.ID, f.admin, models.ChannelRoleMember); err != nil {
+				return err
+			}
+			_, err := f.service.SetSlowMode(f.admin, f.channel.ID, 30)
+			return err
+		}, ErrInsufficientRole},
+		{"promoted member gains admin rights", func(f *roleFixture) error {
+			if _, err := f.service.SetMemberRole(f.owner, f.channel.ID, f.member, models.ChannelRoleAdmin); err != nil {
+				return err
+			}
+			return f.service.RemoveUserFromChannel(f.member, f.channel.ID, f.member2)
+		}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newRoleFixture(t)
+			if err := tt.run(f); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOwnedChannelLimit(t *testing.T) {
+	db := newTestDB(t)
+	service := NewChannelService(postgres.NewChannelRepository(db), postgres.NewUserRepository(db), 2)
+	owner := createTestUser(t, db, false).ID
+	member := createTestUser(t, db, false).ID
+
+	var created []*models.Channel
+	for i := 0; i < 2; i++ {
+		channel, err := service.CreateChannelWithUsers("owned", owner, models.ChannelTypeGroup, []uint{owner, member})
+		if err != nil {
+			t.Fatalf("create channel %d within the limit: %v", i+1, err)
+		}
+		created = append(created, channel)
+	}
+
+	if _, err := service.CreateChannelWithUsers("owned", owner, models.ChannelTypeGroup, []uint{owner, member}); !errors.Is(err, ErrChannelLimitReached) {
+		t.Fatalf("create channel past the limit: err = %v, want %v", err, ErrChannelLimitReached)
+	}
+	if _, err := service.CreateChannel("owned", owner, models.ChannelTypeGroup); !errors.Is(err, ErrChannelLimitReached) {
+		t.Fatalf("CreateChannel past the limit: err = %v, want %v", err, ErrChannelLimitReached)
+	}
+	// The limit is per owner
+	if _, err := service.CreateChannel("owned", member, models.ChannelTypeGroup); err != nil {
+		t.Fatalf("another user creates a channel: %v", err)
+	}
+
+	// Deleted channels no longer count
+	if err := service.DeleteChannel(owner, created[0].ID); err != nil {
+		t.Fatalf("delete channel: %v", err)
+	}
+	if _, err := service.CreateChannelWithUsers("owned", owner, models.ChannelTypeGroup, []uint{owner, member}); err != nil {
+		t.Fatalf("create channel after deleting one: %v", err)
+	}
+}
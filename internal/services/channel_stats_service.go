@@ -0,0 +1,111 @@
+package services
+
+import (
+	"chat-service/internal/models"
+	"chat-service/internal/repositories/postgres"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+const (
+	channelStatsCacheTTL = time.Minute
+	channelStatsTopN     = 5
+)
+
+// Channel stats errors
+var (
+	ErrChannelNotFound   = errors.New("channel not found")
+	ErrStatsAccessDenied = errors.New("only channel owner or admin can view stats")
+)
+
+// ChannelStatsService computes engagement analytics for channels. Results are
+// cached in Redis for a short time since they are aggregate queries.
+type ChannelStatsService struct {
+	channelRepo  *postgres.ChannelRepository
+	chatRepo     *postgres.ChatRepository
+	reactionRepo *postgres.ReactionRepository
+	redisService *RedisService
+}
+
+func NewChannelStatsService(channelRepo *postgres.ChannelRepository, chatRepo *postgres.ChatRepository, reactionRepo *postgres.ReactionRepository, redisService *RedisService) *ChannelStatsService {
+	return &ChannelStatsService{
+		channelRepo:  channelRepo,
+		chatRepo:     chatRepo,
+		reactionRepo: reactionRepo,
+		redisService: redisService,
+	}
+}
+
+// GetChannelStats returns stats for a channel. Only the channel's owner and admins may view them.
+func (s *ChannelStatsService) GetChannelStats(ctx context.Context, userID, channelID uint) (*models.ChannelStatsResponse, error) {
+	if _, _, err := requireChannelRole(s.channelRepo, channelID, userID, models.ChannelRoleAdmin); err != nil {
+		if errors.Is(err, ErrInsufficientRole) {
+			return nil, ErrStatsAccessDenied
+		}
+		return nil, err
+	}
+
+	cacheKey := fmt.Sprintf("channel:%d:stats", channelID)
+	if s.redisService != nil {
+		var cached models.ChannelStatsResponse
+		if err := s.redisService.Get(ctx, cacheKey, &cached); err == nil {
+			return &cached, nil
+		}
+	}
+
+	stats, err := s.computeStats(channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.redisService != nil {
+		if err := s.redisService.Set(ctx, cacheKey, stats, channelStatsCacheTTL); err != nil {
+			slog.Warn("Failed to cache channel stats", "channelID", channelID, "error", err)
+		}
+	}
+	return stats, nil
+}
+
+func (s *ChannelStatsService) computeStats(channelID uint) (*models.ChannelStatsResponse, error) {
+	messageCount, err := s.chatRepo.CountByChannel(channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count messages: %w", err)
+	}
+
+	topMembers, err := s.chatRepo.TopSenders(channelID, channelStatsTopN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load top members: %w", err)
+	}
+
+	topReactions, err := s.reactionRepo.TopEmojisByChannel(channelID, channelStatsTopN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load top reactions: %w", err)
+	}
+
+	stats := &models.ChannelStatsResponse{
+		ChannelID:    channelID,
+		MessageCount: messageCount,
+		TopMembers:   topMembers,
+		TopReactions: topReactions,
+		GeneratedAt:  time.Now().UTC(),
+	}
+	if stats.TopMembers == nil {
+		stats.TopMembers = []models.MemberActivity{}
+	}
+	if stats.TopReactions == nil {
+		stats.TopReactions = []models.ReactionUsage{}
+	}
+
+	hour, count, ok, err := s.chatRepo.BusiestHour(channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute busiest hour: %w", err)
+	}
+	if ok {
+		stats.BusiestHour = &hour
+		stats.BusiestHourMessages = count
+	}
+	return stats, nil
+}
@@ -0,0 +1,377 @@
+package services
+
+import (
+	"chat-service/internal/models"
+	"chat-service/internal/repositories/postgres"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Webhook event fired for every new message in a channel with webhooks
+const EventChannelMessage = "message.created"
+
+// Messages each inbound webhook may post per minute
+const inboundWebhookRateLimit = 30
+
+// Channel webhook errors
+var (
+	ErrWebhookNotFound       = errors.New("webhook not found")
+	ErrWebhookAccessDenied   = errors.New("only channel owner or admin can manage webhooks")
+	ErrInvalidWebhookToken   = errors.New("invalid webhook token")
+	ErrWebhookRateLimited    = errors.New("webhook rate limit exceeded")
+	ErrInvalidWebhookMessage = errors.New("invalid webhook message")
+)
+
+// ChannelWebhookService manages per-channel outbound webhooks and the inbound
+// webhooks external systems use to post into channels as bots
+type ChannelWebhookService struct {
+	webhookRepo  *postgres.ChannelWebhookRepository
+	channelRepo  *postgres.ChannelRepository
+	userRepo     *postgres.UserRepository
+	chatRepo     *postgres.ChatRepository
+	chatService  *ChatService
+	redisService *RedisService
+
+	// Key for signing inbound webhook tokens
+	signingKey []byte
+}
+
+func NewChannelWebhookService(webhookRepo *postgres.ChannelWebhookRepository, channelRepo *postgres.ChannelRepository, userRepo *postgres.UserRepository, chatRepo *postgres.ChatRepository, chatService *ChatService, redisService *RedisService, signingKey string) *ChannelWebhookService {
+	return &ChannelWebhookService{
+		webhookRepo:  webhookRepo,
+		channelRepo:  channelRepo,
+		userRepo:     userRepo,
+		chatRepo:     chatRepo,
+		chatService:  chatService,
+		redisService: redisService,
+		signingKey:   []byte(signingKey),
+	}
+}
+
+// checkManage verifies the user is the channel's owner or one of its admins
+func (s *ChannelWebhookService) checkManage(userID, channelID uint) error {
+	if _, _, err := requireChannelRole(s.channelRepo, channelID, userID, models.ChannelRoleAdmin); err != nil {
+		if errors.Is(err, ErrInsufficientRole) {
+			return ErrWebhookAccessDenied
+		}
+		return err
+	}
+	return nil
+}
+
+// findWebhook loads a webhook and checks it belongs to the channel
+func (s *ChannelWebhookService) findWebhook(channelID, webhookID uint) (*models.ChannelWebhook, error) {
+	webhook, err := s.webhookRepo.FindByID(webhookID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, fmt.Errorf("failed to find webhook: %w", err)
+	}
+	if webhook.ChannelID != channelID {
+		return nil, ErrWebhookNotFound
+	}
+	return webhook, nil
+}
+
+// CreateWebhook registers a webhook for the channel with a freshly generated signing secret
+func (s *ChannelWebhookService) CreateWebhook(userID, channelID uint, url string) (*models.ChannelWebhook, error) {
+	if err := s.checkManage(userID, channelID); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	err := ValidateWebhookURL(ctx, url)
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	webhook := &models.ChannelWebhook{
+		ChannelID: channelID,
+		URL:       url,
+		Secret:    secret,
+		Enabled:   true,
+		CreatedBy: userID,
+	}
+	if err := s.webhookRepo.Create(webhook); err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+// ListWebhooks returns the channel's webhooks
+func (s *ChannelWebhookService) ListWebhooks(userID, channelID uint) ([]models.ChannelWebhook, error) {
+	if err := s.checkManage(userID, channelID); err != nil {
+		return nil, err
+	}
+	webhooks, err := s.webhookRepo.ListByChannel(channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+// SetWebhookEnabled enables or disables delivery to a webhook
+func (s *ChannelWebhookService) SetWebhookEnabled(userID, channelID, webhookID uint, enabled bool) (*models.ChannelWebhook, error) {
+	if err := s.checkManage(userID, channelID); err != nil {
+		return nil, err
+	}
+	webhook, err := s.findWebhook(channelID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.webhookRepo.SetEnabled(webhook.ID, enabled); err != nil {
+		return nil, fmt.Errorf("failed to update webhook: %w", err)
+	}
+	webhook.Enabled = enabled
+	return webhook, nil
+}
+
+// DeleteWebhook removes a webhook from the channel
+func (s *ChannelWebhookService) DeleteWebhook(userID, channelID, webhookID uint) error {
+	if err := s.checkManage(userID, channelID); err != nil {
+		return err
+	}
+	webhook, err := s.findWebhook(channelID, webhookID)
+	if err != nil {
+		return err
+	}
+	if err := s.webhookRepo.Delete(webhook.ID); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	return nil
+}
+
+// randomHex returns n random bytes, hex encoded
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// inboundToken builds the token for an inbound webhook: "<id>.<signature>"
+func (s *ChannelWebhookService) inboundToken(webhook *models.InboundWebhook) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	fmt.Fprintf(mac, "inbound:%d:%d:%s", webhook.ID, webhook.ChannelID, webhook.Salt)
+	return strconv.FormatUint(uint64(webhook.ID), 10) + "." + hex.EncodeToString(mac.Sum(nil))
+}
+
+// CreateInboundWebhook creates a bot identity for the channel and returns the
+// webhook together with its token, which is not retrievable later
+func (s *ChannelWebhookService) CreateInboundWebhook(userID, channelID uint, name string) (*models.InboundWebhook, string, error) {
+	if err := s.checkManage(userID, channelID); err != nil {
+		return nil, "", err
+	}
+
+	salt, err := randomHex(16)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate webhook salt: %w", err)
+	}
+	suffix, err := randomHex(4)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate bot name: %w", err)
+	}
+
+	bot := &models.User{
+		Username: fmt.Sprintf("%s-bot-%s", name, suffix),
+		Email:    fmt.Sprintf("bot-%s-%s@bots.invalid", strconv.FormatUint(uint64(channelID), 10), suffix),
+		IsBot:    true,
+	}
+	if err := s.userRepo.Create(bot); err != nil {
+		return nil, "", fmt.Errorf("failed to create bot user: %w", err)
+	}
+
+	webhook := &models.InboundWebhook{
+		ChannelID: channelID,
+		Name:      name,
+		BotUserID: bot.ID,
+		Salt:      salt,
+		Enabled:   true,
+		CreatedBy: userID,
+	}
+	if err := s.webhookRepo.CreateInbound(webhook); err != nil {
+		return nil, "", fmt.Errorf("failed to create inbound webhook: %w", err)
+	}
+	return webhook, s.inboundToken(webhook), nil
+}
+
+// ListInboundWebhooks returns the channel's inbound webhooks
+func (s *ChannelWebhookService) ListInboundWebhooks(userID, channelID uint) ([]models.InboundWebhook, error) {
+	if err := s.checkManage(userID, channelID); err != nil {
+		return nil, err
+	}
+	webhooks, err := s.webhookRepo.ListInboundByChannel(channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inbound webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+// DeleteInboundWebhook revokes an inbound webhook. Its bot user is kept so past
+// messages stay attributed.
+func (s *ChannelWebhookService) DeleteInboundWebhook(userID, channelID, webhookID uint) error {
+	if err := s.checkManage(userID, channelID); err != nil {
+		return err
+	}
+	webhook, err := s.webhookRepo.FindInboundByID(webhookID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrWebhookNotFound
+		}
+		return fmt.Errorf("failed to find inbound webhook: %w", err)
+	}
+	if webhook.ChannelID != channelID {
+		return ErrWebhookNotFound
+	}
+	if err := s.webhookRepo.DeleteInbound(webhook.ID); err != nil {
+		return fmt.Errorf("failed to delete inbound webhook: %w", err)
+	}
+	return nil
+}
+
+// verifyInboundToken resolves a token to its enabled webhook
+func (s *ChannelWebhookService) verifyInboundToken(token string) (*models.InboundWebhook, error) {
+	idPart, _, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidWebhookToken
+	}
+	id, err := strconv.ParseUint(idPart, 10, 64)
+	if err != nil || id == 0 {
+		return nil, ErrInvalidWebhookToken
+	}
+
+	webhook, err := s.webhookRepo.FindInboundByID(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidWebhookToken
+		}
+		return nil, fmt.Errorf("failed to find inbound webhook: %w", err)
+	}
+	if !hmac.Equal([]byte(token), []byte(s.inboundToken(webhook))) || !webhook.Enabled {
+		return nil, ErrInvalidWebhookToken
+	}
+	return webhook, nil
+}
+
+// PostInboundMessage stores a message posted through an inbound webhook as its bot
+// user. The caller is responsible for broadcasting the returned message.
+func (s *ChannelWebhookService) PostInboundMessage(ctx context.Context, token string, channelID uint, text string) (*models.Chat, error) {
+	webhook, err := s.verifyInboundToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.ChannelID != channelID {
+		return nil, ErrInvalidWebhookToken
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, ErrInvalidWebhookMessage
+	}
+	// Bots get the same size limit as users
+	if err := s.chatService.ValidateText(text); err != nil {
+		return nil, err
+	}
+
+	archived, err := s.channelRepo.IsArchived(channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load channel: %w", err)
+	}
+	if archived {
+		return nil, ErrChannelArchived
+	}
+
+	if s.redisService != nil {
+		key := fmt.Sprintf("rate_limit:inbound_webhook:%d", webhook.ID)
+		allowed, err := s.redisService.CheckRateLimit(ctx, key, inboundWebhookRateLimit, time.Minute)
+		if err != nil {
+			slog.Warn("Inbound webhook rate limit check failed, allowing message", "webhookID", webhook.ID, "error", err)
+		} else if !allowed {
+			return nil, ErrWebhookRateLimited
+		}
+	}
+
+	chat := &models.Chat{
+		SenderID:  webhook.BotUserID,
+		ChannelID: webhook.ChannelID,
+		Text:      &text,
+	}
+	if err := s.chatRepo.Create(chat); err != nil {
+		return nil, fmt.Errorf("failed to save message: %w", err)
+	}
+
+	// Reload with the bot sender for the broadcast payload
+	return s.chatRepo.FindByID(chat.ID)
+}
+
+// ChannelWebhookNotifier posts new channel messages to the channel's enabled webhooks
+type ChannelWebhookNotifier struct {
+	webhookRepo *postgres.ChannelWebhookRepository
+	dispatcher  *WebhookDispatcher
+}
+
+func NewChannelWebhookNotifier(webhookRepo *postgres.ChannelWebhookRepository, dispatcher *WebhookDispatcher) *ChannelWebhookNotifier {
+	return &ChannelWebhookNotifier{
+		webhookRepo: webhookRepo,
+		dispatcher:  dispatcher,
+	}
+}
+
+// NotifyMessage queues the message for every enabled webhook on its channel.
+// It runs asynchronously and never blocks delivery.
+func (n *ChannelWebhookNotifier) NotifyMessage(chat *models.Chat) {
+	go func() {
+		webhooks, err := n.webhookRepo.ListEnabledByChannel(chat.ChannelID)
+		if err != nil {
+			slog.Error("Failed to load channel webhooks", "error", err, "channelID", chat.ChannelID)
+			return
+		}
+
+		for _, webhook := range webhooks {
+			event := models.ChannelMessageWebhookEvent{
+				Event:      EventChannelMessage,
+				WebhookID:  webhook.ID,
+				ChannelID:  chat.ChannelID,
+				MessageID:  chat.ID,
+				SenderID:   chat.SenderID,
+				SenderName: chat.Sender.Username,
+				Text:       chat.Text,
+				URL:        chat.URL,
+				FileName:   chat.FileName,
+				CreatedAt:  chat.CreatedAt,
+			}
+			if chat.UUID != nil {
+				event.MessageUUID = *chat.UUID
+			}
+
+			webhookID := webhook.ID
+			n.dispatcher.DispatchTo(webhook.URL, webhook.Secret, EventChannelMessage, event, func(err error) {
+				if err != nil {
+					slog.Warn("Channel webhook delivery failed", "webhookID", webhookID, "channelID", chat.ChannelID, "error", err)
+				}
+				if recordErr := n.webhookRepo.RecordDelivery(webhookID, err); recordErr != nil {
+					slog.Error("Failed to record webhook delivery", "webhookID", webhookID, "error", recordErr)
+				}
+			})
+		}
+	}()
+}
@@ -0,0 +1,622 @@
+package services
+
+import (
+	"chat-service/internal/models"
+	"chat-service/internal/repositories/postgres"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Chat errors
+var (
+	ErrMessageNotFound   = errors.New("message not found")
+	ErrNotChannelMember  = errors.New("user is not a member of the channel")
+	ErrInvalidEmoji      = errors.New("invalid reaction emoji")
+	ErrInvalidCursor     = errors.New("cursor does not refer to a message in this channel")
+	ErrNotMessageSender  = errors.New("only the sender can edit this message")
+	ErrCannotDelete      = errors.New("only the sender or a channel owner or admin can delete this message")
+	ErrMessageTooLarge   = errors.New("message text is too large")
+	ErrSenderBlocked     = errors.New("recipient does not accept messages from this user")
+	ErrInvalidParent     = errors.New("parent message must be a top-level message in the same channel")
+	ErrInvalidAttachment = errors.New("invalid attachment")
+	ErrPinLimitReached   = errors.New("channel has reached its pin limit")
+	ErrTooManyChannels   = errors.New("too many channels in one request")
+)
+
+// Most attachments a single message may carry
+const maxAttachmentsPerMessage = 10
+
+// Attachment types clients may send
+var allowedAttachmentTypes = map[string]bool{
+	"image/png":       true,
+	"image/jpeg":      true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"video/mp4":       true,
+	"audio/mpeg":      true,
+	"application/pdf": true,
+	"text/plain":      true,
+}
+
+// Message history page sizes
+const (
+	defaultHistoryPageSize = 50
+	maxHistoryPageSize     = 100
+)
+
+// Most channels a single catch-up request may cover
+const maxCatchupChannels = 200
+
+type ChatService struct {
+	chatRepo     *postgres.ChatRepository
+	channelRepo  *postgres.ChannelRepository
+	reactionRepo *postgres.ReactionRepository
+	userRepo     *postgres.UserRepository
+
+	// Maximum words in a highlighted search snippet
+	snippetMaxWords int
+
+	// Maximum message text size in bytes
+	maxTextBytes int
+}
+
+func NewChatService(chatRepo *postgres.ChatRepository, channelRepo *postgres.ChannelRepository, reactionRepo *postgres.ReactionRepository, userRepo *postgres.UserRepository, snippetMaxWords, maxTextBytes int) *ChatService {
+	if snippetMaxWords < 2 {
+		snippetMaxWords = 20
+	}
+	if maxTextBytes <= 0 {
+		maxTextBytes = 4096
+	}
+	return &ChatService{
+		chatRepo:        chatRepo,
+		channelRepo:     channelRepo,
+		reactionRepo:    reactionRepo,
+		userRepo:        userRepo,
+		snippetMaxWords: snippetMaxWords,
+		maxTextBytes:    maxTextBytes,
+	}
+}
+
+// CheckBlocked rejects a direct message whose recipient has blocked the sender.
+// It covers both direct channels and chats addressed with ReceiverID; group
+// channel messages always pass.
+func (s *ChatService) CheckBlocked(ctx context.Context, chat *models.Chat) error {
+	var blocked bool
+	var err error
+	if chat.ReceiverID != nil {
+		blocked, err = s.userRepo.IsBlocked(ctx, *chat.ReceiverID, chat.SenderID)
+	} else {
+		blocked, err = s.userRepo.IsBlockedInDirectChannel(ctx, chat.ChannelID, chat.SenderID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check blocked users: %w", err)
+	}
+	if blocked {
+		return ErrSenderBlocked
+	}
+	return nil
+}
+
+// CheckReceiver verifies the recipient of a direct message exists
+func (s *ChatService) CheckReceiver(receiverID uint) error {
+	if _, err := s.userRepo.FindByID(receiverID); err != nil {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// ValidateText checks a message text against the size limit
+func (s *ChatService) ValidateText(text string) error {
+	if len(text) > s.maxTextBytes {
+		return fmt.Errorf("%w: %d bytes, limit is %d", ErrMessageTooLarge, len(text), s.maxTextBytes)
+	}
+	return nil
+}
+
+// SearchMessages searches the text of messages in the user's channels, optionally
+// restricted to one channel, and returns highlighted snippets
+func (s *ChatService) SearchMessages(userID uint, query string, channelID *uint, limit int) ([]models.MessageSearchResult, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	if channelID != nil {
+		isMember, err := s.channelRepo.IsMember(*channelID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check channel membership: %w", err)
+		}
+		if !isMember {
+			return nil, ErrNotChannelMember
+		}
+	}
+
+	results, err := s.chatRepo.Search(userID, query, channelID, limit, s.snippetMaxWords)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search messages: %w", err)
+	}
+	return results, nil
+}
+
+// ValidateAttachments checks the attachment count and each attachment's URL,
+// type, size and dimensions
+func (s *ChatService) ValidateAttachments(attachments []models.Attachment) error {
+	if len(attachments) > maxAttachmentsPerMessage {
+		return fmt.Errorf("%w: at most %d attachments per message", ErrInvalidAttachment, maxAttachmentsPerMessage)
+	}
+	for i, a := range attachments {
+		switch {
+		case a.URL == "":
+			return fmt.Errorf("%w: attachment %d has no url", ErrInvalidAttachment, i)
+		case !allowedAttachmentTypes[a.MimeType]:
+			return fmt.Errorf("%w: type %q is not allowed", ErrInvalidAttachment, a.MimeType)
+		case a.Size < 0:
+			return fmt.Errorf("%w: attachment %d has a negative size", ErrInvalidAttachment, i)
+		case (a.Width != nil && *a.Width <= 0) || (a.Height != nil && *a.Height <= 0):
+			return fmt.Errorf("%w: attachment %d has invalid dimensions", ErrInvalidAttachment, i)
+		}
+	}
+	return nil
+}
+
+// ValidateParent checks that a reply's parent exists in the same channel and is
+// not itself a reply, keeping threads one level deep
+func (s *ChatService) ValidateParent(channelID, parentID uint) error {
+	parent, err := s.chatRepo.FindByID(parentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidParent
+		}
+		return fmt.Errorf("failed to find parent message: %w", err)
+	}
+	if parent.ChannelID != channelID || parent.ParentID != nil {
+		return ErrInvalidParent
+	}
+	return nil
+}
+
+// GetThread returns a top-level message and its replies. Asking for a reply
+// returns the whole thread it belongs to.
+func (s *ChatService) GetThread(userID, messageID uint) (*models.ThreadResponse, error) {
+	chat, err := s.findMessageForMember(userID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	parentID := chat.ID
+	if chat.ParentID != nil {
+		parentID = *chat.ParentID
+	}
+
+	items, err := s.chatRepo.ListThread(parentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load thread: %w", err)
+	}
+
+	thread := &models.ThreadResponse{Replies: []models.ChatResponse{}}
+	for _, item := range items {
+		item.Type = string(models.ChatTypeChannel)
+		redactDeleted(&item)
+		if item.ID == parentID {
+			thread.Parent = item
+		} else {
+			thread.Replies = append(thread.Replies, item)
+		}
+	}
+	if thread.Parent.ID == 0 {
+		return nil, ErrMessageNotFound
+	}
+	return thread, nil
+}
+
+// redactDeleted withholds the content of a deleted message, leaving a placeholder
+func redactDeleted(item *models.ChatResponse) {
+	if item.Deleted {
+		item.Text, item.URL, item.FileName, item.EditedAt = nil, nil, nil, nil
+		item.Attachments = nil
+	}
+}
+
+// GetChannelHistory returns a page of the channel's messages, newest first, older
+// than the beforeID message when given. limit defaults to 50 and is capped at 100.
+func (s *ChatService) GetChannelHistory(userID, channelID uint, beforeID *uint, limit int) (*models.ChatHistoryPage, error) {
+	if limit <= 0 {
+		limit = defaultHistoryPageSize
+	}
+	if limit > maxHistoryPageSize {
+		limit = maxHistoryPageSize
+	}
+
+	isMember, err := s.channelRepo.IsMember(channelID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check channel membership: %w", err)
+	}
+	if !isMember {
+		return nil, ErrNotChannelMember
+	}
+
+	var before *models.Chat
+	if beforeID != nil {
+		before, err = s.chatRepo.FindByIDWithDeleted(*beforeID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrInvalidCursor
+			}
+			return nil, fmt.Errorf("failed to find cursor message: %w", err)
+		}
+		if before.ChannelID != channelID {
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	// Fetch one extra row to learn whether an older page exists
+	items, err := s.chatRepo.ListChannelPage(channelID, before, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load messages: %w", err)
+	}
+
+	page := &models.ChatHistoryPage{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		nextCursor := page.Items[limit-1].ID
+		page.NextCursor = &nextCursor
+	}
+	if page.Items == nil {
+		page.Items = []models.ChatResponse{}
+	}
+	for i := range page.Items {
+		item := &page.Items[i]
+		item.Type = string(models.ChatTypeChannel)
+		redactDeleted(item)
+	}
+	return page, nil
+}
+
+// CatchUp returns, for each requested channel the user belongs to, the messages
+// newer than the channel's cursor, oldest first. Channels the user does not
+// belong to are left out. limit caps the messages per channel; it defaults to 50
+// and is capped at 100.
+func (s *ChatService) CatchUp(userID uint, cursors map[uint]uint, limit int) (*models.CatchupResponse, error) {
+	if len(cursors) > maxCatchupChannels {
+		return nil, ErrTooManyChannels
+	}
+	if limit <= 0 {
+		limit = defaultHistoryPageSize
+	}
+	if limit > maxHistoryPageSize {
+		limit = maxHistoryPageSize
+	}
+
+	resp := &models.CatchupResponse{Channels: make(map[uint]models.CatchupChannel)}
+	if len(cursors) == 0 {
+		return resp, nil
+	}
+	channelIDs := make([]uint, 0, len(cursors))
+	for channelID := range cursors {
+		channelIDs = append(channelIDs, channelID)
+	}
+	memberOf, err := s.channelRepo.MemberChannelIDs(userID, channelIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check channel membership: %w", err)
+	}
+
+	for _, channelID := range memberOf {
+		// Fetch one extra row to learn whether messages were left out
+		items, err := s.chatRepo.ListChannelAfter(channelID, cursors[channelID], limit+1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load messages: %w", err)
+		}
+		channel := models.CatchupChannel{Items: items}
+		if len(items) > limit {
+			channel.Items = items[:limit]
+			channel.HasMore = true
+		}
+		if channel.Items == nil {
+			channel.Items = []models.ChatResponse{}
+		}
+		for i := range channel.Items {
+			item := &channel.Items[i]
+			item.Type = string(models.ChatTypeChannel)
+			redactDeleted(item)
+		}
+		resp.Channels[channelID] = channel
+	}
+	return resp, nil
+}
+
+// ForwardMessage copies a message from the source channel into the target channel.
+// The user must be a member of both channels. The copy keeps a reference to the
+// original message so clients can render "forwarded from" attribution.
+func (s *ChatService) ForwardMessage(userID, sourceChannelID, messageID, targetChannelID uint) (*models.Chat, error) {
+	for _, channelID := range []uint{sourceChannelID, targetChannelID} {
+		isMember, err := s.channelRepo.IsMember(channelID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check channel membership: %w", err)
+		}
+		if !isMember {
+			return nil, ErrNotChannelMember
+		}
+	}
+
+	archived, err := s.channelRepo.IsArchived(targetChannelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load channel: %w", err)
+	}
+	if archived {
+		return nil, ErrChannelArchived
+	}
+
+	original, err := s.chatRepo.FindByID(messageID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMessageNotFound
+		}
+		return nil, fmt.Errorf("failed to find message: %w", err)
+	}
+	if original.ChannelID != sourceChannelID {
+		return nil, ErrMessageNotFound
+	}
+
+	// Always point at the root message so attribution survives re-forwarding
+	forwardedFrom := original.ID
+	if original.ForwardedFrom != nil {
+		forwardedFrom = *original.ForwardedFrom
+	}
+
+	chat := &models.Chat{
+		SenderID:      userID,
+		ChannelID:     targetChannelID,
+		Text:          original.Text,
+		URL:           original.URL,
+		FileName:      original.FileName,
+		ForwardedFrom: &forwardedFrom,
+	}
+	if err := s.CheckBlocked(context.Background(), chat); err != nil {
+		return nil, err
+	}
+	if err := s.chatRepo.Create(chat); err != nil {
+		return nil, fmt.Errorf("failed to forward message: %w", err)
+	}
+
+	// Reload with sender data for the broadcast payload
+	return s.chatRepo.FindByID(chat.ID)
+}
+
+// EditMessage replaces the text of a message. Only the original sender may edit,
+// and deleted messages and messages in archived channels cannot be edited.
+func (s *ChatService) EditMessage(userID, messageID uint, text string) (*models.Chat, error) {
+	if err := s.ValidateText(text); err != nil {
+		return nil, err
+	}
+
+	chat, err := s.chatRepo.FindByID(messageID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMessageNotFound
+		}
+		return nil, fmt.Errorf("failed to find message: %w", err)
+	}
+	if chat.SenderID != userID {
+		return nil, ErrNotMessageSender
+	}
+	if chat.ChannelID != 0 {
+		archived, err := s.channelRepo.IsArchived(chat.ChannelID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load channel: %w", err)
+		}
+		if archived {
+			return nil, ErrChannelArchived
+		}
+	}
+
+	editedAt := time.Now()
+	if err := s.chatRepo.UpdateText(chat, text, editedAt); err != nil {
+		return nil, fmt.Errorf("failed to edit message: %w", err)
+	}
+	chat.Text = &text
+	chat.EditedAt = &editedAt
+	return chat, nil
+}
+
+// DeleteMessage soft-deletes a message. The sender or the channel owner may delete
+// it. Deleting a message that is already deleted succeeds and reports deleted=false.
+func (s *ChatService) DeleteMessage(userID, messageID uint) (chat *models.Chat, deleted bool, err error) {
+	chat, err = s.chatRepo.FindByIDWithDeleted(messageID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, ErrMessageNotFound
+		}
+		return nil, false, fmt.Errorf("failed to find message: %w", err)
+	}
+
+	if chat.SenderID != userID {
+		if chat.ChannelID == 0 {
+			return nil, false, ErrCannotDelete
+		}
+		channel, err := s.channelRepo.GetByID(chat.ChannelID)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to find channel: %w", err)
+		}
+		role, err := memberRole(s.channelRepo, channel, userID)
+		if err != nil {
+			return nil, false, err
+		}
+		if channelRoleRank[role] < channelRoleRank[models.ChannelRoleAdmin] {
+			return nil, false, ErrCannotDelete
+		}
+	}
+
+	if chat.DeletedAt.Valid {
+		return chat, false, nil
+	}
+	if err := s.chatRepo.Delete(chat.ID); err != nil {
+		return nil, false, fmt.Errorf("failed to delete message: %w", err)
+	}
+	return chat, true, nil
+}
+
+// requirePinRole checks the user is an admin or owner of the channel
+func (s *ChatService) requirePinRole(userID, channelID uint) error {
+	channel, err := s.channelRepo.GetByID(channelID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrChannelNotFound
+		}
+		return fmt.Errorf("failed to find channel: %w", err)
+	}
+	role, err := memberRole(s.channelRepo, channel, userID)
+	if err != nil {
+		return err
+	}
+	if channelRoleRank[role] < channelRoleRank[models.ChannelRoleAdmin] {
+		return ErrInsufficientRole
+	}
+	return nil
+}
+
+// PinMessage pins a message of the channel. Only channel admins and owners may
+// pin, and a channel holds at most models.MaxPinsPerChannel pins. Pinning an
+// already pinned message succeeds and reports Changed=false.
+func (s *ChatService) PinMessage(userID, channelID, messageID uint) (*models.PinEvent, error) {
+	if err := s.requirePinRole(userID, channelID); err != nil {
+		return nil, err
+	}
+
+	chat, err := s.chatRepo.FindByID(messageID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMessageNotFound
+		}
+		return nil, fmt.Errorf("failed to find message: %w", err)
+	}
+	if chat.ChannelID != channelID {
+		return nil, ErrMessageNotFound
+	}
+
+	pin := &models.PinnedMessage{
+		ChannelID: channelID,
+		ChatID:    chat.ID,
+		PinnedBy:  userID,
+		PinnedAt:  time.Now(),
+	}
+	pinned, err := s.chatRepo.PinMessage(pin, models.MaxPinsPerChannel)
+	if err != nil {
+		if errors.Is(err, postgres.ErrPinLimitReached) {
+			return nil, ErrPinLimitReached
+		}
+		return nil, fmt.Errorf("failed to pin message: %w", err)
+	}
+	return &models.PinEvent{
+		ChannelID: channelID,
+		MessageID: chat.ID,
+		UserID:    pin.PinnedBy,
+		PinnedAt:  &pin.PinnedAt,
+		Changed:   pinned,
+	}, nil
+}
+
+// UnpinMessage removes a message's pin. Only channel admins and owners may unpin.
+// Unpinning a message that is not pinned succeeds and reports Changed=false.
+func (s *ChatService) UnpinMessage(userID, channelID, messageID uint) (*models.PinEvent, error) {
+	if err := s.requirePinRole(userID, channelID); err != nil {
+		return nil, err
+	}
+	unpinned, err := s.chatRepo.UnpinMessage(channelID, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpin message: %w", err)
+	}
+	return &models.PinEvent{
+		ChannelID: channelID,
+		MessageID: messageID,
+		UserID:    userID,
+		Changed:   unpinned,
+	}, nil
+}
+
+// ListPins returns the channel's pinned messages, most recently pinned first
+func (s *ChatService) ListPins(userID, channelID uint) ([]models.PinnedMessageResponse, error) {
+	isMember, err := s.channelRepo.IsMember(channelID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check channel membership: %w", err)
+	}
+	if !isMember {
+		return nil, ErrNotChannelMember
+	}
+
+	pins, err := s.chatRepo.ListPins(channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pins: %w", err)
+	}
+	for i := range pins {
+		pins[i].Type = string(models.ChatTypeChannel)
+	}
+	return pins, nil
+}
+
+// findMessageForMember loads a message and checks the user belongs to its channel
+func (s *ChatService) findMessageForMember(userID, messageID uint) (*models.Chat, error) {
+	chat, err := s.chatRepo.FindByID(messageID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMessageNotFound
+		}
+		return nil, fmt.Errorf("failed to find message: %w", err)
+	}
+
+	isMember, err := s.channelRepo.IsMember(chat.ChannelID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check channel membership: %w", err)
+	}
+	if !isMember {
+		return nil, ErrNotChannelMember
+	}
+	return chat, nil
+}
+
+// AddReaction adds the user's emoji reaction to a message. Adding the same
+// reaction twice is a no-op reported with Changed=false.
+func (s *ChatService) AddReaction(userID, messageID uint, emoji string) (*models.ReactionEvent, error) {
+	return s.applyReaction(userID, messageID, emoji, models.ReactionOpAdd)
+}
+
+// RemoveReaction removes the user's emoji reaction from a message. Removing a
+// reaction that does not exist is a no-op reported with Changed=false.
+func (s *ChatService) RemoveReaction(userID, messageID uint, emoji string) (*models.ReactionEvent, error) {
+	return s.applyReaction(userID, messageID, emoji, models.ReactionOpRemove)
+}
+
+func (s *ChatService) applyReaction(userID, messageID uint, emoji, op string) (*models.ReactionEvent, error) {
+	emoji = strings.TrimSpace(emoji)
+	if emoji == "" || len(emoji) > models.MaxEmojiLength {
+		return nil, ErrInvalidEmoji
+	}
+
+	chat, err := s.findMessageForMember(userID, messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	changed, summary, err := s.reactionRepo.Apply(op, &models.Reaction{ChatID: chat.ID, UserID: userID, Emoji: emoji})
+	if err != nil {
+		return nil, fmt.Errorf("failed to %s reaction: %w", op, err)
+	}
+
+	event := &models.ReactionEvent{
+		MessageID: chat.ID,
+		ChannelID: chat.ChannelID,
+		UserID:    userID,
+		Emoji:     emoji,
+		Op:        op,
+		Changed:   changed,
+		Reactions: summary,
+	}
+	for _, r := range summary {
+		if r.Emoji == emoji {
+			event.Count = r.Count
+		}
+	}
+	return event, nil
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"chat-service/internal/models"
+	"chat-service/internal/repositories/postgres"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// chatFixture is a group channel with its owner, a member who sends messages
+// and a second member with no rights over them
+type chatFixture struct {
+	db       *gorm.DB
+	chats    *ChatService
+	channels *ChannelService
+	channel  *models.Channel
+
+	owner, sender, other uint
+}
+
+func newChatFixture(t *testing.T) *chatFixture {
+	t.Helper()
+	db := newTestDB(t)
+	channelRepo := postgres.NewChannelRepository(db)
+	userRepo := postgres.NewUserRepository(db)
+	f := &chatFixture{
+		db:       db,
+		chats:    NewChatService(postgres.NewChatRepository(db), channelRepo, postgres.NewReactionRepository(db), userRepo, 0, 0),
+		channels: NewChannelService(channelRepo, userRepo, 0),
+	}
+	f.owner = createTestUser(t, db, false).ID
+	f.sender = createTestUser(t, db, false).ID
+	f.other = createTestUser(t, db, false).ID
+
+	channel, err := f.channels.CreateChannel("chat", f.owner, models.ChannelTypeGroup)
+	if err != nil {
+		t.Fatalf("create channel: %v", err)
+	}
+	f.channel = channel
+	for _, id := range []uint{f.sender, f.other} {
+		if err := f.channels.AddUserToChannel(f.owner, channel.ID, id); err != nil {
+			t.Fatalf("add member: %v", err)
+		}
+	}
+	return f
+}
+
+// send stores a text message from the fixture's sender
+func (f *chatFixture) send(t *testing.T, text string) *models.Chat {
+	t.Helper()
+	id := uuid.New().String()
+	chat := &models.Chat{UUID: &id, SenderID: f.sender, ChannelID: f.channel.ID, Text: &text}
+	if err := f.db.Create(chat).Error; err != nil {
+		t.Fatalf("create message: %v", err)
+	}
+	return chat
+}
+
+func TestDeleteMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    func(f *chatFixture) uint
+		wantErr error
+	}{
+		{"sender deletes", func(f *chatFixture) uint { return f.sender }, nil},
+		{"channel owner deletes", func(f *chatFixture) uint { return f.owner }, nil},
+		{"other member is forbidden", func(f *chatFixture) uint { return f.other }, ErrCannotDelete},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newChatFixture(t)
+			chat := f.send(t, "secret")
+
+			_, deleted, err := f.chats.DeleteMessage(tt.user(f), chat.ID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DeleteMessage error = %v, want %v", err, tt.wantErr)
+			}
+			if deleted != (tt.wantErr == nil) {
+				t.Fatalf("deleted = %v, want %v", deleted, tt.wantErr == nil)
+			}
+
+			history, err := f.channels.GetChatMessagesByChannelWithPagination(f.channel.ID, 20, nil)
+			if err != nil {
+				t.Fatalf("load history: %v", err)
+			}
+			if len(history) != 1 {
+				t.Fatalf("history has %d messages, want 1", len(history))
+			}
+			item := history[0]
+			if item.Deleted != deleted {
+				t.Fatalf("history Deleted = %v, want %v", item.Deleted, deleted)
+			}
+			if deleted && item.Text != nil {
+				t.Fatalf("deleted message text %q returned in history", *item.Text)
+			}
+			if !deleted && (item.Text == nil || *item.Text != "secret") {
+				t.Fatalf("message text was redacted without a delete")
+			}
+		})
+	}
+}
+
+func TestDeleteMessageTwiceIsNoop(t *testing.T) {
+	f := newChatFixture(t)
+	chat := f.send(t, "twice")
+
+	if _, deleted, err := f.chats.DeleteMessage(f.sender, chat.ID); err != nil || !deleted {
+		t.Fatalf("first delete: deleted=%v err=%v", deleted, err)
+	}
+	_, deleted, err := f.chats.DeleteMessage(f.sender, chat.ID)
+	if err != nil {
+		t.Fatalf("second delete: %v", err)
+	}
+	if deleted {
+		t.Fatal("second delete reported a new deletion")
+	}
+}
+
+func TestValidateTextBoundary(t *testing.T) {
+	s := NewChatService(nil, nil, nil, nil, 0, 0)
+	if err := s.ValidateText(strings.Repeat("a", 4096)); err != nil {
+		t.Fatalf("text at the default limit: %v", err)
+	}
+	if err := s.ValidateText(strings.Repeat("a", 4097)); !errors.Is(err, ErrMessageTooLarge) {
+		t.Fatalf("text over the default limit: err = %v, want %v", err, ErrMessageTooLarge)
+	}
+
+	// The limit counts bytes, not characters
+	s = NewChatService(nil, nil, nil, nil, 0, 4)
+	if err := s.ValidateText("éé"); err != nil {
+		t.Fatalf("4 byte text: %v", err)
+	}
+	if err := s.ValidateText("ééa"); !errors.Is(err, ErrMessageTooLarge) {
+		t.Fatalf("5 byte text: err = %v, want %v", err, ErrMessageTooLarge)
+	}
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// EventHubErrors is the webhook event carrying a batch of hub errors
+const EventHubErrors = "hub.errors"
+
+// HubError is one error recorded by a hub instance
+type HubError struct {
+	Source     string    `json:"source"` // e.g. "write", "persist", "redis"
+	InstanceID string    `json:"instanceId"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// HubErrorsWebhookEvent is the webhook payload for a batch of hub errors
+type HubErrorsWebhookEvent struct {
+	Event  string     `json:"event"`
+	Errors []HubError `json:"errors"`
+}
+
+// ErrorNotifier forwards hub errors to a monitoring webhook. Errors are sent in
+// batches of up to batchSize, or every flushInterval, so an error storm does not
+// turn into a request per error. It never blocks the caller: deliveries go
+// through the dispatcher's queue, which dead-letters events it cannot accept.
+type ErrorNotifier struct {
+	dispatcher    *WebhookDispatcher
+	batchSize     int
+	flushInterval time.Duration
+
+	mu      sync.Mutex
+	pending []HubError
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewErrorNotifier(dispatcher *WebhookDispatcher, batchSize int, flushInterval time.Duration) *ErrorNotifier {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	return &ErrorNotifier{
+		dispatcher:    dispatcher,
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+}
+
+// Start launches the periodic flush
+func (n *ErrorNotifier) Start() {
+	go func() {
+		defer close(n.done)
+		if n.flushInterval <= 0 {
+			<-n.stop
+			return
+		}
+		ticker := time.NewTicker(n.flushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-n.stop:
+				return
+			case <-ticker.C:
+				n.flush()
+			}
+		}
+	}()
+}
+
+// Stop sends any errors still pending and stops the periodic flush
+func (n *ErrorNotifier) Stop() {
+	close(n.stop)
+	<-n.done
+	n.flush()
+}
+
+// NotifyError records an error from the given source on the hub instance and
+// sends the batch once it is full
+func (n *ErrorNotifier) NotifyError(source, instanceID string) {
+	n.mu.Lock()
+	n.pending = append(n.pending, HubError{Source: source, InstanceID: instanceID, Timestamp: time.Now().UTC()})
+	var batch []HubError
+	if len(n.pending) >= n.batchSize {
+		batch, n.pending = n.pending, nil
+	}
+	n.mu.Unlock()
+
+	if batch != nil {
+		n.send(batch)
+	}
+}
+
+func (n *ErrorNotifier) flush() {
+	n.mu.Lock()
+	batch := n.pending
+	n.pending = nil
+	n.mu.Unlock()
+
+	if len(batch) > 0 {
+		n.send(batch)
+	}
+}
+
+func (n *ErrorNotifier) send(batch []HubError) {
+	n.dispatcher.Dispatch(EventHubErrors, HubErrorsWebhookEvent{
+		Event:  EventHubErrors,
+		Errors: batch,
+	})
+}
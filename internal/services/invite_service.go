@@ -0,0 +1,114 @@
+package services
+
+import (
+	"chat-service/internal/models"
+	"chat-service/internal/repositories/postgres"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Channel invite errors
+var (
+	ErrInviteNotFound  = errors.New("invite not found")
+	ErrInviteExpired   = errors.New("invite has expired")
+	ErrInviteExhausted = errors.New("invite has reached its maximum uses")
+	ErrNotGroupChannel = errors.New("invites are only available for group channels")
+)
+
+// InviteService manages invitation links that let users join a channel
+type InviteService struct {
+	inviteRepo  *postgres.InviteRepository
+	channelRepo *postgres.ChannelRepository
+}
+
+func NewInviteService(inviteRepo *postgres.InviteRepository, channelRepo *postgres.ChannelRepository) *InviteService {
+	return &InviteService{
+		inviteRepo:  inviteRepo,
+		channelRepo: channelRepo,
+	}
+}
+
+// loadOpenChannel loads a group channel that is not archived
+func (s *InviteService) loadOpenChannel(channelID uint) (*models.Channel, error) {
+	channel, err := s.channelRepo.GetByID(channelID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrChannelNotFound
+		}
+		return nil, fmt.Errorf("failed to find channel: %w", err)
+	}
+	if channel.Type != models.ChannelTypeGroup {
+		return nil, ErrNotGroupChannel
+	}
+	if channel.Archived {
+		return nil, ErrChannelArchived
+	}
+	return channel, nil
+}
+
+// CreateInvite mints an invite link for the channel. Only the owner and admins may create invites.
+func (s *InviteService) CreateInvite(userID, channelID uint, req models.CreateInviteRequest) (*models.Invite, error) {
+	channel, err := s.loadOpenChannel(channelID)
+	if err != nil {
+		return nil, err
+	}
+	role, err := memberRole(s.channelRepo, channel, userID)
+	if err != nil {
+		return nil, err
+	}
+	if channelRoleRank[role] < channelRoleRank[models.ChannelRoleAdmin] {
+		return nil, ErrInsufficientRole
+	}
+
+	token, err := randomHex(16)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate invite token: %w", err)
+	}
+
+	invite := &models.Invite{
+		ChannelID: channelID,
+		Token:     token,
+		CreatedBy: userID,
+		MaxUses:   req.MaxUses,
+	}
+	if req.ExpiresIn > 0 {
+		expiresAt := time.Now().Add(time.Duration(req.ExpiresIn) * time.Second)
+		invite.ExpiresAt = &expiresAt
+	}
+	if err := s.inviteRepo.Create(invite); err != nil {
+		return nil, fmt.Errorf("failed to create invite: %w", err)
+	}
+	return invite, nil
+}
+
+// AcceptInvite joins the user to the invite's channel. Accepting an invite to a
+// channel the user already belongs to succeeds without using it up.
+func (s *InviteService) AcceptInvite(userID uint, token string) (*models.AcceptInviteResponse, error) {
+	invite, err := s.inviteRepo.FindByToken(token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInviteNotFound
+		}
+		return nil, fmt.Errorf("failed to find invite: %w", err)
+	}
+	if _, err := s.loadOpenChannel(invite.ChannelID); err != nil {
+		if errors.Is(err, ErrChannelNotFound) {
+			return nil, ErrInviteNotFound
+		}
+		return nil, err
+	}
+
+	joined, err := s.inviteRepo.Accept(invite, userID, time.Now())
+	switch {
+	case errors.Is(err, postgres.ErrInviteExpired):
+		return nil, ErrInviteExpired
+	case errors.Is(err, postgres.ErrInviteExhausted):
+		return nil, ErrInviteExhausted
+	case err != nil:
+		return nil, fmt.Errorf("failed to accept invite: %w", err)
+	}
+	return &models.AcceptInviteResponse{ChannelID: invite.ChannelID, Joined: joined}, nil
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"chat-service/internal/models"
+	"chat-service/internal/repositories/postgres"
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Most distinct usernames looked up per message; further mentions are ignored
+const maxMentionsPerMessage = 20
+
+const (
+	mentionQueueSize   = 1000
+	mentionWorkerCount = 2
+)
+
+// An @ only starts a mention at the beginning of the text or after a character
+// that cannot be part of a name, so "mail@example.com" is not a mention
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@.-])@([\w.-]+)`)
+
+// ParseMentions returns the distinct usernames mentioned with @username in text,
+// in order of first appearance. Trailing punctuation is not part of the name and
+// names outside the allowed username length are skipped.
+func ParseMentions(text string) []string {
+	var names []string
+	seen := make(map[string]struct{})
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		name := strings.TrimRight(match[1], ".-")
+		if len(name) < 3 || len(name) > 50 {
+			continue
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+		if len(names) == maxMentionsPerMessage {
+			break
+		}
+	}
+	return names
+}
+
+// MentionService resolves @username mentions in channel messages and queues
+// notifications for mentioned users who are offline. Mentions are recorded by a
+// fixed pool of workers started with Start.
+type MentionService struct {
+	userRepo     *postgres.UserRepository
+	mentionRepo  *postgres.MentionRepository
+	redisService *RedisService
+
+	queue  chan *models.Chat
+	wg     sync.WaitGroup
+	mu     sync.RWMutex
+	closed bool
+}
+
+func NewMentionService(userRepo *postgres.UserRepository, mentionRepo *postgres.MentionRepository, redisService *RedisService) *MentionService {
+	return &MentionService{
+		userRepo:     userRepo,
+		mentionRepo:  mentionRepo,
+		redisService: redisService,
+		queue:        make(chan *models.Chat, mentionQueueSize),
+	}
+}
+
+// Start launches the workers that record mentions
+func (s *MentionService) Start() {
+	for i := 0; i < mentionWorkerCount; i++ {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			for chat := range s.queue {
+				s.record(chat)
+			}
+		}()
+	}
+}
+
+// Stop stops accepting messages and waits for queued mentions to be recorded
+func (s *MentionService) Stop() {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// Resolve returns the IDs of the channel members mentioned in the message, sorted.
+// Names that match no member and the sender mentioning themselves are ignored.
+func (s *MentionService) Resolve(chat *models.Chat) ([]uint, error) {
+	if chat.Text == nil || chat.ChannelID == 0 {
+		return nil, nil
+	}
+	names := ParseMentions(*chat.Text)
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	ids, err := s.userRepo.FindChannelMemberIDsByUsernames(chat.ChannelID, names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve mentions: %w", err)
+	}
+	mentioned := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if id != chat.SenderID {
+			mentioned = append(mentioned, id)
+		}
+	}
+	sort.Slice(mentioned, func(i, j int) bool { return mentioned[i] < mentioned[j] })
+	return mentioned, nil
+}
+
+// Record queues the message's resolved mentions to be stored, along with a
+// notification for each mentioned user who is not connected to any instance.
+// It never blocks delivery: when the queue is full the mentions are dropped.
+func (s *MentionService) Record(chat *models.Chat) {
+	if len(chat.Mentions) == 0 {
+		return
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		slog.Warn("Dropping mentions, service stopped", "chatID", chat.ID)
+		return
+	}
+	select {
+	case s.queue <- chat:
+	default:
+		slog.Error("Dropping mentions, queue full", "chatID", chat.ID, "mentions", len(chat.Mentions))
+	}
+}
+
+func (s *MentionService) record(chat *models.Chat) {
+	userIDs := make([]string, len(chat.Mentions))
+	for i, id := range chat.Mentions {
+		userIDs[i] = strconv.FormatUint(uint64(id), 10)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	online, err := s.redisService.AreUsersOnline(ctx, userIDs)
+	cancel()
+	if err != nil {
+		// Better a notice for someone who was online than none for someone who was not
+		slog.Warn("Failed to check presence for mention notifications", "error", err, "chatID", chat.ID)
+		online = nil
+	}
+
+	mentions := make([]models.Mention, len(chat.Mentions))
+	var notifications []models.MentionNotification
+	for i, userID := range chat.Mentions {
+		mentions[i] = models.Mention{ChatID: chat.ID, UserID: userID, ChannelID: chat.ChannelID, SenderID: chat.SenderID}
+		if i < len(online) && online[i] {
+			continue
+		}
+		notifications = append(notifications, models.MentionNotification{
+			UserID:    userID,
+			ChatID:    chat.ID,
+			ChannelID: chat.ChannelID,
+			SenderID:  chat.SenderID,
+		})
+	}
+
+	if err := s.mentionRepo.Create(mentions, notifications); err != nil {
+		slog.Error("Failed to record mentions", "error", err, "chatID", chat.ID)
+	}
+}
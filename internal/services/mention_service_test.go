@@ -0,0 +1,71 @@
+package services
+
+import (
+	"chat-service/internal/models"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseMentions(t *testing.T) {
+	tooMany := make([]string, 0, maxMentionsPerMessage+5)
+	for i := 0; i < maxMentionsPerMessage+5; i++ {
+		tooMany = append(tooMany, "@user"+strings.Repeat("x", i))
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"no mentions", "hello there", nil},
+		{"single", "hi @alice", []string{"alice"}},
+		{"start of text", "@alice hi", []string{"alice"}},
+		{"order of first appearance", "@bob and @alice", []string{"bob", "alice"}},
+		{"duplicates collapse", "@alice @bob @alice", []string{"alice", "bob"}},
+		{"trailing punctuation", "thanks @alice. and @bob-", []string{"alice", "bob"}},
+		{"dots and dashes inside", "@first.last @a-b-c", []string{"first.last", "a-b-c"}},
+		{"email is not a mention", "mail me at bob@example.com", nil},
+		{"double at", "@@alice", nil},
+		{"too short", "@al", nil},
+		{"too long", "@" + strings.Repeat("a", 51), nil},
+		{"longest allowed", "@" + strings.Repeat("a", 50), []string{strings.Repeat("a", 50)}},
+		{"after punctuation", "(@alice)", []string{"alice"}},
+		{"capped", strings.Join(tooMany, " "), func() []string {
+			names := make([]string, maxMentionsPerMessage)
+			for i := range names {
+				names[i] = strings.TrimPrefix(tooMany[i], "@")
+			}
+			return names
+		}()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseMentions(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseMentions(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMentionRecordNeverBlocks(t *testing.T) {
+	s := NewMentionService(nil, nil, nil)
+	chat := &models.Chat{ChannelID: 1, SenderID: 1, Mentions: []uint{2}}
+
+	// No workers are running, so everything past the queue size is dropped
+	for i := 0; i < mentionQueueSize+10; i++ {
+		s.Record(chat)
+	}
+	if got := len(s.queue); got != mentionQueueSize {
+		t.Fatalf("queued %d, want %d", got, mentionQueueSize)
+	}
+
+	s.Record(&models.Chat{ChannelID: 1, SenderID: 1})
+	if got := len(s.queue); got != mentionQueueSize {
+		t.Errorf("chat without mentions was queued")
+	}
+
+	s.Stop()
+	s.Record(chat) // must not panic on the closed queue
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"chat-service/internal/models"
+	"chat-service/internal/repositories/postgres"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrQuotaExceeded is returned when a user has used up their daily message quota
+var ErrQuotaExceeded = errors.New("daily message quota exceeded")
+
+// MessageQuota caps how many messages a user may send per UTC day. The counts live
+// in Redis under a key per user and day, so they hold across instances and expire
+// on their own. Admins are exempt and a limit of 0 disables the quota.
+type MessageQuota struct {
+	redis    *RedisService
+	userRepo *postgres.UserRepository
+	limit    int
+}
+
+func NewMessageQuota(redis *RedisService, userRepo *postgres.UserRepository, limit int) *MessageQuota {
+	return &MessageQuota{
+		redis:    redis,
+		userRepo: userRepo,
+		limit:    limit,
+	}
+}
+
+// quotaDayEnd returns the start of the UTC day after t, when its quota resets
+func quotaDayEnd(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, time.UTC)
+}
+
+// Enabled reports whether a quota is configured
+func (q *MessageQuota) Enabled() bool {
+	return q.limit > 0
+}
+
+// Count records one message by the user on the UTC day of now and returns the
+// day's total. It only talks to Redis; see Check for the verdict.
+func (q *MessageQuota) Count(ctx context.Context, userID uint, now time.Time) (int64, error) {
+	used, err := q.redis.IncrMessageQuota(ctx, userID, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count message: %w", err)
+	}
+	return used, nil
+}
+
+// Refund gives back a message counted on the UTC day of countedAt, for a send
+// that was counted but not stored
+func (q *MessageQuota) Refund(ctx context.Context, userID uint, countedAt time.Time) error {
+	if err := q.redis.DecrMessageQuota(ctx, userID, countedAt); err != nil {
+		return fmt.Errorf("failed to refund message: %w", err)
+	}
+	return nil
+}
+
+// Check decides whether a message that brought the day's count to used may be
+// sent. Past the limit it returns ErrQuotaExceeded along with the usage, unless
+// the user is an admin.
+func (q *MessageQuota) Check(userID uint, used int64, now time.Time) (*models.MessageQuotaUsage, error) {
+	if q.limit <= 0 || used <= int64(q.limit) {
+		return nil, nil
+	}
+
+	// Only users over the limit need the admin lookup
+	exempt, err := q.isExempt(userID)
+	if err != nil {
+		return nil, err
+	}
+	if exempt {
+		return nil, nil
+	}
+	return q.usage(used, now, false), ErrQuotaExceeded
+}
+
+// Usage reports how much of today's quota the user has used
+func (q *MessageQuota) Usage(ctx context.Context, userID uint) (*models.MessageQuotaUsage, error) {
+	now := time.Now()
+	if q.limit <= 0 {
+		return q.usage(0, now, false), nil
+	}
+
+	used, err := q.redis.GetMessageQuota(ctx, userID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load message count: %w", err)
+	}
+	exempt, err := q.isExempt(userID)
+	if err != nil {
+		return nil, err
+	}
+	return q.usage(used, now, exempt), nil
+}
+
+func (q *MessageQuota) isExempt(userID uint) (bool, error) {
+	user, err := q.userRepo.FindByID(userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to find user: %w", err)
+	}
+	return user.IsAdmin, nil
+}
+
+// usage builds the report. Rejected sends are counted in Redis too, so the count
+// is capped at the limit for users the quota applies to.
+func (q *MessageQuota) usage(used int64, now time.Time, exempt bool) *models.MessageQuotaUsage {
+	if !exempt && q.limit > 0 && used > int64(q.limit) {
+		used = int64(q.limit)
+	}
+	return &models.MessageQuotaUsage{
+		Limit:    q.limit,
+		Used:     used,
+		ResetsAt: quotaDayEnd(now),
+		Exempt:   exempt,
+	}
+}
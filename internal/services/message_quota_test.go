@@ -0,0 +1,139 @@
+package services
+
+import (
+	"chat-service/internal/repositories/postgres"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMessageQuotaCountAndRefund(t *testing.T) {
+	redis, mr := newTestRedis(t)
+	quota := NewMessageQuota(redis, nil, 3)
+	ctx := context.Background()
+	now := time.Date(2026, 3, 14, 22, 30, 0, 0, time.UTC)
+	mr.SetTime(now)
+
+	for want := int64(1); want <= 4; want++ {
+		used, err := quota.Count(ctx, 1, now)
+		if err != nil {
+			t.Fatalf("Count: %v", err)
+		}
+		if used != want {
+			t.Fatalf("Count = %d, want %d", used, want)
+		}
+	}
+
+	// Counters expire when the UTC day ends
+	key := messageQuotaKey(1, now)
+	if ttl := mr.TTL(key); ttl <= 0 || ttl > 90*time.Minute {
+		t.Errorf("TTL = %v, want until midnight UTC", ttl)
+	}
+
+	// A refund takes back one unsaved send
+	if err := quota.Refund(ctx, 1, now); err != nil {
+		t.Fatalf("Refund: %v", err)
+	}
+	if used, _ := redis.GetMessageQuota(ctx, 1, now); used != 3 {
+		t.Errorf("after refund used = %d, want 3", used)
+	}
+
+	// Other users and days are counted separately
+	if used, _ := quota.Count(ctx, 2, now); used != 1 {
+		t.Errorf("other user used = %d, want 1", used)
+	}
+	if used, _ := quota.Count(ctx, 1, now.Add(2*time.Hour)); used != 1 {
+		t.Errorf("next day used = %d, want 1", used)
+	}
+}
+
+func TestMessageQuotaRefundAfterRollover(t *testing.T) {
+	redis, mr := newTestRedis(t)
+	quota := NewMessageQuota(redis, nil, 3)
+	ctx := context.Background()
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	mr.SetTime(now)
+
+	if _, err := quota.Count(ctx, 1, now); err != nil {
+		t.Fatalf("Count: %v", err)
+	}
+	if !mr.Exists(messageQuotaKey(1, now)) {
+		t.Fatal("counter not stored")
+	}
+	mr.FastForward(12 * time.Hour)
+
+	// The day's counter is gone; the refund must not create a negative one
+	if err := quota.Refund(ctx, 1, now); err != nil {
+		t.Fatalf("Refund: %v", err)
+	}
+	if mr.Exists(messageQuotaKey(1, now)) {
+		t.Error("refund recreated an expired counter")
+	}
+}
+
+func TestMessageQuotaCheck(t *testing.T) {
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		limit   int
+		used    int64
+		wantErr bool
+	}{
+		{"disabled", 0, 1000, false},
+		{"under the limit", 3, 2, false},
+		{"at the limit", 3, 3, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quota := NewMessageQuota(nil, nil, tt.limit)
+			usage, err := quota.Check(1, tt.used, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if usage != nil {
+				t.Errorf("usage = %+v, want nil for an allowed send", usage)
+			}
+		})
+	}
+}
+
+func TestMessageQuotaUsage(t *testing.T) {
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	quota := NewMessageQuota(nil, nil, 3)
+
+	usage := quota.usage(7, now, false)
+	if usage.Used != 3 || usage.Limit != 3 || usage.Exempt {
+		t.Errorf("usage = %+v, want used capped at the limit", usage)
+	}
+	if want := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC); !usage.ResetsAt.Equal(want) {
+		t.Errorf("ResetsAt = %v, want %v", usage.ResetsAt, want)
+	}
+	if usage := quota.usage(7, now, true); usage.Used != 7 {
+		t.Errorf("exempt usage = %d, want the uncapped count", usage.Used)
+	}
+}
+
+func TestMessageQuotaCheckOverLimit(t *testing.T) {
+	db := newTestDB(t)
+	userRepo := postgres.NewUserRepository(db)
+	quota := NewMessageQuota(nil, userRepo, 3)
+	now := time.Now()
+
+	user := createTestUser(t, db, false)
+	usage, err := quota.Check(user.ID, 4, now)
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("Check error = %v, want ErrQuotaExceeded", err)
+	}
+	if usage == nil || usage.Used != 3 || usage.Limit != 3 {
+		t.Errorf("usage = %+v, want 3 of 3 used", usage)
+	}
+
+	// Admins are exempt
+	admin := createTestUser(t, db, true)
+	if _, err := quota.Check(admin.ID, 4, now); err != nil {
+		t.Errorf("admin Check error = %v, want nil", err)
+	}
+}
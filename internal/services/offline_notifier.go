@@ -0,0 +1,103 @@
+package services
+
+import (
+	"chat-service/internal/models"
+	"chat-service/internal/repositories/postgres"
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+// Webhook event fired when a message is delivered to an offline user
+const EventMessageOffline = "message.offline_delivery"
+
+// OfflineMessageEvent is the webhook payload for an offline delivery.
+// Text is only included when explicitly enabled in config.
+type OfflineMessageEvent struct {
+	Event         string    `json:"event"`
+	Reason        string    `json:"reason"` // direct
+	RecipientID   uint      `json:"recipientId"`
+	SenderID      uint      `json:"senderId"`
+	SenderName    string    `json:"senderName,omitempty"`
+	ChannelID     uint      `json:"channelId"`
+	ChannelType   string    `json:"channelType"`
+	MessageID     uint      `json:"messageId"`
+	MessageUUID   string    `json:"messageUuid,omitempty"`
+	Text          *string   `json:"text,omitempty"`
+	HasAttachment bool      `json:"hasAttachment"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// OfflineNotifier hands messages for offline recipients to the webhook dispatcher
+type OfflineNotifier struct {
+	channelRepo  *postgres.ChannelRepository
+	redisService *RedisService
+	dispatcher   *WebhookDispatcher
+	includeText  bool
+}
+
+func NewOfflineNotifier(channelRepo *postgres.ChannelRepository, redisService *RedisService, dispatcher *WebhookDispatcher, includeText bool) *OfflineNotifier {
+	return &OfflineNotifier{
+		channelRepo:  channelRepo,
+		redisService: redisService,
+		dispatcher:   dispatcher,
+		includeText:  includeText,
+	}
+}
+
+// NotifyMessage fires the offline webhook for every DM recipient that is not
+// connected to any instance. It runs asynchronously and never blocks delivery.
+func (n *OfflineNotifier) NotifyMessage(chat *models.Chat) {
+	go func() {
+		channel, err := n.channelRepo.GetByID(chat.ChannelID)
+		if err != nil {
+			slog.Error("Failed to load channel for offline notification", "error", err, "channelID", chat.ChannelID)
+			return
+		}
+		if channel.Type != models.ChannelTypeDirect {
+			return
+		}
+
+		for _, member := range channel.Members {
+			if member.ID == chat.SenderID {
+				continue
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			online, err := n.redisService.IsUserOnline(ctx, strconv.FormatUint(uint64(member.ID), 10))
+			cancel()
+			if err != nil {
+				slog.Warn("Failed to check presence for offline notification", "error", err, "userID", member.ID)
+				continue
+			}
+			if online {
+				continue
+			}
+
+			n.dispatcher.Dispatch(EventMessageOffline, n.buildEvent(chat, channel.Type, member.ID))
+		}
+	}()
+}
+
+func (n *OfflineNotifier) buildEvent(chat *models.Chat, channelType string, recipientID uint) OfflineMessageEvent {
+	event := OfflineMessageEvent{
+		Event:         EventMessageOffline,
+		Reason:        "direct",
+		RecipientID:   recipientID,
+		SenderID:      chat.SenderID,
+		SenderName:    chat.Sender.Username,
+		ChannelID:     chat.ChannelID,
+		ChannelType:   channelType,
+		MessageID:     chat.ID,
+		HasAttachment: chat.URL != nil,
+		CreatedAt:     chat.CreatedAt,
+	}
+	if chat.UUID != nil {
+		event.MessageUUID = *chat.UUID
+	}
+	if n.includeText {
+		event.Text = chat.Text
+	}
+	return event
+}
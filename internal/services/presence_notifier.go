@@ -0,0 +1,103 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// Webhook events fired when a user connects or disconnects
+const (
+	EventUserConnected    = "user.connected"
+	EventUserDisconnected = "user.disconnected"
+)
+
+// PresenceWebhookEvent is the webhook payload for a presence change
+type PresenceWebhookEvent struct {
+	Event      string    `json:"event"`
+	UserID     string    `json:"userId"`
+	InstanceID string    `json:"instanceId"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// pendingPresence is a presence change waiting out the debounce window
+type pendingPresence struct {
+	online bool
+	timer  *time.Timer
+}
+
+// PresenceNotifier fires connect/disconnect webhooks for presence integrations.
+// Changes are debounced per user: a reconnect within the window cancels the
+// pending disconnect, so a flapping connection produces no events.
+type PresenceNotifier struct {
+	dispatcher *WebhookDispatcher
+	debounce   time.Duration
+
+	mu      sync.Mutex
+	pending map[string]*pendingPresence
+	// Users last reported connected, so a cancelled flap does not repeat an event
+	reported map[string]bool
+}
+
+func NewPresenceNotifier(dispatcher *WebhookDispatcher, debounce time.Duration) *PresenceNotifier {
+	return &PresenceNotifier{
+		dispatcher: dispatcher,
+		debounce:   debounce,
+		pending:    make(map[string]*pendingPresence),
+		reported:   make(map[string]bool),
+	}
+}
+
+// NotifyPresence records a connect (online=true) or disconnect for the user on
+// the given instance. It never blocks.
+func (n *PresenceNotifier) NotifyPresence(userID, instanceID string, online bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if p, ok := n.pending[userID]; ok {
+		if p.online == online {
+			return
+		}
+		// The user flipped back within the window: drop the pending change
+		p.timer.Stop()
+		delete(n.pending, userID)
+		// Users never reported are treated as offline
+		if n.reported[userID] == online {
+			return
+		}
+	}
+
+	timestamp := time.Now().UTC()
+	p := &pendingPresence{online: online}
+	p.timer = time.AfterFunc(n.debounce, func() {
+		n.fire(userID, instanceID, p, timestamp)
+	})
+	n.pending[userID] = p
+}
+
+func (n *PresenceNotifier) fire(userID, instanceID string, p *pendingPresence, timestamp time.Time) {
+	n.mu.Lock()
+	if n.pending[userID] != p {
+		// Superseded after the timer fired
+		n.mu.Unlock()
+		return
+	}
+	delete(n.pending, userID)
+	if p.online {
+		n.reported[userID] = true
+	} else {
+		// Nothing needs remembering once the user is reported offline
+		delete(n.reported, userID)
+	}
+	n.mu.Unlock()
+
+	event := EventUserDisconnected
+	if p.online {
+		event = EventUserConnected
+	}
+	n.dispatcher.Dispatch(event, PresenceWebhookEvent{
+		Event:      event,
+		UserID:     userID,
+		InstanceID: instanceID,
+		Timestamp:  timestamp,
+	})
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"chat-service/internal/models"
+	"chat-service/internal/repositories/postgres"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrNotDirectChannel is returned for operations that only apply to direct messages
+var ErrNotDirectChannel = errors.New("channel is not a direct message channel")
+
+// ReadStateService tracks how far each user has read in their channels
+type ReadStateService struct {
+	readRepo    *postgres.ReadStateRepository
+	chatRepo    *postgres.ChatRepository
+	channelRepo *postgres.ChannelRepository
+}
+
+func NewReadStateService(readRepo *postgres.ReadStateRepository, chatRepo *postgres.ChatRepository, channelRepo *postgres.ChannelRepository) *ReadStateService {
+	return &ReadStateService{
+		readRepo:    readRepo,
+		chatRepo:    chatRepo,
+		channelRepo: channelRepo,
+	}
+}
+
+// MarkDirectReadUpTo marks everything in a DM up to readAt as read. The read pointer
+// is set to the latest message at or before readAt and is never moved backwards.
+func (s *ReadStateService) MarkDirectReadUpTo(userID, channelID uint, readAt time.Time) (*models.ReadStateResponse, error) {
+	channel, err := s.channelRepo.GetByID(channelID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrChannelNotFound
+		}
+		return nil, fmt.Errorf("failed to find channel: %w", err)
+	}
+	if channel.Type != models.ChannelTypeDirect {
+		return nil, ErrNotDirectChannel
+	}
+
+	if err := s.checkMember(userID, channelID); err != nil {
+		return nil, err
+	}
+
+	advanced := false
+	chat, err := s.chatRepo.LatestAtOrBefore(channelID, readAt)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		// Nothing was sent before readAt, so there is nothing to mark
+	case err != nil:
+		return nil, fmt.Errorf("failed to find latest message: %w", err)
+	default:
+		advanced, err = s.readRepo.Advance(&models.ChannelRead{
+			ChannelID:         channelID,
+			UserID:            userID,
+			LastReadMessageID: chat.ID,
+			LastReadAt:        chat.CreatedAt,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to update read pointer: %w", err)
+		}
+	}
+
+	return s.readState(userID, channelID, advanced)
+}
+
+// readState loads the user's current read pointer for the response
+func (s *ReadStateService) readState(userID, channelID uint, advanced bool) (*models.ReadStateResponse, error) {
+	read, err := s.readRepo.Get(channelID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load read pointer: %w", err)
+	}
+
+	resp := &models.ReadStateResponse{ChannelID: channelID, Advanced: advanced}
+	if read != nil {
+		resp.LastReadMessageID = &read.LastReadMessageID
+		resp.LastReadAt = &read.LastReadAt
+	}
+	return resp, nil
+}
+
+// checkMember verifies the user belongs to the channel
+func (s *ReadStateService) checkMember(userID, channelID uint) error {
+	isMember, err := s.channelRepo.IsMember(channelID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check channel membership: %w", err)
+	}
+	if !isMember {
+		return ErrNotChannelMember
+	}
+	return nil
+}
+
+// MarkRead moves the user's read pointer in the channel forward to the given
+// message. Marking an older message than the current pointer is a no-op reported
+// with Advanced=false. The message must belong to the channel.
+func (s *ReadStateService) MarkRead(userID, channelID, messageID uint) (*models.ReadStateResponse, error) {
+	if err := s.checkMember(userID, channelID); err != nil {
+		return nil, err
+	}
+
+	chat, err := s.chatRepo.FindByID(messageID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMessageNotFound
+		}
+		return nil, fmt.Errorf("failed to find message: %w", err)
+	}
+	if chat.ChannelID != channelID {
+		return nil, ErrMessageNotFound
+	}
+
+	advanced, err := s.readRepo.Advance(&models.ChannelRead{
+		ChannelID:         channelID,
+		UserID:            userID,
+		LastReadMessageID: chat.ID,
+		LastReadAt:        chat.CreatedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update read pointer: %w", err)
+	}
+
+	return s.readState(userID, channelID, advanced)
+}
+
+// ListChannelReads returns every member's read pointer so clients can render read markers
+func (s *ReadStateService) ListChannelReads(userID, channelID uint) ([]models.ChannelRead, error) {
+	if err := s.checkMember(userID, channelID); err != nil {
+		return nil, err
+	}
+	reads, err := s.readRepo.ListByChannel(channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list read pointers: %w", err)
+	}
+	return reads, nil
+}
+
+// ChannelSummary returns the channel's latest message, its message count and how
+// many messages from others arrived after the user's read pointer
+func (s *ReadStateService) ChannelSummary(userID, channelID uint) (*models.ChannelSummaryResponse, error) {
+	if err := s.checkMember(userID, channelID); err != nil {
+		return nil, err
+	}
+
+	read, err := s.readRepo.Get(channelID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load read pointer: %w", err)
+	}
+	resp := &models.ChannelSummaryResponse{ChannelID: channelID}
+	var afterID uint
+	if read != nil {
+		afterID = read.LastReadMessageID
+		resp.LastReadMessageID = &read.LastReadMessageID
+	}
+
+	resp.TotalMessages, resp.UnreadCount, err = s.chatRepo.CountTotalAndUnread(channelID, userID, afterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count messages: %w", err)
+	}
+
+	latest, err := s.chatRepo.ListChannelPage(channelID, nil, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load latest message: %w", err)
+	}
+	if len(latest) > 0 {
+		item := latest[0]
+		item.Type = string(models.ChatTypeChannel)
+		redactDeleted(&item)
+		resp.LatestMessage = &item
+	}
+	return resp, nil
+}
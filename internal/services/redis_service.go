@@ -42,6 +42,11 @@ func (r *RedisService) SetUserOnline(ctx context.Context, userID string) error {
 	// Set expiration for status
 	pipe.Expire(ctx, fmt.Sprintf("user:%s:status", userID), 5*time.Minute)
 
+	// Let presence subscribers on every instance know
+	if update, err := presenceUpdate("online", userID); err == nil {
+		pipe.Publish(ctx, PresenceUpdatesChannel, update)
+	}
+
 	_, err := pipe.Exec(ctx)
 	if err != nil {
 		slog.Error("Failed to set user online", "userID", userID, "error", err)
@@ -68,6 +73,10 @@ func (r *RedisService) SetUserOffline(ctx context.Context, userID string) error
 	// Set longer expiration for offline status
 	pipe.Expire(ctx, fmt.Sprintf("user:%s:status", userID), 24*time.Hour)
 
+	if update, err := presenceUpdate("offline", userID); err == nil {
+		pipe.Publish(ctx, PresenceUpdatesChannel, update)
+	}
+
 	_, err := pipe.Exec(ctx)
 	if err != nil {
 		slog.Error("Failed to set user offline", "userID", userID, "error", err)
@@ -78,16 +87,208 @@ func (r *RedisService) SetUserOffline(ctx context.Context, userID string) error
 	return nil
 }
 
+// PresenceUpdatesChannel carries batched presence changes for other instances and watchers
+const PresenceUpdatesChannel = "presence:updates"
+
+// PresenceUpdate is the payload published on PresenceUpdatesChannel
+type PresenceUpdate struct {
+	Status  string   `json:"status"` // "online" | "offline"
+	UserIDs []string `json:"user_ids"`
+}
+
+func presenceUpdate(status string, userIDs ...string) ([]byte, error) {
+	return json.Marshal(PresenceUpdate{Status: status, UserIDs: userIDs})
+}
+
+// Maximum users written per pipeline by SetUsersOffline
+const presenceBatchSize = 100
+
+// SetUsersOffline marks many users offline in batches, publishing one presence
+// update per batch instead of one round trip per user
+func (r *RedisService) SetUsersOffline(ctx context.Context, userIDs []string) error {
+	now := time.Now().Unix()
+
+	for start := 0; start < len(userIDs); start += presenceBatchSize {
+		end := start + presenceBatchSize
+		if end > len(userIDs) {
+			end = len(userIDs)
+		}
+		batch := userIDs[start:end]
+
+		members := make([]interface{}, len(batch))
+		for i, id := range batch {
+			members[i] = id
+		}
+		update, err := presenceUpdate("offline", batch...)
+		if err != nil {
+			return fmt.Errorf("failed to marshal presence update: %w", err)
+		}
+
+		pipe := r.client.GetClient().Pipeline()
+		pipe.SRem(ctx, "online_users", members...)
+		for _, id := range batch {
+			key := fmt.Sprintf("user:%s:status", id)
+			pipe.HSet(ctx, key, map[string]interface{}{
+				"status":     "offline",
+				"last_seen":  now,
+				"updated_at": now,
+			})
+			pipe.Expire(ctx, key, 24*time.Hour)
+		}
+		pipe.Publish(ctx, PresenceUpdatesChannel, update)
+
+		if _, err := pipe.Exec(ctx); err != nil {
+			slog.Error("Failed to set users offline", "count", len(batch), "error", err)
+			return err
+		}
+	}
+
+	slog.Debug("Users set to offline", "count", len(userIDs))
+	return nil
+}
+
+// Per-user presence keys hold the IDs of the instances the user is connected to.
+// Instances refresh the TTL while the user stays connected, so the key expires
+// on its own if an instance dies without cleaning up.
+func presenceKey(userID string) string {
+	return fmt.Sprintf("presence:user:%s", userID)
+}
+
+// releasePresenceScript removes an instance from a user's presence key, deletes
+// the key once no instance is left, and returns how many instances remain
+var releasePresenceScript = redis.NewScript(`
+redis.call("SREM", KEYS[1], ARGV[1])
+local remaining = redis.call("SCARD", KEYS[1])
+if remaining == 0 then
+	redis.call("DEL", KEYS[1])
+end
+return remaining
+`)
+
+// RefreshPresence records that the users are connected to the instance and
+// extends their presence keys by ttl
+func (r *RedisService) RefreshPresence(ctx context.Context, instanceID string, userIDs []string, ttl time.Duration) error {
+	for start := 0; start < len(userIDs); start += presenceBatchSize {
+		end := start + presenceBatchSize
+		if end > len(userIDs) {
+			end = len(userIDs)
+		}
+
+		pipe := r.client.GetClient().Pipeline()
+		for _, id := range userIDs[start:end] {
+			pipe.SAdd(ctx, presenceKey(id), instanceID)
+			pipe.Expire(ctx, presenceKey(id), ttl)
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReleasePresence removes the instance from the users' presence keys and returns
+// the users no instance reports online any more
+func (r *RedisService) ReleasePresence(ctx context.Context, instanceID string, userIDs []string) ([]string, error) {
+	offline := make([]string, 0, len(userIDs))
+	for start := 0; start < len(userIDs); start += presenceBatchSize {
+		end := start + presenceBatchSize
+		if end > len(userIDs) {
+			end = len(userIDs)
+		}
+		batch := userIDs[start:end]
+
+		// Script.Run cannot fall back from EVALSHA to EVAL inside a pipeline,
+		// so the script is sent in full
+		pipe := r.client.GetClient().Pipeline()
+		cmds := make([]*redis.Cmd, len(batch))
+		for i, id := range batch {
+			cmds[i] = releasePresenceScript.Eval(ctx, pipe, []string{presenceKey(id)}, instanceID)
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, err
+		}
+		for i, cmd := range cmds {
+			if remaining, err := cmd.Int64(); err == nil && remaining == 0 {
+				offline = append(offline, batch[i])
+			}
+		}
+	}
+	return offline, nil
+}
+
+// ClearPresence removes the user's presence on every instance
+func (r *RedisService) ClearPresence(ctx context.Context, userID string) error {
+	return r.client.GetClient().Del(ctx, presenceKey(userID)).Err()
+}
+
+// IsUserOnline reports whether any live instance has the user connected
 func (r *RedisService) IsUserOnline(ctx context.Context, userID string) (bool, error) {
-	result, err := r.client.GetClient().SIsMember(ctx, "online_users", userID).Result()
+	n, err := r.client.GetClient().Exists(ctx, presenceKey(userID)).Result()
 	if err != nil {
 		return false, err
 	}
-	return result, nil
+	return n > 0, nil
+}
+
+// AreUsersOnline reports for each user whether any live instance has them online
+func (r *RedisService) AreUsersOnline(ctx context.Context, userIDs []string) ([]bool, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	pipe := r.client.GetClient().Pipeline()
+	cmds := make([]*redis.IntCmd, len(userIDs))
+	for i, id := range userIDs {
+		cmds[i] = pipe.Exists(ctx, presenceKey(id))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+	online := make([]bool, len(userIDs))
+	for i, cmd := range cmds {
+		online[i] = cmd.Val() > 0
+	}
+	return online, nil
+}
+
+// GetUserLastSeen returns when the user's status was last recorded, zero if unknown
+func (r *RedisService) GetUserLastSeen(ctx context.Context, userID string) (time.Time, error) {
+	lastSeen, err := r.client.GetClient().HGet(ctx, fmt.Sprintf("user:%s:status", userID), "last_seen").Int64()
+	if err == redis.Nil {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(lastSeen, 0), nil
 }
 
+// GetOnlineUsers lists online users. Entries left in the online set by an
+// instance that died are dropped once their presence key has expired.
 func (r *RedisService) GetOnlineUsers(ctx context.Context) ([]string, error) {
-	return r.client.GetClient().SMembers(ctx, "online_users").Result()
+	members, err := r.client.GetClient().SMembers(ctx, "online_users").Result()
+	if err != nil {
+		return nil, err
+	}
+	online, err := r.AreUsersOnline(ctx, members)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]string, 0, len(members))
+	var stale []interface{}
+	for i, id := range members {
+		if online[i] {
+			users = append(users, id)
+		} else {
+			stale = append(stale, id)
+		}
+	}
+	if len(stale) > 0 {
+		if err := r.client.GetClient().SRem(ctx, "online_users", stale...).Err(); err != nil {
+			slog.Warn("Failed to prune stale online users", "count", len(stale), "error", err)
+		}
+	}
+	return users, nil
 }
 
 // =============================================================================
@@ -209,6 +410,25 @@ func (r *RedisService) PublishUserNotification(ctx context.Context, userID strin
 	return nil
 }
 
+// HubCommandsChannel carries control commands between WebSocket hub instances
+const HubCommandsChannel = "ws:commands"
+
+func (r *RedisService) PublishHubCommand(ctx context.Context, command interface{}) error {
+	data, err := json.Marshal(command)
+	if err != nil {
+		return fmt.Errorf("failed to marshal command: %w", err)
+	}
+
+	err = r.client.GetClient().Publish(ctx, HubCommandsChannel, data).Err()
+	if err != nil {
+		slog.Error("Failed to publish hub command", "error", err)
+		return err
+	}
+
+	slog.Debug("Published hub command")
+	return nil
+}
+
 func (r *RedisService) Subscribe(ctx context.Context, channels ...string) *redis.PubSub {
 	pubsub := r.client.GetClient().Subscribe(ctx, channels...)
 	slog.Debug("Subscribed to channels", "channels", channels)
@@ -254,6 +474,123 @@ func (r *RedisService) CheckRateLimit(ctx context.Context, key string, limit int
 	return count < int64(limit), nil
 }
 
+// messageQuotaKey is the user's message counter for one UTC day
+func messageQuotaKey(userID uint, day time.Time) string {
+	return fmt.Sprintf("quota:messages:%d:%s", userID, day.UTC().Format("2006-01-02"))
+}
+
+// IncrMessageQuota counts a message by the user on the UTC day of now and returns
+// the day's total. The counter expires when the day ends.
+func (r *RedisService) IncrMessageQuota(ctx context.Context, userID uint, now time.Time) (int64, error) {
+	key := messageQuotaKey(userID, now)
+
+	pipe := r.client.GetClient().TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.ExpireAt(ctx, key, quotaDayEnd(now))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+// decrMessageQuotaScript decrements a quota counter that still exists, so a
+// refund after the day rolled over does not create a negative counter
+var decrMessageQuotaScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	return redis.call("DECR", KEYS[1])
+end
+return 0
+`)
+
+// DecrMessageQuota takes back one message counted on the UTC day of day
+func (r *RedisService) DecrMessageQuota(ctx context.Context, userID uint, day time.Time) error {
+	return decrMessageQuotaScript.Run(ctx, r.client.GetClient(), []string{messageQuotaKey(userID, day)}).Err()
+}
+
+// GetMessageQuota returns how many messages the user has sent on the UTC day of now
+func (r *RedisService) GetMessageQuota(ctx context.Context, userID uint, now time.Time) (int64, error) {
+	used, err := r.client.GetClient().Get(ctx, messageQuotaKey(userID, now)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return used, err
+}
+
+// AcquireSlowModeSlot records a send by the user in a slow-mode channel. When the
+// user already sent within the interval it returns false and the remaining wait.
+func (r *RedisService) AcquireSlowModeSlot(ctx context.Context, channelID, userID string, interval time.Duration) (bool, time.Duration, error) {
+	key := fmt.Sprintf("channel:%s:slowmode:%s", channelID, userID)
+
+	ok, err := r.client.GetClient().SetNX(ctx, key, time.Now().UnixMilli(), interval).Result()
+	if err != nil {
+		return false, 0, err
+	}
+	if ok {
+		return true, 0, nil
+	}
+
+	ttl, err := r.client.GetClient().PTTL(ctx, key).Result()
+	if err != nil {
+		return false, 0, err
+	}
+	if ttl < 0 {
+		// Key expired between the two calls
+		ttl = 0
+	}
+	return false, ttl, nil
+}
+
+// ReleaseSlowModeSlot frees the user's slow-mode slot in the channel
+func (r *RedisService) ReleaseSlowModeSlot(ctx context.Context, channelID, userID string) error {
+	key := fmt.Sprintf("channel:%s:slowmode:%s", channelID, userID)
+	return r.client.GetClient().Del(ctx, key).Err()
+}
+
+// =============================================================================
+// Connection Resume State
+// =============================================================================
+
+// ResumeState is the channel subscription set a disconnected client may resume
+type ResumeState struct {
+	UserID   string   `json:"userId"`
+	Channels []string `json:"channels"`
+}
+
+func resumeKey(token string) string {
+	return "ws:resume:" + token
+}
+
+// SaveResumeStates stores each state under its resume token for ttl in one round trip
+func (r *RedisService) SaveResumeStates(ctx context.Context, states map[string]*ResumeState, ttl time.Duration) error {
+	pipe := r.client.GetClient().Pipeline()
+	for token, state := range states {
+		data, err := json.Marshal(state)
+		if err != nil {
+			return fmt.Errorf("failed to marshal resume state: %w", err)
+		}
+		pipe.Set(ctx, resumeKey(token), data, ttl)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// TakeResumeState loads and deletes the state stored under the resume token, so a
+// token resumes at most once. It returns nil when the token is unknown or expired.
+func (r *RedisService) TakeResumeState(ctx context.Context, token string) (*ResumeState, error) {
+	data, err := r.client.GetClient().GetDel(ctx, resumeKey(token)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state ResumeState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal resume state: %w", err)
+	}
+	return &state, nil
+}
+
 // =============================================================================
 // Migration State Management
 // =============================================================================
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPresenceAcrossInstances(t *testing.T) {
+	r, _ := newTestRedis(t)
+	ctx := context.Background()
+
+	for _, instance := range []string{"a", "b"} {
+		if err := r.RefreshPresence(ctx, instance, []string{"1"}, time.Minute); err != nil {
+			t.Fatalf("refresh presence on %s: %v", instance, err)
+		}
+	}
+
+	offline, err := r.ReleasePresence(ctx, "a", []string{"1"})
+	if err != nil {
+		t.Fatalf("release presence: %v", err)
+	}
+	if len(offline) != 0 {
+		t.Fatalf("user reported offline while still connected to another instance: %v", offline)
+	}
+	if online, err := r.IsUserOnline(ctx, "1"); err != nil || !online {
+		t.Fatalf("IsUserOnline = %v, %v; want true", online, err)
+	}
+
+	offline, err = r.ReleasePresence(ctx, "b", []string{"1"})
+	if err != nil {
+		t.Fatalf("release presence: %v", err)
+	}
+	if len(offline) != 1 || offline[0] != "1" {
+		t.Fatalf("offline = %v, want [1]", offline)
+	}
+	if online, err := r.IsUserOnline(ctx, "1"); err != nil || online {
+		t.Fatalf("IsUserOnline = %v, %v; want false", online, err)
+	}
+}
+
+func TestPresenceExpiresWithoutRefresh(t *testing.T) {
+	r, mr := newTestRedis(t)
+	ctx := context.Background()
+
+	if err := r.RefreshPresence(ctx, "a", []string{"1", "2"}, time.Minute); err != nil {
+		t.Fatalf("refresh presence: %v", err)
+	}
+	mr.FastForward(45 * time.Second)
+	// Only user 2 is still connected, so only its key is refreshed
+	if err := r.RefreshPresence(ctx, "a", []string{"2"}, time.Minute); err != nil {
+		t.Fatalf("refresh presence: %v", err)
+	}
+	mr.FastForward(30 * time.Second)
+
+	online, err := r.AreUsersOnline(ctx, []string{"1", "2"})
+	if err != nil {
+		t.Fatalf("AreUsersOnline: %v", err)
+	}
+	if online[0] || !online[1] {
+		t.Fatalf("online = %v, want [false true]", online)
+	}
+}
+
+func TestGetOnlineUsersDropsExpiredPresence(t *testing.T) {
+	r, mr := newTestRedis(t)
+	ctx := context.Background()
+
+	for _, userID := range []string{"1", "2"} {
+		if err := r.SetUserOnline(ctx, userID); err != nil {
+			t.Fatalf("set user online: %v", err)
+		}
+		if err := r.RefreshPresence(ctx, "a", []string{userID}, time.Minute); err != nil {
+			t.Fatalf("refresh presence: %v", err)
+		}
+	}
+	// The instance holding user 1 dies; user 2 is kept alive by another instance
+	mr.FastForward(45 * time.Second)
+	if err := r.RefreshPresence(ctx, "b", []string{"2"}, time.Minute); err != nil {
+		t.Fatalf("refresh presence: %v", err)
+	}
+	mr.FastForward(30 * time.Second)
+
+	users, err := r.GetOnlineUsers(ctx)
+	if err != nil {
+		t.Fatalf("GetOnlineUsers: %v", err)
+	}
+	if len(users) != 1 || users[0] != "2" {
+		t.Fatalf("online users = %v, want [2]", users)
+	}
+	if stale, _ := mr.IsMember("online_users", "1"); stale {
+		t.Fatal("expired user was not pruned from the online set")
+	}
+}
+
+func TestClearPresence(t *testing.T) {
+	r, _ := newTestRedis(t)
+	ctx := context.Background()
+
+	for _, instance := range []string{"a", "b"} {
+		if err := r.RefreshPresence(ctx, instance, []string{"1"}, time.Minute); err != nil {
+			t.Fatalf("refresh presence on %s: %v", instance, err)
+		}
+	}
+	if err := r.ClearPresence(ctx, "1"); err != nil {
+		t.Fatalf("clear presence: %v", err)
+	}
+	if online, err := r.IsUserOnline(ctx, "1"); err != nil || online {
+		t.Fatalf("IsUserOnline = %v, %v; want false", online, err)
+	}
+}
@@ -0,0 +1,20 @@
+package services
+
+import (
+	"chat-service/internal/database"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// newTestRedis returns a RedisService backed by an in-memory Redis server
+func newTestRedis(t *testing.T) (*RedisService, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client, err := database.NewRedisConnection("redis://" + mr.Addr())
+	if err != nil {
+		t.Fatalf("connect to miniredis: %v", err)
+	}
+	t.Cleanup(func() { client.GetClient().Close() })
+	return NewRedisService(client), mr
+}
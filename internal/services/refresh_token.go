@@ -0,0 +1,132 @@
+package services
+
+import (
+	"chat-service/internal/models"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Refresh token errors
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token was already used, all sessions revoked")
+)
+
+// hashRefreshToken returns the stored form of a refresh token
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// issueRefreshToken creates and stores a new refresh token for the user
+func (s *UserService) issueRefreshToken(userID uint) (string, error) {
+	token, err := randomHex(32)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	err = s.refreshRepo.Create(&models.RefreshToken{
+		UserID:    userID,
+		TokenHash: hashRefreshToken(token),
+		ExpiresAt: time.Now().Add(s.refreshTTL),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to store refresh token: %w", err)
+	}
+	return token, nil
+}
+
+// Refresh exchanges a refresh token for a new access token and a new refresh
+// token. The presented token is revoked. Presenting a token that was already
+// rotated means it leaked, so every refresh token of its user is revoked; a
+// token revoked by logout or a session revocation is just invalid.
+func (s *UserService) Refresh(refreshToken string) (*models.TokenResponse, error) {
+	stored, err := s.refreshRepo.FindByHash(hashRefreshToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, fmt.Errorf("failed to find refresh token: %w", err)
+	}
+
+	if stored.RevokedAt != nil {
+		return nil, s.rejectRevoked(stored)
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	revoked, err := s.refreshRepo.Revoke(stored.ID, models.RefreshTokenRevokedRotated)
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+	if !revoked {
+		// Lost a race with another refresh or a logout using the same token
+		stored, err = s.refreshRepo.FindByHash(stored.TokenHash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find refresh token: %w", err)
+		}
+		return nil, s.rejectRevoked(stored)
+	}
+
+	user, err := s.repo.FindByID(stored.UserID)
+	if err != nil || user.IsBot {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	token, err := s.generateJWT(user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	next, err := s.issueRefreshToken(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.TokenResponse{Token: token, RefreshToken: next}, nil
+}
+
+// rejectRevoked returns the error for presenting a revoked token. Reuse of a
+// rotated token revokes every session of the user. Tokens revoked before reasons
+// were recorded have no reason and are treated as rotated.
+func (s *UserService) rejectRevoked(stored *models.RefreshToken) error {
+	switch stored.RevokedReason {
+	case models.RefreshTokenRevokedRotated, "":
+	default:
+		return ErrInvalidRefreshToken
+	}
+
+	slog.Warn("Refresh token reuse detected, revoking all refresh tokens", "userID", stored.UserID)
+	if err := s.refreshRepo.RevokeAllForUser(stored.UserID); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	return ErrRefreshTokenReused
+}
+
+// RevokeAllSessions revokes every refresh token of the user, so no session can
+// be renewed after a forced logout
+func (s *UserService) RevokeAllSessions(userID uint) error {
+	if err := s.refreshRepo.RevokeAllForUser(userID); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	return nil
+}
+
+// Logout revokes a refresh token. Unknown or already revoked tokens are ignored.
+func (s *UserService) Logout(refreshToken string) error {
+	stored, err := s.refreshRepo.FindByHash(hashRefreshToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to find refresh token: %w", err)
+	}
+	if _, err := s.refreshRepo.Revoke(stored.ID, models.RefreshTokenRevokedLogout); err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"chat-service/internal/models"
+	"chat-service/internal/repositories/postgres"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func newTestUserService(db *gorm.DB) *UserService {
+	return NewUserService(postgres.NewUserRepository(db), postgres.NewRefreshTokenRepository(db), "test-secret", nil, time.Minute, time.Hour)
+}
+
+// issueTestRefreshToken creates a user and issues it a refresh token
+func issueTestRefreshToken(t *testing.T, db *gorm.DB, s *UserService) (uint, string) {
+	t.Helper()
+	user := createTestUser(t, db, false)
+	token, err := s.issueRefreshToken(user.ID)
+	if err != nil {
+		t.Fatalf("issue refresh token: %v", err)
+	}
+	return user.ID, token
+}
+
+func TestRefreshRotatesToken(t *testing.T) {
+	db := newTestDB(t)
+	s := newTestUserService(db)
+	_, token := issueTestRefreshToken(t, db, s)
+
+	resp, err := s.Refresh(token)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if resp.Token == "" || resp.RefreshToken == "" || resp.RefreshToken == token {
+		t.Fatalf("refresh returned %+v, want a new access and refresh token", resp)
+	}
+	stored, err := s.refreshRepo.FindByHash(hashRefreshToken(token))
+	if err != nil {
+		t.Fatalf("find old token: %v", err)
+	}
+	if stored.RevokedAt == nil || stored.RevokedReason != models.RefreshTokenRevokedRotated {
+		t.Fatalf("old token revoked at %v with reason %q, want rotated", stored.RevokedAt, stored.RevokedReason)
+	}
+	if _, err := s.Refresh(resp.RefreshToken); err != nil {
+		t.Fatalf("refresh with the rotated token: %v", err)
+	}
+}
+
+func TestRefreshRejectsExpiredToken(t *testing.T) {
+	db := newTestDB(t)
+	s := newTestUserService(db)
+	_, token := issueTestRefreshToken(t, db, s)
+	err := db.Model(&models.RefreshToken{}).
+		Where("token_hash = ?", hashRefreshToken(token)).
+		Update("expires_at", time.Now().Add(-time.Minute)).Error
+	if err != nil {
+		t.Fatalf("expire token: %v", err)
+	}
+
+	if _, err := s.Refresh(token); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("refresh with an expired token: err = %v, want %v", err, ErrInvalidRefreshToken)
+	}
+}
+
+func TestRefreshRejectsUnknownToken(t *testing.T) {
+	s := newTestUserService(newTestDB(t))
+
+	if _, err := s.Refresh("not-a-token"); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("refresh with an unknown token: err = %v, want %v", err, ErrInvalidRefreshToken)
+	}
+}
+
+func TestRefreshReuseRevokesAllSessions(t *testing.T) {
+	db := newTestDB(t)
+	s := newTestUserService(db)
+	userID, token := issueTestRefreshToken(t, db, s)
+	other, err := s.issueRefreshToken(userID)
+	if err != nil {
+		t.Fatalf("issue second refresh token: %v", err)
+	}
+
+	resp, err := s.Refresh(token)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if _, err := s.Refresh(token); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("reusing a rotated token: err = %v, want %v", err, ErrRefreshTokenReused)
+	}
+	for name, live := range map[string]string{"rotated": resp.RefreshToken, "other session": other} {
+		if _, err := s.Refresh(live); err == nil {
+			t.Fatalf("%s token still refreshes after reuse was detected", name)
+		}
+	}
+}
+
+func TestLogoutRevokesToken(t *testing.T) {
+	db := newTestDB(t)
+	s := newTestUserService(db)
+	userID, token := issueTestRefreshToken(t, db, s)
+	other, err := s.issueRefreshToken(userID)
+	if err != nil {
+		t.Fatalf("issue second refresh token: %v", err)
+	}
+
+	if err := s.Logout(token); err != nil {
+		t.Fatalf("logout: %v", err)
+	}
+	// A logged out token is invalid but is not treated as reuse
+	if _, err := s.Refresh(token); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("refresh after logout: err = %v, want %v", err, ErrInvalidRefreshToken)
+	}
+	if _, err := s.Refresh(other); err != nil {
+		t.Fatalf("logout revoked another session: %v", err)
+	}
+	if err := s.Logout(token); err != nil {
+		t.Fatalf("second logout: %v", err)
+	}
+	if err := s.Logout("not-a-token"); err != nil {
+		t.Fatalf("logout with an unknown token: %v", err)
+	}
+}
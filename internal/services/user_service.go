@@ -3,6 +3,7 @@ package services
 import (
 	"chat-service/internal/models"
 	"chat-service/internal/repositories/postgres"
+	"context"
 	"errors"
 	"fmt"
 	"log"
@@ -30,15 +31,29 @@ var (
 
 type UserService struct {
 	repo        *postgres.UserRepository
+	refreshRepo *postgres.RefreshTokenRepository
 	jwtSecret   string
 	redisClient *redis.Client
+
+	// Lifetimes of access tokens (JWTs) and refresh tokens
+	accessTTL  time.Duration
+	refreshTTL time.Duration
 }
 
-func NewUserService(repo *postgres.UserRepository, jwtSecret string, redisClient *redis.Client) *UserService {
+func NewUserService(repo *postgres.UserRepository, refreshRepo *postgres.RefreshTokenRepository, jwtSecret string, redisClient *redis.Client, accessTTL, refreshTTL time.Duration) *UserService {
+	if accessTTL <= 0 {
+		accessTTL = 24 * time.Hour
+	}
+	if refreshTTL <= 0 {
+		refreshTTL = 30 * 24 * time.Hour
+	}
 	return &UserService{
 		repo:        repo,
+		refreshRepo: refreshRepo,
 		jwtSecret:   jwtSecret,
 		redisClient: redisClient,
+		accessTTL:   accessTTL,
+		refreshTTL:  refreshTTL,
 	}
 }
 
@@ -48,7 +63,7 @@ func (s *UserService) generateJWT(user *models.User) (string, error) {
 		"user_id":  user.ID,
 		"email":    user.Email,
 		"username": user.Username,
-		"exp":      time.Now().Add(time.Hour * 24 * 7).Unix(), // Token expires in 7 days
+		"exp":      time.Now().Add(s.accessTTL).Unix(),
 		"iat":      time.Now().Unix(),
 	}
 
@@ -101,7 +116,7 @@ func (s *UserService) Register(req *models.RegisterRequest) (*models.UserRespons
 
 func (s *UserService) Login(req *models.LoginRequest) (*models.LoginResponse, error) {
 	user, err := s.repo.FindByEmail(req.Email)
-	if err != nil {
+	if err != nil || user.IsBot {
 		return nil, ErrInvalidCredentials
 	}
 
@@ -114,8 +129,14 @@ func (s *UserService) Login(req *models.LoginRequest) (*models.LoginResponse, er
 		return nil, fmt.Errorf("failed to generate token: %w", err)
 	}
 
+	refreshToken, err := s.issueRefreshToken(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	return &models.LoginResponse{
-		Token: token,
+		Token:        token,
+		RefreshToken: refreshToken,
 		User: models.UserResponse{
 			ID:        user.ID,
 			Email:     user.Email,
@@ -153,6 +174,15 @@ func (s *UserService) GetUserByEmail(email string) (*models.UserResponse, error)
 	}, nil
 }
 
+// IsAdmin reports whether the user has administrative privileges
+func (s *UserService) IsAdmin(userID uint) (bool, error) {
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return false, ErrUserNotFound
+	}
+	return user.IsAdmin, nil
+}
+
 // SearchUsersByUsername searches for users by username (partial match)
 func (s *UserService) SearchUsersByUsername(username string) ([]models.UserResponse, error) {
 	users, err := s.repo.SearchUsersByUsername(username)
@@ -175,6 +205,48 @@ func (s *UserService) SearchUsersByUsername(username string) ([]models.UserRespo
 	return responses, nil
 }
 
+// User directory page sizes
+const (
+	defaultDirectoryPageSize = 20
+	maxDirectoryPageSize     = 100
+)
+
+// SearchUsers returns a page of the user directory matching query by username or
+// email. Users the caller has blocked are not listed.
+func (s *UserService) SearchUsers(ctx context.Context, callerID uint, query string, limit, offset int) (*models.UserDirectoryPage, error) {
+	if limit <= 0 {
+		limit = defaultDirectoryPageSize
+	}
+	if limit > maxDirectoryPageSize {
+		limit = maxDirectoryPageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	// Fetch one extra row to learn whether another page follows
+	users, err := s.repo.SearchUsers(ctx, callerID, query, limit+1, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.UserDirectoryPage{Items: []models.UserResponse{}, Limit: limit, Offset: offset}
+	if len(users) > limit {
+		users = users[:limit]
+		page.HasMore = true
+	}
+	for _, user := range users {
+		page.Items = append(page.Items, models.UserResponse{
+			ID:        user.ID,
+			Email:     user.Email,
+			Username:  user.Username,
+			CreatedAt: user.CreatedAt,
+			Avatar:    user.Avatar,
+		})
+	}
+	return page, nil
+}
+
 // UpdateProfile updates the user's profile information
 func (s *UserService) UpdateProfile(userID uint, req *models.UpdateProfileRequest) (*models.UserResponse, error) {
 	// Get current user
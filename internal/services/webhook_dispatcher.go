@@ -0,0 +1,175 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	webhookQueueSize   = 1000
+	webhookWorkerCount = 4
+)
+
+// WebhookEvent is a single outbound webhook delivery
+type WebhookEvent struct {
+	Name    string
+	Payload interface{}
+
+	// Target endpoint and signing secret for the delivery
+	URL    string
+	Secret string
+
+	// Called with the final delivery result (nil on success), if set
+	OnResult func(err error)
+}
+
+// WebhookDispatcher delivers webhook events asynchronously with retries.
+// Events that exhaust their retries (or cannot be queued) are written to the
+// dead-letter log so they can be replayed by an operator. Dispatch sends to the
+// dispatcher's default endpoint; DispatchTo targets any endpoint.
+type WebhookDispatcher struct {
+	url        string
+	secret     string
+	maxRetries int
+	client     *http.Client
+
+	queue  chan WebhookEvent
+	wg     sync.WaitGroup
+	mu     sync.RWMutex
+	closed bool
+}
+
+func NewWebhookDispatcher(url, secret string, timeout time.Duration, maxRetries int) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		url:        url,
+		secret:     secret,
+		maxRetries: maxRetries,
+		client:     &http.Client{Timeout: timeout},
+		queue:      make(chan WebhookEvent, webhookQueueSize),
+	}
+}
+
+// NewPublicWebhookDispatcher creates a dispatcher for user-supplied endpoints. It
+// only connects to public addresses, so webhooks cannot reach loopback, internal
+// hosts or cloud metadata services.
+func NewPublicWebhookDispatcher(timeout time.Duration, maxRetries int) *WebhookDispatcher {
+	d := NewWebhookDispatcher("", "", timeout, maxRetries)
+	d.client = publicOnlyClient(timeout)
+	return d
+}
+
+// Start launches the delivery workers
+func (d *WebhookDispatcher) Start() {
+	for i := 0; i < webhookWorkerCount; i++ {
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			for event := range d.queue {
+				d.deliver(event)
+			}
+		}()
+	}
+}
+
+// Stop stops accepting events and waits for queued deliveries to finish
+func (d *WebhookDispatcher) Stop() {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		close(d.queue)
+	}
+	d.mu.Unlock()
+	d.wg.Wait()
+}
+
+// Dispatch queues an event for the default endpoint without blocking the caller
+func (d *WebhookDispatcher) Dispatch(name string, payload interface{}) {
+	d.enqueue(WebhookEvent{Name: name, Payload: payload, URL: d.url, Secret: d.secret})
+}
+
+// DispatchTo queues an event for the given endpoint without blocking the caller.
+// onResult, if not nil, is called once delivery succeeds or finally fails.
+func (d *WebhookDispatcher) DispatchTo(url, secret, name string, payload interface{}, onResult func(err error)) {
+	d.enqueue(WebhookEvent{Name: name, Payload: payload, URL: url, Secret: secret, OnResult: onResult})
+}
+
+func (d *WebhookDispatcher) enqueue(event WebhookEvent) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.closed {
+		d.deadLetter(event, fmt.Errorf("dispatcher stopped"))
+		return
+	}
+
+	select {
+	case d.queue <- event:
+	default:
+		d.deadLetter(event, fmt.Errorf("delivery queue full"))
+	}
+}
+
+// deliver posts the event, retrying with exponential backoff
+func (d *WebhookDispatcher) deliver(event WebhookEvent) {
+	body, err := json.Marshal(event.Payload)
+	if err != nil {
+		d.deadLetter(event, fmt.Errorf("failed to marshal payload: %w", err))
+		return
+	}
+
+	for attempt := 0; attempt <= d.maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(1<<(attempt-1)) * time.Second)
+		}
+		if err = d.post(event, body); err == nil {
+			slog.Debug("Webhook delivered", "event", event.Name)
+			if event.OnResult != nil {
+				event.OnResult(nil)
+			}
+			return
+		}
+		slog.Warn("Webhook delivery failed", "event", event.Name, "attempt", attempt+1, "error", err)
+	}
+
+	d.deadLetter(event, err)
+}
+
+func (d *WebhookDispatcher) post(event WebhookEvent, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, event.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Notify-Event", event.Name)
+	if event.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(event.Secret))
+		mac.Write(body)
+		req.Header.Set("X-Notify-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// deadLetter records an undeliverable event with its full payload
+func (d *WebhookDispatcher) deadLetter(event WebhookEvent, err error) {
+	slog.Error("DEAD LETTER: webhook event not delivered", "event", event.Name, "url", event.URL, "payload", event.Payload, "error", err)
+	if event.OnResult != nil {
+		event.OnResult(err)
+	}
+}
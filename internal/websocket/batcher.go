@@ -0,0 +1,227 @@
+package websocket
+
+import (
+	"chat-service/internal/models"
+	"chat-service/internal/repositories/postgres"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// errBatchQueueFull is returned when the write-behind queue cannot take another
+// message, e.g. because the database is too slow to keep up
+var errBatchQueueFull = errors.New("message persistence queue is full")
+
+// batchResult is the outcome of persisting one queued chat
+type batchResult int
+
+const (
+	batchInserted  batchResult = iota
+	batchDuplicate             // skipped: the sender's client_msg_id was already stored
+	batchFailed                // dropped after the flush retries ran out
+)
+
+// queuedChat is a chat waiting in the write-behind queue together with what is
+// needed to acknowledge it to its sender once the outcome is known
+type queuedChat struct {
+	chat      *models.Chat
+	client    *Client
+	messageID string // ID of the client frame that sent the chat
+
+	// Gives the sender's daily quota back if the chat is not stored
+	refundQuota func()
+}
+
+// messageBatcher is a write-behind buffer that persists channel messages with
+// multi-row inserts. Messages are broadcast immediately with an ID reserved from
+// the chats sequence, so clients see the same ID that is eventually stored. The
+// sender is only acknowledged once the flush reports the outcome, see onFlushed.
+type messageBatcher struct {
+	chatRepo      *postgres.ChatRepository
+	batchSize     int
+	flushInterval time.Duration
+	maxRetries    int
+	onFlushed     func(item *queuedChat, result batchResult)
+	logger        *slog.Logger
+
+	queue chan *queuedChat
+	done  chan struct{}
+
+	// Pre-reserved chat IDs, guarded by mu
+	mu          sync.Mutex
+	reservedIDs []uint
+}
+
+func newMessageBatcher(chatRepo *postgres.ChatRepository, batchSize int, flushInterval time.Duration, maxRetries int, onFlushed func(*queuedChat, batchResult), logger *slog.Logger) *messageBatcher {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	if flushInterval <= 0 {
+		flushInterval = 500 * time.Millisecond
+	}
+	return &messageBatcher{
+		chatRepo:      chatRepo,
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		maxRetries:    maxRetries,
+		onFlushed:     onFlushed,
+		logger:        logger,
+		queue:         make(chan *queuedChat, batchSize*4),
+		done:          make(chan struct{}),
+	}
+}
+
+// nextID hands out the next reserved chat ID, reserving a new block when exhausted
+func (b *messageBatcher) nextID() (uint, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.reservedIDs) == 0 {
+		ids, err := b.chatRepo.ReserveIDs(b.batchSize)
+		if err != nil {
+			return 0, fmt.Errorf("failed to reserve chat IDs: %w", err)
+		}
+		b.reservedIDs = ids
+	}
+	id := b.reservedIDs[0]
+	b.reservedIDs = b.reservedIDs[1:]
+	return id, nil
+}
+
+// enqueue queues a chat for persistence, preserving submission order. It never
+// blocks: while the queue is full it returns errBatchQueueFull.
+func (b *messageBatcher) enqueue(item *queuedChat) error {
+	select {
+	case b.queue <- item:
+		return nil
+	default:
+		return errBatchQueueFull
+	}
+}
+
+// run flushes the queue whenever a batch fills up or the flush interval elapses.
+// On context cancellation the remaining messages are flushed before returning.
+func (b *messageBatcher) run(ctx context.Context) {
+	defer close(b.done)
+
+	ticker := time.NewTicker(b.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*queuedChat, 0, b.batchSize)
+	for {
+		select {
+		case item := <-b.queue:
+			batch = append(batch, item)
+			if len(batch) >= b.batchSize {
+				b.flush(batch)
+				batch = batch[:0]
+			}
+
+		case <-ticker.C:
+			if len(batch) > 0 {
+				b.flush(batch)
+				batch = batch[:0]
+			}
+
+		case <-ctx.Done():
+			// Drain whatever is still queued
+			for {
+				select {
+				case item := <-b.queue:
+					batch = append(batch, item)
+				default:
+					b.flush(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+// flush writes a batch, retrying with backoff before giving up, and reports the
+// outcome of every chat in it to onFlushed
+func (b *messageBatcher) flush(batch []*queuedChat) {
+	if len(batch) == 0 {
+		return
+	}
+
+	chats := make([]*models.Chat, len(batch))
+	for i, item := range batch {
+		chats[i] = item.chat
+	}
+
+	var err error
+	for attempt := 0; attempt <= b.maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * 100 * time.Millisecond)
+		}
+		var inserted map[uint]bool
+		if inserted, err = b.chatRepo.CreateBatch(chats); err == nil {
+			b.logger.Debug("Flushed message batch", "count", len(batch), "skipped", len(batch)-len(inserted))
+			for _, item := range batch {
+				result := batchInserted
+				if !inserted[item.chat.ID] {
+					result = batchDuplicate
+				}
+				b.onFlushed(item, result)
+			}
+			return
+		}
+		b.logger.Warn("Failed to flush message batch", "attempt", attempt+1, "count", len(batch), "error", err)
+	}
+
+	ids := make([]uint, len(batch))
+	for i, chat := range chats {
+		ids[i] = chat.ID
+	}
+	b.logger.Error("ALERT: dropping message batch after retries", "count", len(batch), "chatIDs", ids, "error", err)
+	for _, item := range batch {
+		b.onFlushed(item, batchFailed)
+	}
+}
+
+// completeQueuedChat acknowledges a batched chat to its sender once its outcome
+// is known. The chat was broadcast when it was queued, so a copy that was not
+// stored is withdrawn from the channel with a delete tombstone.
+func (h *Hub) completeQueuedChat(item *queuedChat, result batchResult) {
+	chat, client := item.chat, item.client
+	clientMsgID := chat.ClientMsgID
+
+	if result == batchInserted {
+		if clientMsgID != nil {
+			h.recentClientMsgs.put(client.userID, *clientMsgID, chat)
+		}
+		h.sendToClient(client, NewMessageAckMessage(item.messageID, client.userID, clientMsgID, chat, false))
+		h.notifyPersisted(chat)
+		return
+	}
+
+	if item.refundQuota != nil {
+		item.refundQuota()
+	}
+	h.broadcastToChannel(strconv.FormatUint(uint64(chat.ChannelID), 10), NewMessageDeleteMessage(uuid.New().String(), client.userID, chat))
+	if clientMsgID != nil {
+		h.recentClientMsgs.remove(client.userID, *clientMsgID)
+	}
+
+	// A duplicate lost the race against the sender's first copy, which is re-acked
+	if result == batchDuplicate && clientMsgID != nil {
+		if existing := h.findResentMessage(client.userID, *clientMsgID); existing != nil {
+			h.sendToClient(client, NewMessageAckMessage(item.messageID, client.userID, clientMsgID, existing, true))
+			return
+		}
+	}
+
+	h.recordError("persist")
+	errMsg := NewErrorMessage(item.messageID, client.userID, "SAVE_FAILED", "Failed to save message")
+	if clientMsgID != nil {
+		errMsg = NewMessageNackMessage(item.messageID, client.userID, *clientMsgID, strconv.FormatUint(uint64(chat.ChannelID), 10), errMsg)
+	}
+	h.sendToClient(client, errMsg)
+}
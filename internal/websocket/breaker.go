@@ -0,0 +1,142 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// BreakerState is the state of the hub's Redis circuit breaker
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"    // Redis calls go through
+	BreakerOpen     BreakerState = "open"      // Redis calls are skipped until the cooldown ends
+	BreakerHalfOpen BreakerState = "half-open" // one probe call is testing recovery
+)
+
+// errRedisCircuitOpen is returned instead of calling Redis while the breaker is open
+var errRedisCircuitOpen = errors.New("redis circuit breaker is open")
+
+// circuitBreaker stops Redis calls after consecutive failures so a Redis outage
+// does not add a timeout to every message. Once the cooldown passes a single
+// probe is let through; its outcome closes or re-opens the breaker.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	state     BreakerState
+	openedAt  time.Time
+	probing   bool // a half-open probe is in flight
+	logger    *slog.Logger
+}
+
+// newCircuitBreaker creates a breaker. A threshold of 0 disables it and every call is allowed.
+func newCircuitBreaker(threshold int, cooldown time.Duration, logger *slog.Logger) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown, state: BreakerClosed, logger: logger}
+}
+
+// allow reports whether a call may be attempted now
+func (b *circuitBreaker) allow() bool {
+	if b.threshold <= 0 {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+		b.logger.Info("Redis circuit half-open, probing")
+		return true
+	case BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// success records a successful call and closes the breaker
+func (b *circuitBreaker) success() {
+	if b.threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != BreakerClosed {
+		b.logger.Info("Redis circuit closed, Redis recovered")
+	}
+	b.state = BreakerClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// failure records a failed call. A failed probe re-opens the breaker at once.
+func (b *circuitBreaker) failure() {
+	if b.threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == BreakerHalfOpen || (b.state == BreakerClosed && b.failures >= b.threshold) {
+		b.state = BreakerOpen
+		b.openedAt = time.Now()
+		b.probing = false
+		b.logger.Warn("Redis circuit open, skipping Redis calls", "failures", b.failures, "cooldown", b.cooldown.String())
+	}
+}
+
+// release gives up an in-flight probe without judging Redis, e.g. on shutdown
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	b.probing = false
+	b.mu.Unlock()
+}
+
+func (b *circuitBreaker) currentState() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// RedisBreakerState returns the state of the hub's Redis circuit breaker
+func (h *Hub) RedisBreakerState() BreakerState {
+	return h.redisBreaker.currentState()
+}
+
+// callRedis runs op through the circuit breaker with its own timeout. It returns
+// errRedisCircuitOpen without calling op while the breaker is open. Errors caused
+// by the hub shutting down do not count against Redis.
+func (h *Hub) callRedis(parent context.Context, timeout time.Duration, op func(ctx context.Context) error) error {
+	if !h.redisBreaker.allow() {
+		return errRedisCircuitOpen
+	}
+
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	err := op(ctx)
+	cancel()
+
+	switch {
+	case err == nil:
+		h.redisBreaker.success()
+	case parent.Err() == nil:
+		h.redisBreaker.failure()
+	default:
+		h.redisBreaker.release()
+	}
+	return err
+}
@@ -0,0 +1,125 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errTestRedis = errors.New("redis unavailable")
+
+// newBreakerHub returns a hub whose Redis breaker opens after two failures and
+// half-opens after cooldown
+func newBreakerHub(t *testing.T, cooldown time.Duration) *Hub {
+	t.Helper()
+	hub := newTestHub(t)
+	hub.redisBreaker = newCircuitBreaker(2, cooldown, hub.logger)
+	return hub
+}
+
+// callCounted runs a Redis call returning err and reports whether op ran
+func callCounted(hub *Hub, err error) (bool, error) {
+	called := false
+	got := hub.callRedis(context.Background(), time.Second, func(ctx context.Context) error {
+		called = true
+		return err
+	})
+	return called, got
+}
+
+func TestRedisBreakerOpensAndCloses(t *testing.T) {
+	hub := newBreakerHub(t, 20*time.Millisecond)
+
+	for i := 0; i < 2; i++ {
+		if state := hub.RedisBreakerState(); state != BreakerClosed {
+			t.Fatalf("state after %d failures = %s, want closed", i, state)
+		}
+		callCounted(hub, errTestRedis)
+	}
+	if state := hub.RedisBreakerState(); state != BreakerOpen {
+		t.Fatalf("state after threshold = %s, want open", state)
+	}
+
+	// While open, calls are skipped without touching Redis
+	called, err := callCounted(hub, nil)
+	if called || !errors.Is(err, errRedisCircuitOpen) {
+		t.Fatalf("call while open: called=%v err=%v, want skipped with errRedisCircuitOpen", called, err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	// The first call after the cooldown is the probe; others wait for its outcome
+	probeStarted := make(chan struct{})
+	releaseProbe := make(chan struct{})
+	probeDone := make(chan error, 1)
+	go func() {
+		probeDone <- hub.callRedis(context.Background(), time.Second, func(ctx context.Context) error {
+			close(probeStarted)
+			<-releaseProbe
+			return nil
+		})
+	}()
+	<-probeStarted
+	if state := hub.RedisBreakerState(); state != BreakerHalfOpen {
+		t.Fatalf("state during probe = %s, want half-open", state)
+	}
+	if called, err := callCounted(hub, nil); called || !errors.Is(err, errRedisCircuitOpen) {
+		t.Fatalf("call during probe: called=%v err=%v, want skipped", called, err)
+	}
+	close(releaseProbe)
+	if err := <-probeDone; err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+
+	if state := hub.RedisBreakerState(); state != BreakerClosed {
+		t.Fatalf("state after successful probe = %s, want closed", state)
+	}
+	if called, err := callCounted(hub, nil); !called || err != nil {
+		t.Fatalf("call after close: called=%v err=%v", called, err)
+	}
+}
+
+func TestRedisBreakerFailedProbeReopens(t *testing.T) {
+	hub := newBreakerHub(t, 20*time.Millisecond)
+	callCounted(hub, errTestRedis)
+	callCounted(hub, errTestRedis)
+
+	time.Sleep(30 * time.Millisecond)
+	if called, _ := callCounted(hub, errTestRedis); !called {
+		t.Fatal("probe was not attempted after the cooldown")
+	}
+	if state := hub.RedisBreakerState(); state != BreakerOpen {
+		t.Fatalf("state after failed probe = %s, want open", state)
+	}
+	if called, _ := callCounted(hub, nil); called {
+		t.Fatal("call attempted right after a failed probe")
+	}
+}
+
+func TestRedisBreakerIgnoresShutdown(t *testing.T) {
+	hub := newBreakerHub(t, time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for i := 0; i < 3; i++ {
+		hub.callRedis(ctx, time.Second, func(ctx context.Context) error { return ctx.Err() })
+	}
+	if state := hub.RedisBreakerState(); state != BreakerClosed {
+		t.Fatalf("state after cancelled calls = %s, want closed", state)
+	}
+}
+
+func TestPublishSkippedWhileBreakerOpen(t *testing.T) {
+	hub, hook := newPublishRetryHub(t, 2, 5)
+	hub.redisBreaker = newCircuitBreaker(2, time.Hour, hub.logger)
+
+	err := hub.publishCommand(context.Background(), hubCommand{Type: hubCommandDisconnect, UserID: "1"})
+	if !errors.Is(err, errRedisCircuitOpen) {
+		t.Fatalf("publish error = %v, want errRedisCircuitOpen", err)
+	}
+	// Two failures open the breaker, so the third attempt never reaches Redis
+	if got := hook.attempts.Load(); got != 2 {
+		t.Fatalf("publish attempted %d times, want 2", got)
+	}
+}
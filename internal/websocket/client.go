@@ -3,17 +3,19 @@ package websocket
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
-	"log/slog"
-
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 )
 
 const (
-	// Time allowed to write a message to the peer
-	writeWait = 10 * time.Second
+	// Time allowed to write a message to the peer when WriteTimeout is not configured
+	defaultWriteWait = 10 * time.Second
 
 	// Time allowed to read the next pong message from the peer
 	pongWait = 60 * time.Second
@@ -21,8 +23,15 @@ const (
 	// Send pings to peer with this period. Must be less than pongWait
 	pingPeriod = (pongWait * 9) / 10
 
-	// Maximum message size allowed from peer
-	maxMessageSize = 512
+	// Frame size limit when MaxFrameBytes is not configured
+	defaultMaxFrameBytes = 8192
+
+	// Frames beyond this multiple of the frame limit close the connection
+	// instead of being answered with an error
+	frameHardLimitFactor = 4
+
+	// Writes taking longer than this are counted as slow
+	slowWriteThreshold = time.Second
 )
 
 type Client struct {
@@ -33,76 +42,281 @@ type Client struct {
 	// Connection state management
 	ctx    context.Context
 	cancel context.CancelFunc
+
+	// Connection metadata for diagnostics, guarded by mu
+	mu             sync.Mutex
+	connectedAt    time.Time
+	lastActivity   time.Time
+	lastHeartbeat  time.Time
+	heartbeatCount int
+	status         PresenceStatus // last status announced to channels
+
+	// Set once send is closed, guarded by mu
+	closed bool
+
+	// Close frame writePump sends after draining send, 0 for none. Guarded by mu.
+	closeCode   int
+	closeReason string
+
+	// When the inactivity probe ping was sent, zero when not probing. Guarded by mu.
+	probeSentAt time.Time
+
+	// Connection quality tracking, guarded by mu
+	quality connectionQuality
+
+	// Outbound write progress for the stalled-writer reaper, guarded by mu.
+	// writeStartedAt is zero when no write is in progress.
+	lastWrite      time.Time
+	writeStartedAt time.Time
+
+	// Last typing start broadcast for this client, guarded by mu
+	lastTypingAt time.Time
+
+	// Per-connection channel message allowance, guarded by mu
+	messageTokens tokenBucket
+
+	// Token a later connection may present to rejoin this one's channels, "" when disabled
+	resumeToken string
+
+	// Large frames are compressed; set before the pumps start
+	compress bool
+
+	// Messages queued close together are written as one batch frame; set before the pumps start
+	coalesce bool
 }
 
 func NewClient(hub *Hub, conn *websocket.Conn, userID string) *Client {
 	ctx, cancel := context.WithCancel(context.Background())
+	now := time.Now()
 
 	return &Client{
-		hub:    hub,
-		conn:   conn,
-		send:   make(chan []byte, 256),
-		userID: userID,
-		ctx:    ctx,
-		cancel: cancel,
+		hub:          hub,
+		conn:         conn,
+		send:         make(chan []byte, 256),
+		userID:       userID,
+		ctx:          ctx,
+		cancel:       cancel,
+		connectedAt:  now,
+		lastActivity: now,
+		lastWrite:    now,
+		status:       PresenceOnline,
+	}
+}
+
+// trySend queues data without blocking. It reports whether the data was queued
+// and whether the client was already closed.
+func (c *Client) trySend(data []byte) (sent bool, closed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return false, true
+	}
+	select {
+	case c.send <- data:
+		return true, false
+	default:
+		return false, false
 	}
 }
 
+// closeWith closes the client like close, but has writePump finish with a close
+// frame carrying the given code once the queued messages are flushed
+func (c *Client) closeWith(code int, reason string) {
+	c.mu.Lock()
+	if !c.closed {
+		c.closeCode = code
+		c.closeReason = reason
+	}
+	c.mu.Unlock()
+	c.close()
+}
+
+// close closes the send channel exactly once, which stops writePump
+func (c *Client) close() {
+	c.mu.Lock()
+	if !c.closed {
+		c.closed = true
+		close(c.send)
+	}
+	c.mu.Unlock()
+	c.cancel()
+}
+
+// touch records inbound activity on the connection
+func (c *Client) touch() {
+	c.mu.Lock()
+	c.lastActivity = time.Now()
+	c.mu.Unlock()
+}
+
+// recordHeartbeat records a pong received from the peer. Heartbeats prove the
+// connection is alive but do not count as user activity.
+func (c *Client) recordHeartbeat() {
+	c.mu.Lock()
+	c.lastHeartbeat = time.Now()
+	c.heartbeatCount++
+	c.quality.recordPong(c.lastHeartbeat)
+	c.mu.Unlock()
+}
+
+// writeWait returns the deadline for a single write to a client
+func (h *Hub) writeWait() time.Duration {
+	if h.config.WriteTimeout > 0 {
+		return h.config.WriteTimeout
+	}
+	return defaultWriteWait
+}
+
+// maxFrameBytes returns the configured inbound frame limit
+func (h *Hub) maxFrameBytes() int {
+	if h.config.MaxFrameBytes > 0 {
+		return h.config.MaxFrameBytes
+	}
+	return defaultMaxFrameBytes
+}
+
 func (c *Client) readPump(h *Hub) {
 	defer func() {
-		h.unregister <- c
+		select {
+		case h.unregister <- c:
+		case <-h.ctx.Done():
+		}
 		_ = c.conn.Close()
 	}()
 
-	c.conn.SetReadLimit(maxMessageSize)
-	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	readTimeout := h.readTimeout()
+	maxFrameBytes := h.maxFrameBytes()
+
+	c.conn.SetReadLimit(int64(maxFrameBytes * frameHardLimitFactor))
+	c.conn.SetReadDeadline(time.Now().Add(readTimeout))
 	c.conn.SetPingHandler(nil)
 	c.conn.SetPongHandler(func(string) error {
-		c.conn.SetReadDeadline(time.Now().Add(pongWait))
+		c.conn.SetReadDeadline(time.Now().Add(readTimeout))
+		c.recordHeartbeat()
 		return nil
 	})
 
 	for {
-		_, messageBytes, err := c.conn.ReadMessage()
+		frameType, messageBytes, err := c.conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-				slog.Error("readPump error", "error", err, "userID", c.userID)
+				h.logger.Error("readPump error", "error", err, "userID", c.userID)
 			}
 			break
 		}
-		// push the message to the hub broadcast channel
-		c.hub.broadcast <- messageBytes
+		c.touch()
+		c.conn.SetReadDeadline(time.Now().Add(readTimeout))
+
+		if frameType != websocket.TextMessage {
+			h.logger.Warn("Rejected non-text frame", "userID", c.userID, "frameType", frameType)
+			h.sendToClient(c, NewErrorMessage(uuid.New().String(), c.userID, "UNSUPPORTED_FRAME", "only text frames carrying JSON are supported"))
+			continue
+		}
+
+		if len(messageBytes) > maxFrameBytes {
+			// Misbehaving client rather than a hub fault, so only counted in metrics
+			h.metrics.countError("message_too_large")
+			h.logger.Warn("Rejected oversize frame", "userID", c.userID, "bytes", len(messageBytes))
+			h.sendToClient(c, NewErrorMessage(uuid.New().String(), c.userID, "MESSAGE_TOO_LARGE", fmt.Sprintf("frame is %d bytes, limit is %d", len(messageBytes), maxFrameBytes)))
+			continue
+		}
+
+		message, err := DecodeMessage(messageBytes)
+		if errors.Is(err, ErrIncompleteFrame) {
+			h.logger.Warn("Rejected incomplete frame", "userID", c.userID, "bytes", len(messageBytes))
+			h.sendToClient(c, NewErrorMessage(uuid.New().String(), c.userID, "INCOMPLETE_FRAME", err.Error()))
+			continue
+		}
+		if errors.Is(err, ErrUnsupportedVersion) {
+			h.sendToClient(c, NewErrorMessage(uuid.New().String(), c.userID, "UNSUPPORTED_VERSION", err.Error()))
+			continue
+		}
+		if err != nil {
+			h.logger.Warn("Rejected malformed message", "userID", c.userID, "error", err)
+			h.sendToClient(c, NewErrorMessage(uuid.New().String(), c.userID, "INVALID_MESSAGE", err.Error()))
+			continue
+		}
+		clientMessage := &ClientMessage{Client: c, Message: message}
+		if readPumpActions[message.Type] {
+			h.handleClientMessage(clientMessage)
+			continue
+		}
+
+		// push the message to the hub broadcast channel; the hub loop is gone
+		// once the hub shuts down
+		select {
+		case h.broadcast <- clientMessage:
+		case <-h.ctx.Done():
+			return
+		}
 	}
 }
 
 func (c *Client) writePump() {
+	defer c.hub.writers.Done()
 	defer func() {
 		_ = c.conn.Close()
 	}()
 
-	c.conn.SetWriteDeadline(time.Now().Add(pongWait))
-	c.conn.SetPongHandler(func(string) error {
-		c.conn.SetWriteDeadline(time.Now().Add(pongWait))
-		return nil
-	})
-
+	// Pongs are handled by readPump, which records them for the heartbeat and
+	// quality checks. Each write sets its own deadline.
 	for msgByte := range c.send {
-		c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+		if c.coalesce {
+			if frames := c.collectBatch(msgByte); len(frames) > 1 {
+				if !c.writeBatch(frames) {
+					return
+				}
+				continue
+			}
+		}
+
+		c.conn.SetWriteDeadline(time.Now().Add(c.hub.writeWait()))
 		// Convert the msg from byte[] to JSON and send
 		var msg Message
 		if err := json.Unmarshal(msgByte, &msg); err != nil {
-			slog.Error("Failed to unmarshal message", "error", err)
+			c.hub.logger.Error("Failed to unmarshal message", "error", err)
 			errMsg := NewErrorMessage(msg.ID, msg.UserID, "ERROR", "Failed to unmarshal message")
 			if err := c.conn.WriteJSON(errMsg); err != nil {
-				slog.Error("write error", "userID", c.userID, "error", err)
+				c.hub.logger.Error("write error", "userID", c.userID, "error", err)
 			}
 			continue
 		}
-		if err := c.conn.WriteJSON(msg); err != nil {
-			slog.Error("write error", "userID", c.userID, "error", err)
+		if !c.timedWrite(msgByte, func() error { return c.conn.WriteJSON(msg) }) {
 			return
 		}
 	}
+
+	c.mu.Lock()
+	code, reason := c.closeCode, c.closeReason
+	c.mu.Unlock()
+	if code != 0 {
+		msg := websocket.FormatCloseMessage(code, reason)
+		if err := c.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(c.hub.writeWait())); err != nil {
+			c.hub.logger.Debug("Failed to send close frame", "userID", c.userID, "error", err)
+		}
+	}
+}
+
+// timedWrite runs write for payload with the write deadline, compression and
+// slow write tracking applied. A failed write drops the client and returns false.
+func (c *Client) timedWrite(payload []byte, write func() error) bool {
+	c.conn.SetWriteDeadline(time.Now().Add(c.hub.writeWait()))
+	c.prepareWrite(payload)
+	start := c.beginWrite()
+	err := write()
+	c.endWrite(err == nil)
+	if elapsed := time.Since(start); elapsed > slowWriteThreshold {
+		c.recordSlowWrite(elapsed)
+	}
+	if err != nil {
+		c.recordWriteError()
+		c.hub.logger.Error("write error", "userID", c.userID, "error", err)
+		// Abandon the connection now rather than waiting for the read side to notice
+		c.hub.dropClient(c)
+		return false
+	}
+	return true
 }
 
 /**
@@ -114,18 +328,28 @@ func (c *Client) writePump() {
  */
 func ServeWS(hub *Hub, w http.ResponseWriter, r *http.Request, userID string) {
 	// Upgrade the connection to WebSocket protocol from HTTP 1.1 to websocket
-	conn, err := Upgrader.Upgrade(w, r, nil)
+	conn, err := hub.upgrader.Upgrade(w, r, nil)
 	if err != nil {
-		slog.Error("Failed to upgrade WebSocket connection", "userID", userID, "error", err)
+		hub.logger.Error("Failed to upgrade WebSocket connection", "userID", userID, "error", err)
 		return
 	}
 
 	client := NewClient(hub, conn, userID)
+	client.compress = hub.wantsCompression(r)
+	client.coalesce = hub.wantsCoalescing(r)
+	client.resumeToken = hub.newResumeToken()
 
 	// Register client with hub and wait for confirmation
-	hub.register <- client
+	select {
+	case hub.register <- client:
+	case <-hub.ctx.Done():
+		// Hub stopped between the upgrade and registration
+		_ = conn.Close()
+		return
+	}
 
 	// Start the pumps after registration
+	hub.writers.Add(1)
 	go client.writePump()
 	go client.readPump(hub)
 }
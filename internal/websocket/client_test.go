@@ -0,0 +1,183 @@
+package websocket
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// startReadPump connects a test client to a server side Client running readPump
+// and returns the client connection and the server side Client
+func startReadPump(t *testing.T, hub *Hub) (*websocket.Conn, *Client) {
+	t.Helper()
+	clients := make(chan *Client, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade: %v", err)
+			return
+		}
+		client := NewClient(hub, conn, "1")
+		clients <- client
+		client.readPump(hub)
+	}))
+	t.Cleanup(server.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn, <-clients
+}
+
+func TestReadPumpRejectsBadFrames(t *testing.T) {
+	tests := []struct {
+		name      string
+		frameType int
+		payload   string
+		wantCode  string
+	}{
+		{"binary frame", websocket.BinaryMessage, `{"id":"1","type":"channel.join","data":{}}`, "UNSUPPORTED_FRAME"},
+		{"oversize frame", websocket.TextMessage, `{"id":"1","type":"channel.join","data":{"channel_id":"` + strings.Repeat("9", 100) + `"}}`, "MESSAGE_TOO_LARGE"},
+		{"truncated frame", websocket.TextMessage, `{"id":"1","type":"channel.join"`, "INCOMPLETE_FRAME"},
+		{"trailing bytes", websocket.TextMessage, `{"id":"1","type":"channel.join","data":{}} {}`, "INCOMPLETE_FRAME"},
+		{"unsupported version", websocket.TextMessage, `{"id":"1","version":9,"type":"channel.join","data":{}}`, "UNSUPPORTED_VERSION"},
+		{"unknown field", websocket.TextMessage, `{"id":"1","type":"channel.join","data":{},"x":1}`, "INVALID_MESSAGE"},
+	}
+
+	hub := newTestHub(t)
+	hub.config.MaxFrameBytes = 100
+	conn, client := startReadPump(t, hub)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := conn.WriteMessage(tt.frameType, []byte(tt.payload)); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+
+			select {
+			case data := <-client.send:
+				var msg Message
+				if err := json.Unmarshal(data, &msg); err != nil {
+					t.Fatalf("decode reply: %v", err)
+				}
+				if msg.Type != MessageTypeError || msg.Data["code"] != tt.wantCode {
+					t.Fatalf("reply = %s %v, want error %s", msg.Type, msg.Data["code"], tt.wantCode)
+				}
+			case <-hub.broadcast:
+				t.Fatal("bad frame was passed to the hub")
+			case <-time.After(2 * time.Second):
+				t.Fatal("no reply to bad frame")
+			}
+		})
+	}
+
+	// The connection survives bad frames and still delivers valid ones
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(`{"id":"2","type":"channel.join","data":{"channel_id":"5"}}`)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	select {
+	case cm := <-hub.broadcast:
+		if cm.Message.ID != "2" || cm.Client != client {
+			t.Errorf("hub got message %q from %p, want %q from %p", cm.Message.ID, cm.Client, "2", client)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("valid frame was not passed to the hub")
+	}
+}
+
+func TestReadPumpStopsWhenHubStops(t *testing.T) {
+	hub := newTestHub(t)
+	conn, _ := startReadPump(t, hub)
+
+	// Nothing runs the hub loop, so the frame can only be dropped on shutdown
+	hub.cancel()
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(`{"id":"1","type":"channel.join","data":{"channel_id":"5"}}`)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err := conn.ReadMessage()
+	if err == nil {
+		t.Fatal("read a frame, want the connection closed")
+	}
+	var netErr interface{ Timeout() bool }
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		t.Fatal("readPump still blocked after the hub stopped")
+	}
+}
+
+func TestReadPumpHandlesChannelMessagesOffHubLoop(t *testing.T) {
+	hub := newTestHub(t)
+	conn, client := startReadPump(t, hub)
+	hub.mu.Lock()
+	hub.clients[client.userID] = client
+	hub.mu.Unlock()
+
+	// Nothing runs the hub loop, so only readPump can answer
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(`{"id":"1","type":"channel.message","data":{"channel_id":"x","text":"hi"}}`)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	select {
+	case data := <-client.send:
+		var msg Message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			t.Fatalf("decode reply: %v", err)
+		}
+		if msg.Type != MessageTypeError || msg.Data["code"] != "INVALID_CHANNEL_ID" {
+			t.Fatalf("reply = %s %v, want error INVALID_CHANNEL_ID", msg.Type, msg.Data["code"])
+		}
+	case <-hub.broadcast:
+		t.Fatal("channel message was passed to the hub loop")
+	case <-time.After(2 * time.Second):
+		t.Fatal("channel message was not handled")
+	}
+}
+
+func TestReadPumpFrameSizeBoundary(t *testing.T) {
+	// frame returns a channel.join frame of exactly n bytes, padded in its ID
+	frame := func(n int) []byte {
+		base := `{"id":"","type":"channel.join","data":{"channel_id":"5"}}`
+		return []byte(`{"id":"` + strings.Repeat("a", n-len(base)) + base[7:])
+	}
+
+	hub := newTestHub(t)
+	hub.config.MaxFrameBytes = 200
+	conn, client := startReadPump(t, hub)
+
+	if err := conn.WriteMessage(websocket.TextMessage, frame(200)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	select {
+	case <-hub.broadcast:
+	case data := <-client.send:
+		t.Fatalf("frame at the limit was rejected: %s", data)
+	case <-time.After(2 * time.Second):
+		t.Fatal("frame at the limit was not passed to the hub")
+	}
+
+	if err := conn.WriteMessage(websocket.TextMessage, frame(201)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	select {
+	case data := <-client.send:
+		var msg Message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			t.Fatalf("decode reply: %v", err)
+		}
+		if msg.Type != MessageTypeError || msg.Data["code"] != "MESSAGE_TOO_LARGE" {
+			t.Fatalf("reply = %s %v, want error MESSAGE_TOO_LARGE", msg.Type, msg.Data["code"])
+		}
+	case <-hub.broadcast:
+		t.Fatal("frame over the limit was passed to the hub")
+	case <-time.After(2 * time.Second):
+		t.Fatal("no reply to frame over the limit")
+	}
+}
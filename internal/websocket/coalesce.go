@@ -0,0 +1,75 @@
+package websocket
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Frames per coalesced batch; a longer burst continues in the next batch
+const maxCoalescedFrames = 50
+
+// Type of the frame that carries coalesced messages. It wraps complete
+// messages and is not itself a Message.
+const batchFrameType = "batch"
+
+// batchFrame is written to coalescing clients when several messages were
+// queued within the coalescing window
+type batchFrame struct {
+	Type     string            `json:"type"`
+	Messages []json.RawMessage `json:"messages"`
+}
+
+// wantsCoalescing reports whether the client opted in to batch frames by
+// connecting with ?batch=true and coalescing is enabled
+func (h *Hub) wantsCoalescing(r *http.Request) bool {
+	if !h.config.CoalesceEnabled || h.config.CoalesceWindow <= 0 {
+		return false
+	}
+	batch, _ := strconv.ParseBool(r.URL.Query().Get("batch"))
+	return batch
+}
+
+// collectBatch gathers the frames queued within the coalescing window after
+// first. It stops early when the batch is full or send is closed.
+func (c *Client) collectBatch(first []byte) [][]byte {
+	frames := [][]byte{first}
+	timer := time.NewTimer(c.hub.config.CoalesceWindow)
+	defer timer.Stop()
+
+	for len(frames) < maxCoalescedFrames {
+		select {
+		case frame, ok := <-c.send:
+			if !ok {
+				return frames
+			}
+			frames = append(frames, frame)
+		case <-timer.C:
+			return frames
+		}
+	}
+	return frames
+}
+
+// writeBatch writes several queued messages as one batch frame. It returns
+// false after a failed write, once the client has been dropped.
+func (c *Client) writeBatch(frames [][]byte) bool {
+	messages := make([]json.RawMessage, 0, len(frames))
+	for _, frame := range frames {
+		if frame != nil {
+			messages = append(messages, frame)
+		}
+	}
+	payload, err := json.Marshal(batchFrame{Type: batchFrameType, Messages: messages})
+	if err != nil {
+		c.hub.logger.Error("Failed to marshal batch frame", "userID", c.userID, "error", err)
+		return true
+	}
+
+	return c.timedWrite(payload, func() error {
+		return c.conn.WriteMessage(websocket.TextMessage, payload)
+	})
+}
@@ -0,0 +1,113 @@
+package websocket
+
+import (
+	"chat-service/internal/config"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// dialServedClient connects userID through ServeWS on a running hub and returns
+// the client side of the connection once the connect frame has arrived
+func dialServedClient(t *testing.T, hub *Hub, userID, query string) *websocket.Conn {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ServeWS(hub, w, r, userID)
+	}))
+	t.Cleanup(server.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+query, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	var connect Message
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if err := conn.ReadJSON(&connect); err != nil || connect.Type != MessageTypeConnect {
+		t.Fatalf("connect frame: %s, %v", connect.Type, err)
+	}
+	return conn
+}
+
+func TestCoalescedBurstArrivesInOneBatch(t *testing.T) {
+	cfg := config.WebSocketConfig{AllowAllOrigins: true, CoalesceEnabled: true, CoalesceWindow: 50 * time.Millisecond}
+	hub := NewHub(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), cfg)
+	t.Cleanup(hub.cancel)
+	hub.redisService, _ = newTestRedis(t)
+	go hub.Run()
+
+	batching := dialServedClient(t, hub, "1", "?batch=true")
+	plain := dialServedClient(t, hub, "2", "")
+	// Separate channels, so neither client is told about the other joining
+	for userID, channelID := range map[string]string{"1": "10", "2": "20"} {
+		if err := hub.JoinChannel(userID, channelID); err != nil {
+			t.Fatalf("join channel: %v", err)
+		}
+	}
+
+	for i := 0; i < 10; i++ {
+		for _, channelID := range []string{"10", "20"} {
+			hub.BroadcastToChannel(channelID, NewMessage(strconv.Itoa(i), MessageTypeChannelMessage, "3", map[string]interface{}{"text": "burst"}))
+		}
+	}
+
+	batching.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var batch struct {
+		Type     string    `json:"type"`
+		Messages []Message `json:"messages"`
+	}
+	if err := batching.ReadJSON(&batch); err != nil {
+		t.Fatalf("read batch: %v", err)
+	}
+	if batch.Type != batchFrameType || len(batch.Messages) != 10 {
+		t.Fatalf("got a %q frame with %d messages, want one batch of 10", batch.Type, len(batch.Messages))
+	}
+	for i, msg := range batch.Messages {
+		if msg.ID != strconv.Itoa(i) {
+			t.Fatalf("batch message %d has ID %q, want %d", i, msg.ID, i)
+		}
+	}
+
+	// A client that did not opt in gets each message in its own frame
+	for i := 0; i < 10; i++ {
+		var msg Message
+		plain.SetReadDeadline(time.Now().Add(2 * time.Second))
+		if err := plain.ReadJSON(&msg); err != nil {
+			t.Fatalf("read frame %d: %v", i, err)
+		}
+		if msg.Type != MessageTypeChannelMessage || msg.ID != strconv.Itoa(i) {
+			t.Fatalf("frame %d = %s %q, want channel message %d", i, msg.Type, msg.ID, i)
+		}
+	}
+}
+
+func TestWantsCoalescing(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   config.WebSocketConfig
+		query string
+		want  bool
+	}{
+		{"opted in", config.WebSocketConfig{CoalesceEnabled: true, CoalesceWindow: time.Millisecond}, "?batch=true", true},
+		{"not opted in", config.WebSocketConfig{CoalesceEnabled: true, CoalesceWindow: time.Millisecond}, "", false},
+		{"disabled", config.WebSocketConfig{CoalesceWindow: time.Millisecond}, "?batch=true", false},
+		{"no window", config.WebSocketConfig{CoalesceEnabled: true}, "?batch=true", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := &Hub{config: tt.cfg}
+			r := httptest.NewRequest(http.MethodGet, "/ws"+tt.query, nil)
+			if got := hub.wantsCoalescing(r); got != tt.want {
+				t.Fatalf("wantsCoalescing = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
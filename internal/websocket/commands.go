@@ -0,0 +1,191 @@
+package websocket
+
+import (
+	"chat-service/internal/services"
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Commands exchanged between hub instances over Redis
+const (
+	hubCommandDisconnect    = "disconnect_user"
+	hubCommandDirectMessage = "direct_message"
+
+	hubCommandChannelSettings = "channel_settings" // drop cached channel settings
+)
+
+type hubCommand struct {
+	Type    string          `json:"type"`
+	UserID  string          `json:"user_id"`
+	Reason  string          `json:"reason,omitempty"`
+	Payload json.RawMessage `json:"payload,omitempty"` // encoded frame for direct_message
+	Origin  string          `json:"origin"`            // instance ID of the publisher
+
+	Trace map[string]string `json:"trace,omitempty"` // trace context of the publisher, see injectTrace
+
+	ChannelID uint `json:"channel_id,omitempty"` // for channel_settings
+}
+
+// ForceLogout closes the user's connection on this instance and asks every other
+// instance to do the same. It reports whether a local connection was closed.
+// The command is published in the background since publishCommand may retry
+// with backoff, which must not hold up the HTTP request.
+func (h *Hub) ForceLogout(userID, reason string) bool {
+	closed := h.disconnectLocal(userID, reason)
+
+	go func() {
+		cmd := hubCommand{Type: hubCommandDisconnect, UserID: userID, Reason: reason, Origin: h.instanceID}
+		if err := h.publishCommand(context.Background(), cmd); err != nil {
+			h.logger.Error("Failed to publish disconnect command", "userID", userID, "error", err)
+		}
+	}()
+
+	// The user must not appear online anywhere after a forced logout
+	err := h.callRedis(h.ctx, redisOpTimeout, func(ctx context.Context) error {
+		return h.redisService.ClearPresence(ctx, userID)
+	})
+	if err != nil {
+		h.logger.Warn("Failed to clear presence", "userID", userID, "error", err)
+	}
+	h.setPresence(userID, false)
+	return closed
+}
+
+// publishCommand sends a command to the other instances. Failed publishes are
+// retried with exponential backoff so a brief Redis outage does not drop the
+// command; retries stop early when the hub shuts down or the Redis circuit opens.
+// The command carries the trace context of its span so the receiving instances
+// continue the trace.
+func (h *Hub) publishCommand(ctx context.Context, cmd hubCommand) (err error) {
+	ctx, span := h.tracer.Start(ctx, "websocket.publish_command", trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(attribute.String("ws.command", cmd.Type)))
+	defer func() {
+		if err != nil {
+			recordSpanError(span, err)
+		}
+		span.End()
+	}()
+	injectTrace(ctx, &cmd)
+
+	attempts := h.config.PublishMaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := h.callRedis(h.ctx, redisOpTimeout, func(ctx context.Context) error {
+			return h.redisService.PublishHubCommand(ctx, cmd)
+		})
+		if err == nil {
+			return nil
+		}
+		if errors.Is(err, errRedisCircuitOpen) {
+			return err
+		}
+		h.recordError("redis_publish")
+		if attempt >= attempts {
+			return err
+		}
+
+		delay := h.publishBackoff(attempt)
+		h.logger.Warn("Retrying hub command publish", "type", cmd.Type, "attempt", attempt, "delay", delay.String(), "error", err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-h.ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// publishBackoff returns the delay before retrying after the given failed attempt
+func (h *Hub) publishBackoff(attempt int) time.Duration {
+	delay := h.config.PublishBaseDelay << (attempt - 1)
+	if maxDelay := h.config.PublishMaxDelay; maxDelay > 0 && (delay > maxDelay || delay <= 0) {
+		delay = maxDelay
+	}
+	if jitter := h.config.PublishJitter; jitter > 0 {
+		delay += time.Duration((rand.Float64()*2 - 1) * jitter * float64(delay))
+	}
+	return delay
+}
+
+// disconnectLocal sends a force-logout frame to the user's client and closes it
+func (h *Hub) disconnectLocal(userID, reason string) bool {
+	h.mu.Lock()
+	client, exists := h.clients[userID]
+	if exists {
+		h.removeClient(client)
+	}
+	h.mu.Unlock()
+
+	if !exists {
+		return false
+	}
+
+	if sent, _ := client.trySend(h.messageToBytes(NewForceLogoutMessage(uuid.New().String(), userID, reason))); !sent {
+		h.logger.Warn("Failed to send force logout frame", "userID", userID)
+	}
+	// Closing send lets writePump flush the frame and close the connection
+	client.close()
+
+	h.logger.Info("Client forcibly disconnected", "userID", userID, "reason", reason)
+	return true
+}
+
+// listenCommands applies commands published by other hub instances
+func (h *Hub) listenCommands() {
+	pubsub := h.redisService.Subscribe(h.ctx, services.HubCommandsChannel)
+	defer pubsub.Close()
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-h.ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			var cmd hubCommand
+			if err := json.Unmarshal([]byte(msg.Payload), &cmd); err != nil {
+				h.logger.Warn("Ignoring malformed hub command", "error", err)
+				continue
+			}
+			h.applyCommand(cmd)
+		}
+	}
+}
+
+// applyCommand acts on a command published by another instance
+func (h *Hub) applyCommand(cmd hubCommand) {
+	// Already applied locally by the publisher
+	if cmd.Origin == h.instanceID {
+		return
+	}
+
+	_, span := h.tracer.Start(extractTrace(h.ctx, cmd), "websocket.apply_command", trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attribute.String("ws.command", cmd.Type)))
+	defer span.End()
+
+	switch cmd.Type {
+	case hubCommandDisconnect:
+		h.disconnectLocal(cmd.UserID, cmd.Reason)
+	case hubCommandDirectMessage:
+		delivered := h.sendToUser(cmd.UserID, cmd.Payload)
+		span.SetAttributes(attribute.Bool("ws.delivered", delivered))
+	case hubCommandChannelSettings:
+		h.dropChannelSettings(cmd.ChannelID)
+	default:
+		h.logger.Warn("Ignoring unknown hub command", "type", cmd.Type)
+	}
+}
@@ -0,0 +1,256 @@
+package websocket
+
+import (
+	"chat-service/internal/config"
+	"chat-service/internal/database"
+	"chat-service/internal/services"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestHub(t *testing.T) *Hub {
+	t.Helper()
+	hub := NewHub(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), config.WebSocketConfig{})
+	t.Cleanup(hub.cancel)
+	return hub
+}
+
+func connectTestClient(hub *Hub, userID string) *Client {
+	client := NewClient(hub, nil, userID)
+	hub.mu.Lock()
+	hub.clients[userID] = client
+	hub.mu.Unlock()
+	return client
+}
+
+// readForceLogout returns the reason of the force logout frame queued for the
+// client and checks that the send channel was closed after it
+func readForceLogout(t *testing.T, client *Client) string {
+	t.Helper()
+	data, ok := <-client.send
+	if !ok {
+		t.Fatal("send channel closed without a frame")
+	}
+	var msg Message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		t.Fatalf("decode frame: %v", err)
+	}
+	if msg.Type != MessageTypeForceLogout {
+		t.Fatalf("frame type = %q, want %q", msg.Type, MessageTypeForceLogout)
+	}
+	if _, ok := <-client.send; ok {
+		t.Fatal("send channel still open after the force logout frame")
+	}
+	reason, _ := msg.Data["reason"].(string)
+	return reason
+}
+
+func TestDisconnectLocal(t *testing.T) {
+	hub := newTestHub(t)
+	client := connectTestClient(hub, "42")
+
+	if !hub.disconnectLocal("42", "banned") {
+		t.Fatal("disconnectLocal reported no local connection")
+	}
+	if reason := readForceLogout(t, client); reason != "banned" {
+		t.Errorf("reason = %q, want %q", reason, "banned")
+	}
+	if _, ok := hub.clients["42"]; ok {
+		t.Error("client still registered after disconnect")
+	}
+
+	if hub.disconnectLocal("42", "banned") {
+		t.Error("second disconnect reported a local connection")
+	}
+}
+
+func TestApplyDisconnectCommand(t *testing.T) {
+	tests := []struct {
+		name       string
+		fromSelf   bool
+		wantClosed bool
+	}{
+		{"from another instance", false, true},
+		{"own command echoed back", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := newTestHub(t)
+			client := connectTestClient(hub, "7")
+
+			origin := "other-instance"
+			if tt.fromSelf {
+				origin = hub.instanceID
+			}
+			hub.applyCommand(hubCommand{Type: hubCommandDisconnect, UserID: "7", Reason: "kicked", Origin: origin})
+
+			_, stillConnected := hub.clients["7"]
+			if stillConnected == tt.wantClosed {
+				t.Fatalf("client connected = %v, want %v", stillConnected, !tt.wantClosed)
+			}
+			if tt.wantClosed {
+				if reason := readForceLogout(t, client); reason != "kicked" {
+					t.Errorf("reason = %q, want %q", reason, "kicked")
+				}
+			} else if len(client.send) != 0 {
+				t.Error("frame sent for the publisher's own command")
+			}
+		})
+	}
+}
+
+func TestPublishedDirectMessageNotDeliveredTwice(t *testing.T) {
+	hub := newTestHub(t)
+	redis, mr := newTestRedis(t)
+	hub.redisService = redis
+	recipient := connectTestClient(hub, "5")
+
+	go hub.listenCommands()
+	deadline := time.Now().Add(2 * time.Second)
+	for mr.PubSubNumSub(services.HubCommandsChannel)[services.HubCommandsChannel] == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("hub did not subscribe to commands")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// As in handleDirectMessage: delivered locally, then published for other
+	// instances. The hub consumes its own publish from Redis as well.
+	local := []byte(`{"id":"local"}`)
+	hub.sendToUser("5", local)
+	own := hubCommand{Type: hubCommandDirectMessage, UserID: "5", Payload: local, Origin: hub.instanceID}
+	if err := hub.publishCommand(context.Background(), own); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	// Published after the hub's own command, so once it arrives the first one
+	// has been consumed too
+	foreign := hubCommand{Type: hubCommandDirectMessage, UserID: "5", Payload: []byte(`{"id":"foreign"}`), Origin: "other-instance"}
+	if err := hub.publishCommand(context.Background(), foreign); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	var frames []string
+	for len(frames) < 2 {
+		select {
+		case data := <-recipient.send:
+			frames = append(frames, string(data))
+		case <-time.After(2 * time.Second):
+			t.Fatalf("got frames %v, want the local and the foreign message", frames)
+		}
+	}
+	if frames[0] != `{"id":"local"}` || frames[1] != `{"id":"foreign"}` {
+		t.Fatalf("frames = %v, want the local message once, then the foreign one", frames)
+	}
+	if len(recipient.send) != 0 {
+		t.Fatal("local message was delivered again from Redis")
+	}
+}
+
+// failingPublishes is a Redis hook that fails the first n PUBLISH commands and
+// counts every PUBLISH attempted
+type failingPublishes struct {
+	n        int64
+	attempts atomic.Int64
+}
+
+func (f *failingPublishes) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (f *failingPublishes) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func (f *failingPublishes) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if cmd.Name() != "publish" {
+			return next(ctx, cmd)
+		}
+		if f.attempts.Add(1) <= f.n {
+			err := errors.New("publish failed")
+			cmd.SetErr(err)
+			return err
+		}
+		return next(ctx, cmd)
+	}
+}
+
+// newPublishRetryHub returns a hub whose Redis fails the first n publishes and
+// which retries publishes up to maxAttempts times without noticeable delay
+func newPublishRetryHub(t *testing.T, n int64, maxAttempts int) (*Hub, *failingPublishes) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client, err := database.NewRedisConnection("redis://" + mr.Addr())
+	if err != nil {
+		t.Fatalf("connect to miniredis: %v", err)
+	}
+	t.Cleanup(func() { client.GetClient().Close() })
+	hook := &failingPublishes{n: n}
+	client.GetClient().AddHook(hook)
+
+	hub := newTestHub(t)
+	hub.redisService = services.NewRedisService(client)
+	hub.config.PublishMaxAttempts = maxAttempts
+	hub.config.PublishBaseDelay = time.Millisecond
+	hub.config.PublishMaxDelay = 5 * time.Millisecond
+	return hub, hook
+}
+
+func TestPublishCommandRetriesUntilPublished(t *testing.T) {
+	hub, hook := newPublishRetryHub(t, 2, 5)
+
+	cmd := hubCommand{Type: hubCommandDisconnect, UserID: "1", Origin: hub.instanceID}
+	if err := hub.publishCommand(context.Background(), cmd); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if got := hook.attempts.Load(); got != 3 {
+		t.Fatalf("publish attempted %d times, want 3", got)
+	}
+}
+
+func TestPublishCommandGivesUpAfterMaxAttempts(t *testing.T) {
+	hub, hook := newPublishRetryHub(t, 10, 3)
+
+	cmd := hubCommand{Type: hubCommandDisconnect, UserID: "1", Origin: hub.instanceID}
+	if err := hub.publishCommand(context.Background(), cmd); err == nil {
+		t.Fatal("publish succeeded while Redis was failing")
+	}
+	if got := hook.attempts.Load(); got != 3 {
+		t.Fatalf("publish attempted %d times, want 3", got)
+	}
+}
+
+func TestPublishCommandStopsRetryingOnShutdown(t *testing.T) {
+	hub, hook := newPublishRetryHub(t, 10, 5)
+	hub.config.PublishBaseDelay = time.Hour
+	hub.config.PublishMaxDelay = time.Hour
+
+	done := make(chan error, 1)
+	go func() {
+		done <- hub.publishCommand(context.Background(), hubCommand{Type: hubCommandDisconnect, UserID: "1"})
+	}()
+	for hook.attempts.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	hub.cancel()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("publish reported success after shutdown")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("publish kept waiting to retry after the hub stopped")
+	}
+	if got := hook.attempts.Load(); got != 1 {
+		t.Fatalf("publish attempted %d times, want 1", got)
+	}
+}
@@ -0,0 +1,75 @@
+package websocket
+
+import (
+	"compress/flate"
+	"net/http"
+	"strings"
+)
+
+// Every nth compressed frame is also deflated here to estimate the ratio, since
+// gorilla/websocket does not report compressed sizes
+const compressionSampleEvery = 100
+
+// wantsCompression reports whether the client offered permessage-deflate and the
+// hub compresses outbound frames. Clients that did not offer it are always sent
+// uncompressed frames.
+func (h *Hub) wantsCompression(r *http.Request) bool {
+	if h.config.CompressionThreshold <= 0 {
+		return false
+	}
+	for _, ext := range r.Header.Values("Sec-WebSocket-Extensions") {
+		if strings.Contains(ext, "permessage-deflate") {
+			return true
+		}
+	}
+	return false
+}
+
+// prepareWrite turns compression on for the next frame only when the payload is
+// large enough to be worth it
+func (c *Client) prepareWrite(payload []byte) {
+	if !c.compress {
+		return
+	}
+	compress := len(payload) >= c.hub.config.CompressionThreshold
+	c.conn.EnableWriteCompression(compress)
+	if compress {
+		c.hub.metrics.observeCompressed(payload)
+	}
+}
+
+// observeCompressed counts a compressed frame and samples its compression ratio
+func (m *hubMetrics) observeCompressed(payload []byte) {
+	n := m.compressedFrames.Add(1)
+	m.compressedBytes.Add(int64(len(payload)))
+	if n%compressionSampleEvery != 1 {
+		return
+	}
+
+	counter := &countingWriter{}
+	fw, err := flate.NewWriter(counter, flate.BestSpeed) // gorilla's default level
+	if err != nil {
+		return
+	}
+	if _, err := fw.Write(payload); err != nil {
+		return
+	}
+	if err := fw.Close(); err != nil {
+		return
+	}
+
+	m.mu.Lock()
+	m.sampledRawBytes += int64(len(payload))
+	m.sampledDeflatedBytes += counter.n
+	m.mu.Unlock()
+}
+
+// countingWriter discards its input and counts the bytes
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
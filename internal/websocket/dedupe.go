@@ -0,0 +1,110 @@
+package websocket
+
+import (
+	"chat-service/internal/models"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// How long accepted client_msg_ids are remembered in memory. This only has to
+// cover messages still waiting in the write-behind batch; older ones are found
+// in the database.
+const recentClientMsgTTL = 5 * time.Minute
+
+// Sweep expired entries once the cache grows past this size
+const recentClientMsgSweepSize = 4096
+
+type recentClientMsg struct {
+	chat     *models.Chat
+	storedAt time.Time
+	pending  bool // queued for the write-behind batch and not yet stored
+}
+
+// recentClientMsgs remembers recently accepted messages by sender and
+// client_msg_id so a resend is acknowledged instead of posted twice
+type recentClientMsgs struct {
+	mu      sync.Mutex
+	entries map[string]recentClientMsg // senderID:clientMsgID -> message
+}
+
+func newRecentClientMsgs() *recentClientMsgs {
+	return &recentClientMsgs{entries: make(map[string]recentClientMsg)}
+}
+
+func (r *recentClientMsgs) get(userID, clientMsgID string) *models.Chat {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.entries[userID+":"+clientMsgID]
+	if !ok || entry.pending || time.Since(entry.storedAt) > recentClientMsgTTL {
+		return nil
+	}
+	return entry.chat
+}
+
+// isPending reports whether a message with this client_msg_id is queued but not yet stored
+func (r *recentClientMsgs) isPending(userID, clientMsgID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.entries[userID+":"+clientMsgID]
+	return ok && entry.pending && time.Since(entry.storedAt) <= recentClientMsgTTL
+}
+
+func (r *recentClientMsgs) put(userID, clientMsgID string, chat *models.Chat) {
+	r.store(userID, clientMsgID, recentClientMsg{chat: chat})
+}
+
+// putPending remembers a message queued for the write-behind batch. A resend is
+// neither posted nor acked until the batch reports whether it was stored.
+func (r *recentClientMsgs) putPending(userID, clientMsgID string, chat *models.Chat) {
+	r.store(userID, clientMsgID, recentClientMsg{chat: chat, pending: true})
+}
+
+func (r *recentClientMsgs) remove(userID, clientMsgID string) {
+	r.mu.Lock()
+	delete(r.entries, userID+":"+clientMsgID)
+	r.mu.Unlock()
+}
+
+func (r *recentClientMsgs) store(userID, clientMsgID string, entry recentClientMsg) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if len(r.entries) > recentClientMsgSweepSize {
+		for key, entry := range r.entries {
+			if now.Sub(entry.storedAt) > recentClientMsgTTL {
+				delete(r.entries, key)
+			}
+		}
+	}
+	entry.storedAt = now
+	r.entries[userID+":"+clientMsgID] = entry
+}
+
+// findResentMessage returns the message the user already sent with this
+// client_msg_id, or nil if there is none. Lookup errors are logged and treated
+// as not found so a database hiccup does not block sending.
+func (h *Hub) findResentMessage(userID, clientMsgID string) *models.Chat {
+	if chat := h.recentClientMsgs.get(userID, clientMsgID); chat != nil {
+		return chat
+	}
+
+	senderID, err := strconv.ParseUint(userID, 10, 64)
+	if err != nil {
+		return nil
+	}
+	chat, err := h.chatRepo.FindByClientMsgID(uint(senderID), clientMsgID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			h.logger.Warn("Failed to look up client message ID", "userID", userID, "clientMsgID", clientMsgID, "error", err)
+		}
+		return nil
+	}
+	return chat
+}
@@ -0,0 +1,126 @@
+package websocket
+
+import (
+	"chat-service/internal/models"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRecentClientMsgs(t *testing.T) {
+	chat := &models.Chat{ChannelID: 3}
+
+	tests := []struct {
+		name        string
+		setup       func(r *recentClientMsgs)
+		wantChat    bool
+		wantPending bool
+	}{
+		{"unknown", func(r *recentClientMsgs) {}, false, false},
+		{"stored", func(r *recentClientMsgs) { r.put("1", "a", chat) }, true, false},
+		{"pending", func(r *recentClientMsgs) { r.putPending("1", "a", chat) }, false, true},
+		{"pending then stored", func(r *recentClientMsgs) {
+			r.putPending("1", "a", chat)
+			r.put("1", "a", chat)
+		}, true, false},
+		{"pending then removed", func(r *recentClientMsgs) {
+			r.putPending("1", "a", chat)
+			r.remove("1", "a")
+		}, false, false},
+		{"other sender", func(r *recentClientMsgs) { r.put("2", "a", chat) }, false, false},
+		{"other client_msg_id", func(r *recentClientMsgs) { r.put("1", "b", chat) }, false, false},
+		{"stored and expired", func(r *recentClientMsgs) {
+			r.put("1", "a", chat)
+			expire(r, "1:a")
+		}, false, false},
+		{"pending and expired", func(r *recentClientMsgs) {
+			r.putPending("1", "a", chat)
+			expire(r, "1:a")
+		}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRecentClientMsgs()
+			tt.setup(r)
+
+			if got := r.get("1", "a"); (got != nil) != tt.wantChat {
+				t.Errorf("get() = %v, want found %v", got, tt.wantChat)
+			}
+			if got := r.isPending("1", "a"); got != tt.wantPending {
+				t.Errorf("isPending() = %v, want %v", got, tt.wantPending)
+			}
+		})
+	}
+}
+
+func TestRecentClientMsgsSweep(t *testing.T) {
+	r := newRecentClientMsgs()
+	for i := 0; i <= recentClientMsgSweepSize; i++ {
+		r.put("1", strconv.Itoa(i), &models.Chat{})
+	}
+	for key := range r.entries {
+		expire(r, key)
+	}
+
+	// The next store sweeps the expired entries
+	r.put("1", "fresh", &models.Chat{})
+	if len(r.entries) != 1 {
+		t.Errorf("%d entries after sweep, want 1", len(r.entries))
+	}
+}
+
+func expire(r *recentClientMsgs, key string) {
+	r.mu.Lock()
+	entry := r.entries[key]
+	entry.storedAt = time.Now().Add(-recentClientMsgTTL - time.Second)
+	r.entries[key] = entry
+	r.mu.Unlock()
+}
+
+func TestCompleteQueuedChat(t *testing.T) {
+	clientMsgID := "c1"
+	messageUUID := "6f1c3c3e-8a59-4d57-9d3e-2f0b6a6d8c11"
+
+	tests := []struct {
+		name       string
+		result     batchResult
+		wantType   MessageType
+		wantStored bool
+		wantRefund bool
+	}{
+		{"inserted is acked", batchInserted, MessageTypeMessageAck, true, false},
+		{"failed is nacked and refunded", batchFailed, MessageTypeMessageNack, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := newTestHub(t)
+			client := connectTestClient(hub, "1")
+			chat := &models.Chat{ChannelID: 3, SenderID: 1, UUID: &messageUUID, ClientMsgID: &clientMsgID}
+			hub.recentClientMsgs.putPending("1", clientMsgID, chat)
+
+			refunded := false
+			item := &queuedChat{chat: chat, client: client, messageID: "m1", refundQuota: func() { refunded = true }}
+			hub.completeQueuedChat(item, tt.result)
+
+			var reply Message
+			if err := json.Unmarshal(<-client.send, &reply); err != nil {
+				t.Fatalf("decode reply: %v", err)
+			}
+			if reply.Type != tt.wantType {
+				t.Errorf("reply type = %s, want %s", reply.Type, tt.wantType)
+			}
+			if got := hub.recentClientMsgs.get("1", clientMsgID) != nil; got != tt.wantStored {
+				t.Errorf("remembered = %v, want %v", got, tt.wantStored)
+			}
+			if hub.recentClientMsgs.isPending("1", clientMsgID) {
+				t.Error("still pending after the batch completed")
+			}
+			if refunded != tt.wantRefund {
+				t.Errorf("refunded = %v, want %v", refunded, tt.wantRefund)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package websocket
+
+import (
+	"chat-service/internal/models"
+	"chat-service/internal/services"
+	"errors"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// handleDirectMessage saves a 1:1 message addressed with ReceiverID and delivers
+// it to the recipient's connections without touching channel membership. The
+// sender gets the saved message echoed back as confirmation.
+func (h *Hub) handleDirectMessage(client *Client, message *Message) {
+	ctx, span := h.tracer.Start(h.ctx, "websocket.direct_message", trace.WithAttributes(attribute.String("ws.user_id", client.userID)))
+	defer span.End()
+
+	var data DirectMessageData
+	if err := h.mapToStruct(message.Data, &data); err != nil {
+		h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "INVALID_DATA", "Invalid direct message data"))
+		return
+	}
+	if err := data.Validate(); err != nil {
+		h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "INVALID_DATA", err.Error()))
+		return
+	}
+	if data.ReceiverID == client.userID {
+		h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "INVALID_DATA", "Cannot send a direct message to yourself"))
+		return
+	}
+	if data.Text != nil {
+		if err := h.chatService.ValidateText(*data.Text); err != nil {
+			h.metrics.countError("message_too_large")
+			h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "MESSAGE_TOO_LARGE", err.Error()))
+			return
+		}
+	}
+
+	if retryAfter, allowed := h.checkClientRate(client); !allowed {
+		h.sendToClient(client, NewRateLimitErrorMessage(message.ID, client.userID, retryAfter))
+		return
+	}
+	if !h.checkUserMessageRate(client.userID) {
+		h.sendToClient(client, NewRateLimitErrorMessage(message.ID, client.userID, h.config.RateLimitWindow))
+		return
+	}
+
+	senderIDUint, err := strconv.ParseUint(client.userID, 10, 64)
+	if err != nil {
+		h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "INVALID_USER_ID", "Invalid user ID format"))
+		return
+	}
+	receiverID, _ := parseUserID(data.ReceiverID)
+
+	if err := h.chatService.CheckReceiver(receiverID); err != nil {
+		h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "USER_NOT_FOUND", err.Error()))
+		return
+	}
+
+	chat := &models.Chat{
+		SenderID:   uint(senderIDUint),
+		ReceiverID: &receiverID,
+		Text:       data.Text,
+		URL:        data.URL,
+		FileName:   data.FileName,
+	}
+
+	if err := h.chatService.CheckBlocked(h.ctx, chat); err != nil {
+		if errors.Is(err, services.ErrSenderBlocked) {
+			h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "BLOCKED", err.Error()))
+			return
+		}
+		h.recordError("persist")
+		h.logger.Error("Failed to check blocked users", "error", err, "userID", client.userID, "receiverID", data.ReceiverID)
+		h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "SAVE_FAILED", "Failed to save message"))
+		return
+	}
+
+	usage, refundQuota, allowed := h.consumeQuota(client, chat.SenderID)
+	if !allowed {
+		h.sendToClient(client, NewQuotaExceededMessage(message.ID, client.userID, usage))
+		return
+	}
+
+	if err := h.chatRepo.Create(chat); err != nil {
+		refundQuota()
+		h.recordError("persist")
+		h.logger.Error("Failed to save direct message", "error", err, "userID", client.userID, "receiverID", data.ReceiverID)
+		h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "SAVE_FAILED", "Failed to save message"))
+		return
+	}
+
+	// Preload sender data
+	if loaded, err := h.chatRepo.FindByID(chat.ID); err != nil {
+		h.logger.Error("Failed to load chat data", "error", err, "chatID", chat.ID)
+		// Continue anyway, we can still deliver the message
+	} else {
+		chat = loaded
+	}
+
+	frame := h.messageToBytes(NewDirectMessage(message.ID, client.userID, chat))
+	h.sendToUser(data.ReceiverID, frame)
+
+	// The recipient may be connected to another instance. Publishing retries
+	// with backoff, so keep it off the read goroutine.
+	cmd := hubCommand{Type: hubCommandDirectMessage, UserID: data.ReceiverID, Payload: frame, Origin: h.instanceID}
+	go func() {
+		if err := h.publishCommand(ctx, cmd); err != nil {
+			h.logger.Warn("Failed to publish direct message to other instances", "receiverID", cmd.UserID, "error", err)
+		}
+	}()
+
+	h.sendBytes(client, frame)
+}
+
+// sendToUser delivers an encoded frame to the user's connection on this
+// instance, if there is one
+func (h *Hub) sendToUser(userID string, data []byte) bool {
+	h.mu.RLock()
+	client, exists := h.clients[userID]
+	h.mu.RUnlock()
+	if !exists {
+		return false
+	}
+	return h.sendBytes(client, data)
+}
@@ -0,0 +1,113 @@
+package websocket
+
+import (
+	"sync"
+	"time"
+)
+
+// Hub errors kept for the admin error history
+const errorHistorySize = 500
+
+// Window over which per-type error rates are reported
+const errorRateWindow = time.Minute
+
+// Error severities
+const (
+	SeverityWarning = "warning" // degraded but recovered, e.g. a Redis fallback
+	SeverityError   = "error"   // work was lost or a client was affected
+)
+
+// errorSeverities maps hub error sources to a severity; unlisted sources are warnings
+var errorSeverities = map[string]string{
+	"persist":     SeverityError,
+	"write":       SeverityError,
+	"write_stall": SeverityError,
+}
+
+// HubErrorEntry is one recorded hub error
+type HubErrorEntry struct {
+	Type      string    `json:"type"`
+	Severity  string    `json:"severity"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ErrorHistory is the admin view of recent hub errors
+type ErrorHistory struct {
+	Errors []HubErrorEntry    `json:"errors"` // newest first, after filtering
+	Totals map[string]uint64  `json:"totals"` // all errors counted since start, by type
+	Rates  map[string]float64 `json:"rates"`  // errors per minute over the last minute, by type
+}
+
+// errorHistory is a fixed-size ring of the latest hub errors
+type errorHistory struct {
+	mu      sync.Mutex
+	entries []HubErrorEntry
+	next    int // slot the next entry is written to once the ring is full
+}
+
+func newErrorHistory() *errorHistory {
+	return &errorHistory{entries: make([]HubErrorEntry, 0, errorHistorySize)}
+}
+
+func (e *errorHistory) add(source string) {
+	severity, ok := errorSeverities[source]
+	if !ok {
+		severity = SeverityWarning
+	}
+	entry := HubErrorEntry{Type: source, Severity: severity, Timestamp: time.Now()}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.entries) < errorHistorySize {
+		e.entries = append(e.entries, entry)
+		return
+	}
+	e.entries[e.next] = entry
+	e.next = (e.next + 1) % errorHistorySize
+}
+
+// newestFirst copies the ring out in reverse chronological order
+func (e *errorHistory) newestFirst() []HubErrorEntry {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	out := make([]HubErrorEntry, 0, len(e.entries))
+	for i := 0; i < len(e.entries); i++ {
+		// The newest entry sits just before next, wrapping around
+		idx := (e.next - 1 - i + 2*len(e.entries)) % len(e.entries)
+		out = append(out, e.entries[idx])
+	}
+	return out
+}
+
+// ErrorHistory returns up to limit recent hub errors, optionally filtered by type
+// and severity, with per-type totals and current rates
+func (h *Hub) ErrorHistory(errorType, severity string, limit int) *ErrorHistory {
+	entries := h.errorHistory.newestFirst()
+
+	history := &ErrorHistory{
+		Errors: []HubErrorEntry{},
+		Totals: make(map[string]uint64),
+		Rates:  make(map[string]float64),
+	}
+	cutoff := time.Now().Add(-errorRateWindow)
+	for _, entry := range entries {
+		if entry.Timestamp.After(cutoff) {
+			history.Rates[entry.Type] += 1 / errorRateWindow.Minutes()
+		}
+		if (errorType != "" && entry.Type != errorType) || (severity != "" && entry.Severity != severity) {
+			continue
+		}
+		if limit > 0 && len(history.Errors) >= limit {
+			continue
+		}
+		history.Errors = append(history.Errors, entry)
+	}
+
+	h.metrics.mu.Lock()
+	for errType, count := range h.metrics.errors {
+		history.Totals[errType] = count
+	}
+	h.metrics.mu.Unlock()
+	return history
+}
@@ -0,0 +1,105 @@
+package websocket
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// globalPresenceView mirrors the cluster-wide online set from Redis. It only
+// answers presence queries; it never holds connections.
+type globalPresenceView struct {
+	mu          sync.RWMutex
+	users       map[string]struct{}
+	refreshedAt time.Time
+}
+
+func newGlobalPresenceView() *globalPresenceView {
+	return &globalPresenceView{users: make(map[string]struct{})}
+}
+
+// replace swaps in a freshly loaded online set
+func (v *globalPresenceView) replace(userIDs []string) {
+	users := make(map[string]struct{}, len(userIDs))
+	for _, id := range userIDs {
+		users[id] = struct{}{}
+	}
+
+	v.mu.Lock()
+	v.users = users
+	v.refreshedAt = time.Now()
+	v.mu.Unlock()
+}
+
+// set applies a presence change made by this instance between refreshes
+func (v *globalPresenceView) set(userID string, online bool) {
+	v.mu.Lock()
+	if online {
+		v.users[userID] = struct{}{}
+	} else {
+		delete(v.users, userID)
+	}
+	v.mu.Unlock()
+}
+
+// lookup reports whether the user is online, and false for ok until the first load
+func (v *globalPresenceView) lookup(userID string) (online bool, ok bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if v.refreshedAt.IsZero() {
+		return false, false
+	}
+	_, online = v.users[userID]
+	return online, true
+}
+
+// runPresenceRefresh loads global presence immediately and then periodically
+func (h *Hub) runPresenceRefresh() {
+	interval := h.config.PresenceRefreshInterval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	h.refreshGlobalPresence()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-h.ctx.Done():
+			return
+		case <-ticker.C:
+			h.refreshGlobalPresence()
+		}
+	}
+}
+
+func (h *Hub) refreshGlobalPresence() {
+	ctx, cancel := context.WithTimeout(h.ctx, redisOpTimeout)
+	defer cancel()
+
+	userIDs, err := h.redisService.GetOnlineUsers(ctx)
+	if err != nil {
+		h.logger.Warn("Failed to refresh global presence", "error", err)
+		return
+	}
+	h.globalPresence.replace(userIDs)
+	h.logger.Debug("Refreshed global presence", "onlineUsers", len(userIDs))
+}
+
+// isOnlineGlobally answers from the warmed-up view when available and falls
+// back to asking Redis directly
+func (h *Hub) isOnlineGlobally(ctx context.Context, userID string) bool {
+	if h.globalPresence != nil {
+		if online, ok := h.globalPresence.lookup(userID); ok {
+			return online
+		}
+	}
+
+	online, err := h.redisService.IsUserOnline(ctx, userID)
+	if err != nil {
+		h.logger.Warn("Failed to read global presence", "userID", userID, "error", err)
+	}
+	return online
+}
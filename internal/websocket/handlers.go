@@ -1,50 +1,94 @@
 package websocket
 
 import (
+	"log/slog"
 	"net/http"
-	"os"
+	"net/url"
 	"strings"
 
+	"chat-service/internal/config"
+
 	"github.com/gorilla/websocket"
 )
 
-var Upgrader = websocket.Upgrader{
-	ReadBufferSize:  1024,
-	WriteBufferSize: 1024,
-	// Allow specific origins for WebSocket connections
-	CheckOrigin: func(r *http.Request) bool {
-		origin := r.Header.Get("Origin")
-
-		// Define allowed origins
-		allowedOrigins := []string{
-			"http://localhost:3000",           // Frontend dev server
-			"https://localhost:3000",          // Frontend dev server (HTTPS)
-			"http://localhost",                // Nginx proxy (Docker)
-			"https://localhost",               // Nginx proxy (HTTPS)
-			"http://127.0.0.1:3000",           // Alternative localhost
-			"http://127.0.0.1",                // Alternative localhost (Nginx)
-			"https://notify-chat.netlify.app", // Production deployment
-		}
-
-		// Add custom origins from environment variable if set
-		if customOrigins := os.Getenv("ALLOWED_ORIGINS"); customOrigins != "" {
-			for _, customOrigin := range strings.Split(customOrigins, ",") {
-				allowedOrigins = append(allowedOrigins, strings.TrimSpace(customOrigin))
-			}
-		}
-
-		// Check if origin is in allowed list
-		for _, allowedOrigin := range allowedOrigins {
-			if origin == allowedOrigin {
+// newUpgrader builds the WebSocket upgrader with the configured origin policy.
+// Requests from other origins are refused with 403 before the upgrade.
+func newUpgrader(cfg config.WebSocketConfig, metrics *hubMetrics, logger *slog.Logger) websocket.Upgrader {
+	policy := newOriginPolicy(cfg, logger)
+	return websocket.Upgrader{
+		ReadBufferSize:    1024,
+		WriteBufferSize:   1024,
+		EnableCompression: cfg.CompressionThreshold > 0,
+		CheckOrigin: func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			if policy.allows(origin) {
 				return true
 			}
-		}
+			metrics.countError("origin_rejected")
+			logger.Warn("Rejected WebSocket origin", "origin", origin, "remoteAddr", r.RemoteAddr)
+			return false
+		},
+	}
+}
 
-		// For development/testing, allow any localhost variations
-		if origin != "" && (strings.Contains(origin, "localhost") || strings.Contains(origin, "127.0.0.1")) {
-			return true
+// originPolicy decides which browser origins may open WebSocket connections
+type originPolicy struct {
+	allowAll       bool
+	allowLocalhost bool
+	exact          map[string]struct{}
+	wildcards      []wildcardOrigin
+}
+
+// wildcardOrigin matches any subdomain of suffix under the given scheme
+type wildcardOrigin struct {
+	scheme string
+	suffix string // ".example.com"
+}
+
+func newOriginPolicy(cfg config.WebSocketConfig, logger *slog.Logger) *originPolicy {
+	p := &originPolicy{
+		allowAll:       cfg.AllowAllOrigins,
+		allowLocalhost: cfg.AllowLocalhostOrigins,
+		exact:          make(map[string]struct{}, len(cfg.AllowedOrigins)),
+	}
+	for _, origin := range cfg.AllowedOrigins {
+		origin = strings.ToLower(strings.TrimSuffix(origin, "/"))
+		if scheme, host, ok := strings.Cut(origin, "://*."); ok {
+			p.wildcards = append(p.wildcards, wildcardOrigin{scheme: scheme, suffix: "." + host})
+			continue
 		}
+		p.exact[origin] = struct{}{}
+	}
+	if p.allowAll {
+		logger.Warn("WebSocket origin check disabled, accepting connections from any origin")
+	}
+	return p
+}
 
+// allows reports whether a connection from origin may be upgraded. Requests
+// without an Origin header are refused unless every origin is allowed.
+func (p *originPolicy) allows(origin string) bool {
+	if p.allowAll {
+		return true
+	}
+	if origin == "" {
 		return false
-	},
+	}
+	origin = strings.ToLower(origin)
+	if _, ok := p.exact[origin]; ok {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	host := u.Hostname()
+	for _, w := range p.wildcards {
+		// The suffix includes its leading dot, so the bare domain does not match
+		if u.Scheme == w.scheme && strings.HasSuffix(u.Host, w.suffix) {
+			return true
+		}
+	}
+	return p.allowLocalhost && (host == "localhost" || host == "127.0.0.1")
 }
@@ -0,0 +1,95 @@
+package websocket
+
+import (
+	"chat-service/internal/config"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestOriginPolicy(t *testing.T) {
+	cfg := config.WebSocketConfig{AllowedOrigins: []string{"https://app.example.com/", "https://*.chat.example.com"}}
+	localhost := cfg
+	localhost.AllowLocalhostOrigins = true
+	allowAll := config.WebSocketConfig{AllowAllOrigins: true}
+
+	tests := []struct {
+		name   string
+		cfg    config.WebSocketConfig
+		origin string
+		want   bool
+	}{
+		{"exact match", cfg, "https://app.example.com", true},
+		{"exact match ignores case", cfg, "HTTPS://App.Example.com", true},
+		{"other origin", cfg, "https://evil.example.com", false},
+		{"exact match wrong scheme", cfg, "http://app.example.com", false},
+		{"no origin", cfg, "", false},
+		{"wildcard subdomain", cfg, "https://eu.chat.example.com", true},
+		{"wildcard nested subdomain", cfg, "https://a.eu.chat.example.com", true},
+		{"wildcard bare domain", cfg, "https://chat.example.com", false},
+		{"wildcard wrong scheme", cfg, "http://eu.chat.example.com", false},
+		{"wildcard suffix lookalike", cfg, "https://evilchat.example.com", false},
+		{"localhost not allowed by default", cfg, "http://localhost:3000", false},
+		{"localhost allowed", localhost, "http://localhost:3000", true},
+		{"loopback allowed", localhost, "http://127.0.0.1", true},
+		{"allow all", allowAll, "https://anything.test", true},
+		{"allow all without origin", allowAll, "", true},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newOriginPolicy(tt.cfg, logger).allows(tt.origin); got != tt.want {
+				t.Fatalf("allows(%q) = %v, want %v", tt.origin, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpgraderRejectsDisallowedOrigin(t *testing.T) {
+	cfg := config.WebSocketConfig{AllowedOrigins: []string{"https://*.example.com"}}
+	hub := NewHub(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), cfg)
+	t.Cleanup(hub.cancel)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := hub.upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		conn.Close()
+	}))
+	t.Cleanup(server.Close)
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	tests := []struct {
+		origin     string
+		wantStatus int
+	}{
+		{"https://app.example.com", http.StatusSwitchingProtocols},
+		{"https://evil.test", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.origin, func(t *testing.T) {
+			conn, resp, err := websocket.DefaultDialer.Dial(url, http.Header{"Origin": {tt.origin}})
+			if conn != nil {
+				conn.Close()
+			}
+			if resp == nil {
+				t.Fatalf("dial: %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+
+	hub.metrics.mu.Lock()
+	rejected := hub.metrics.errors["origin_rejected"]
+	hub.metrics.mu.Unlock()
+	if rejected != 1 {
+		t.Fatalf("origin_rejected count = %d, want 1", rejected)
+	}
+}
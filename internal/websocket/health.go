@@ -0,0 +1,121 @@
+package websocket
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// HealthStatus is the hub's self-assessed health
+type HealthStatus string
+
+const (
+	HealthHealthy   HealthStatus = "healthy"
+	HealthUnhealthy HealthStatus = "unhealthy"
+)
+
+// HealthMonitor tracks the hub's recent error rate. Once errors within the window
+// reach the threshold the hub is unhealthy and sheds new connections; it recovers
+// when the rate falls below half the threshold, so it does not flap at the edge.
+type HealthMonitor struct {
+	mu        sync.Mutex
+	window    time.Duration
+	threshold int
+	errors    []time.Time
+	status    HealthStatus
+	logger    *slog.Logger
+}
+
+// NewHealthMonitor creates a monitor. A threshold of 0 disables it and the hub always reports healthy.
+func NewHealthMonitor(threshold int, window time.Duration, logger *slog.Logger) *HealthMonitor {
+	return &HealthMonitor{window: window, threshold: threshold, status: HealthHealthy, logger: logger}
+}
+
+// RecordError counts an error from the given source
+func (m *HealthMonitor) RecordError(source string) {
+	if m.threshold <= 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.errors = append(m.errors, time.Now())
+	m.update(source)
+}
+
+// Status returns the current health status
+func (m *HealthMonitor) Status() HealthStatus {
+	if m.threshold <= 0 {
+		return HealthHealthy
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.update("")
+	return m.status
+}
+
+// RecentErrors returns how many errors fell within the window
+func (m *HealthMonitor) RecentErrors() int {
+	if m.threshold <= 0 {
+		return 0
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.update("")
+	return len(m.errors)
+}
+
+// update drops errors outside the window and re-evaluates the status. Caller must hold m.mu.
+func (m *HealthMonitor) update(source string) {
+	cutoff := time.Now().Add(-m.window)
+	i := 0
+	for i < len(m.errors) && !m.errors[i].After(cutoff) {
+		i++
+	}
+	m.errors = m.errors[i:]
+
+	count := len(m.errors)
+	switch {
+	case m.status == HealthHealthy && count >= m.threshold:
+		m.status = HealthUnhealthy
+		m.logger.Warn("Hub unhealthy, shedding new connections", "errors", count, "window", m.window.String(), "lastSource", source)
+	case m.status == HealthUnhealthy && count < m.threshold/2:
+		m.status = HealthHealthy
+		m.logger.Info("Hub recovered, accepting new connections", "errors", count)
+	}
+}
+
+// Health returns the hub's current health status
+func (h *Hub) Health() HealthStatus {
+	return h.health.Status()
+}
+
+// AcceptingConnections reports whether new WebSocket connections should be accepted
+func (h *Hub) AcceptingConnections() bool {
+	if h.ctx.Err() != nil {
+		// Shutting down
+		return false
+	}
+	return h.health.Status() != HealthUnhealthy
+}
+
+// ActiveConnections returns the number of clients connected to this instance
+func (h *Hub) ActiveConnections() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients)
+}
+
+// RecentErrors returns the hub errors counted toward load shedding in the current window
+func (h *Hub) RecentErrors() int {
+	return h.health.RecentErrors()
+}
+
+// RunLoopAlive reports whether the run loop has turned recently. The loop wakes
+// at least every presenceCheckInterval, so a longer gap means it is stuck.
+func (h *Hub) RunLoopAlive() bool {
+	if h.ctx.Err() != nil {
+		return false
+	}
+	beat := h.loopBeat.Load()
+	return beat != 0 && time.Since(time.Unix(0, beat)) < 3*presenceCheckInterval
+}
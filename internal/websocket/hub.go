@@ -1,23 +1,37 @@
 package websocket
 
 import (
+	"chat-service/internal/config"
 	"chat-service/internal/models"
 	"chat-service/internal/repositories/postgres"
 	"chat-service/internal/services"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// Upper bound for a single Redis call made from the hub loop
+const redisOpTimeout = 2 * time.Second
+
+// How long Stop waits for clients to receive their close frames
+const shutdownWriteTimeout = 5 * time.Second
+
 var (
 	ErrClientDisconnected = fmt.Errorf("client disconnected")
 	ErrChannelNotFound    = fmt.Errorf("channel not found")
 	ErrClientNotFound     = fmt.Errorf("client not found")
+	ErrTooManyChannels    = fmt.Errorf("too many channels joined on this connection")
 )
 
 type ClientMessage struct {
@@ -32,105 +46,389 @@ type Hub struct {
 	// Chat repository for message storage
 	chatRepo *postgres.ChatRepository
 
+	// Channel repository for per-channel settings
+	channelRepo      *postgres.ChannelRepository
+	settings         *channelSettingsCache
+	localLimits      *localRateLimiter // fallback when Redis rate limiting is unavailable
+	recentClientMsgs *recentClientMsgs // recently accepted client_msg_ids, covering the write-behind window
+
+	// Last seen times of users, optional
+	presenceRepo *postgres.PresenceRepository
+
+	// Error rate tracking for load shedding
+	health       *HealthMonitor
+	errorHistory *errorHistory
+
+	// Skips Redis calls while Redis keeps failing
+	redisBreaker *circuitBreaker
+
+	// Count of client writes slower than slowWriteThreshold
+	slowWrites atomic.Int64
+
+	// Unix nanoseconds of the run loop's last iteration, for readiness checks
+	loopBeat atomic.Int64
+
+	// Counters for the Prometheus endpoint
+	metrics *hubMetrics
+
+	// Spans around message persistence, broadcasts and cross-instance commands
+	tracer trace.Tracer
+
+	// Chat service for message actions shared with the REST API
+	chatService *services.ChatService
+
+	// Read pointer service for read receipts
+	readService *services.ReadStateService
+
+	// Redis service for cluster-wide presence
+	redisService *services.RedisService
+
+	// Optional write-behind persistence for channel messages
+	batcher *messageBatcher
+
+	// Optional webhook notifications for offline recipients
+	notifier *services.OfflineNotifier
+
+	// Integration webhooks for new channel messages, optional
+	webhooks *services.ChannelWebhookNotifier
+
+	// Connect/disconnect webhooks for presence integrations, optional
+	presenceNotifier *services.PresenceNotifier
+
+	// Monitoring webhook for hub errors, optional
+	errorNotifier *services.ErrorNotifier
+
+	// Daily per-user message quota, optional
+	quota *services.MessageQuota
+
+	// Resolves @username mentions in channel messages, optional
+	mentions *services.MentionService
+
+	config config.WebSocketConfig
+
+	// All hub logging goes through this logger, filtered to the configured level
+	logger *slog.Logger
+
+	// Upgrades HTTP requests, enforcing the allowed origins
+	upgrader websocket.Upgrader
+
+	// Read-only mirror of cluster-wide presence, nil unless warm-up is enabled
+	globalPresence *globalPresenceView
+
+	// Clients subscribed to presence changes of specific users
+	presenceSubs *presenceSubscriptions
+
+	// Identifies this instance on the cross-instance command bus
+	instanceID string
+
 	// Message broadcasting
 	register   chan *Client
 	unregister chan *Client
-	broadcast  chan []byte
+	broadcast  chan *ClientMessage
 
 	// Context for graceful shutdown
 	ctx    context.Context
 	cancel context.CancelFunc
 
+	// Running writePump goroutines, waited on by Stop
+	writers sync.WaitGroup
+
 	// Mutex for thread safety
 	mu sync.RWMutex
 }
 
-func NewHub(redisService *services.RedisService, chatRepo *postgres.ChatRepository) *Hub {
+func NewHub(redisService *services.RedisService, chatRepo *postgres.ChatRepository, channelRepo *postgres.ChannelRepository, presenceRepo *postgres.PresenceRepository, chatService *services.ChatService, readService *services.ReadStateService, notifier *services.OfflineNotifier, webhooks *services.ChannelWebhookNotifier, presenceNotifier *services.PresenceNotifier, errorNotifier *services.ErrorNotifier, quota *services.MessageQuota, mentions *services.MentionService, logger *slog.Logger, cfg config.WebSocketConfig) *Hub {
 	ctx, cancel := context.WithCancel(context.Background())
+	if logger == nil {
+		logger = newHubLogger(cfg.LogLevel)
+	}
 
 	hub := &Hub{
-		channels:   make(map[string]map[string]*Client),
-		clients:    make(map[string]*Client),
-		register:   make(chan *Client),
-		unregister: make(chan *Client),
-		broadcast:  make(chan []byte),
-		chatRepo:   chatRepo,
-		ctx:        ctx,
-		cancel:     cancel,
+		channels:         make(map[string]map[string]*Client),
+		clients:          make(map[string]*Client),
+		register:         make(chan *Client),
+		unregister:       make(chan *Client),
+		broadcast:        make(chan *ClientMessage),
+		chatRepo:         chatRepo,
+		channelRepo:      channelRepo,
+		presenceRepo:     presenceRepo,
+		chatService:      chatService,
+		readService:      readService,
+		settings:         newChannelSettingsCache(),
+		presenceSubs:     newPresenceSubscriptions(),
+		localLimits:      newLocalRateLimiter(),
+		recentClientMsgs: newRecentClientMsgs(),
+		health:           NewHealthMonitor(cfg.ShedErrorThreshold, cfg.ShedErrorWindow, logger),
+		errorHistory:     newErrorHistory(),
+		redisBreaker:     newCircuitBreaker(cfg.RedisBreakerThreshold, cfg.RedisBreakerCooldown, logger),
+		metrics:          newHubMetrics(),
+		tracer:           newTracer(),
+		redisService:     redisService,
+		notifier:         notifier,
+		webhooks:         webhooks,
+		presenceNotifier: presenceNotifier,
+		errorNotifier:    errorNotifier,
+		quota:            quota,
+		mentions:         mentions,
+		config:           cfg,
+		logger:           logger,
+		instanceID:       uuid.New().String(),
+		ctx:              ctx,
+		cancel:           cancel,
+	}
+	hub.upgrader = newUpgrader(cfg, hub.metrics, logger)
+
+	if cfg.PresenceWarmup {
+		hub.globalPresence = newGlobalPresenceView()
+	}
+
+	if cfg.BatchPersistEnabled {
+		hub.batcher = newMessageBatcher(chatRepo, cfg.BatchSize, cfg.BatchFlushInterval, cfg.BatchMaxRetries, hub.completeQueuedChat, logger)
 	}
 
 	return hub
 }
 
 func (h *Hub) Run() {
-	for {
-		select {
-		case c := <-h.register:
-			h.mu.Lock()
-			// Check if client already exists and clean up if necessary
-			if existingClient, exists := h.clients[c.userID]; exists {
-				slog.Warn("Client already exists, cleaning up old connection", "userID", c.userID)
-				// Clean up existing client
-				existingClient.cancel()
-				close(existingClient.send)
-			}
+	if h.batcher != nil {
+		go h.batcher.run(h.ctx)
+	}
+	go h.listenCommands()
+	go h.listenPresenceUpdates()
+	if h.globalPresence != nil {
+		go h.runPresenceRefresh()
+	}
+	if h.config.InactivityTimeout > 0 {
+		go h.runInactivityReaper()
+	}
+	if h.config.QualityCheckInterval > 0 {
+		go h.runQualityMonitor()
+	}
+	if h.config.WriteStallTimeout > 0 {
+		go h.runWriteStallReaper()
+	}
+	if h.config.LastSeenInterval > 0 {
+		go h.runLastSeenRefresh()
+	}
 
-			// Register new client
-			h.clients[c.userID] = c
+	go h.runPresenceHeartbeat()
 
-			// Send connection confirmation
-			connectMsg := NewConnectMessage(uuid.New().String(), c.conn.RemoteAddr().String(), c.userID)
-			c.send <- h.messageToBytes(connectMsg)
-			h.mu.Unlock()
+	presenceTicker := time.NewTicker(presenceCheckInterval)
+	defer presenceTicker.Stop()
 
-			slog.Info("Client registered successfully", "userID", c.userID, "remoteAddr", c.conn.RemoteAddr().String())
+	for {
+		h.loopBeat.Store(time.Now().UnixNano())
+		select {
+		case c := <-h.register:
+			h.registerClient(c)
 
 		case c := <-h.unregister:
 			h.mu.Lock()
 			// Check if this is the current client (not an old one)
+			removed := false
 			if currentClient, exists := h.clients[c.userID]; exists && currentClient == c {
-				// Remove client from all channels
-				for channelID, clients := range h.channels {
-					if _, exists := clients[c.userID]; exists {
-						delete(clients, c.userID)
-						// Notify other clients in the channel
-						h.notifyChannelMembers(channelID, c.userID, "left")
-
-						// Clean up empty channels
-						if len(clients) == 0 {
-							delete(h.channels, channelID)
-						}
-					}
-				}
-				delete(h.clients, c.userID)
-				slog.Info("Client unregistered", "userID", c.userID)
+				h.removeClient(c)
+				c.close()
+				removed = true
+				h.logger.Info("Client unregistered", "userID", c.userID)
 			} else {
-				slog.Debug("Ignoring unregister for old client", "userID", c.userID)
+				h.logger.Debug("Ignoring unregister for old client", "userID", c.userID)
 			}
 			h.mu.Unlock()
 
-		case messageBytes := <-h.broadcast:
-			h.handleClientMessage(messageBytes)
+			if removed {
+				h.setPresence(c.userID, false)
+			}
+
+		case clientMessage := <-h.broadcast:
+			h.handleClientMessage(clientMessage)
+
+		case <-presenceTicker.C:
+			h.checkPresence()
 
 		case <-h.ctx.Done():
-			slog.Info("WebSocket hub shutting down...")
+			h.logger.Info("WebSocket hub shutting down...")
 			return
 		}
 	}
 }
 
+// Stop shuts the hub down: presence is released, every client is sent a
+// going-away close frame, and the run loop and background routines exit. It waits
+// up to shutdownWriteTimeout for writers to flush and for batched messages to persist.
 func (h *Hub) Stop() {
+	// Hand off presence first so other instances see local users leave immediately
+	h.releasePresence()
+	h.saveAllResumeStates()
+
 	h.cancel()
+	h.closeClients(websocket.CloseGoingAway, "server shutting down")
+
+	writersDone := make(chan struct{})
+	go func() {
+		h.writers.Wait()
+		close(writersDone)
+	}()
+	select {
+	case <-writersDone:
+	case <-time.After(shutdownWriteTimeout):
+		h.logger.Warn("Timed out waiting for WebSocket writers to finish")
+	}
+
+	// Wait for buffered messages to be persisted
+	if h.batcher != nil {
+		<-h.batcher.done
+	}
 }
 
-func (h *Hub) JoinChannel(userID string, channelID string) error {
+// closeClients closes every local connection with the given close code
+func (h *Hub) closeClients(code int, reason string) {
+	h.mu.RLock()
+	clients := make([]*Client, 0, len(h.clients))
+	for _, client := range h.clients {
+		clients = append(clients, client)
+	}
+	h.mu.RUnlock()
+
+	for _, client := range clients {
+		client.closeWith(code, reason)
+	}
+	h.logger.Info("Closed WebSocket connections", "count", len(clients))
+}
+
+// releasePresence marks every locally connected user offline in Redis
+func (h *Hub) releasePresence() {
+	h.mu.RLock()
+	userIDs := make([]string, 0, len(h.clients))
+	for userID := range h.clients {
+		userIDs = append(userIDs, userID)
+	}
+	h.mu.RUnlock()
+
+	if len(userIDs) == 0 {
+		return
+	}
+	h.recordLastSeen(userIDs...)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*redisOpTimeout)
+	defer cancel()
+	offline, err := h.redisService.ReleasePresence(ctx, h.instanceID, userIDs)
+	if err != nil {
+		// Fall back to marking everyone offline; connected users reappear on the next refresh
+		h.logger.Warn("Failed to release presence keys on shutdown", "error", err)
+		offline = userIDs
+	}
+	if err := h.redisService.SetUsersOffline(ctx, offline); err != nil {
+		h.logger.Error("Failed to release presence on shutdown", "count", len(offline), "error", err)
+		return
+	}
+	h.logger.Info("Released presence for local users", "count", len(userIDs), "offline", len(offline))
+}
+
+// removeClient drops the client from every channel and the client registry.
+// Caller must hold h.mu.
+// registerClient installs a new connection, replacing any existing one for the
+// same user. The old connection is removed from its channels so the channels
+// no longer deliver to it; its channels are kept in resume state.
+func (h *Hub) registerClient(c *Client) {
 	h.mu.Lock()
-	defer h.mu.Unlock()
+	var evicted *Client
+	if existingClient, exists := h.clients[c.userID]; exists {
+		h.logger.Warn("Client already exists, cleaning up old connection", "userID", c.userID)
+		h.removeClient(existingClient)
+		existingClient.close()
+	} else {
+		// Make room if the instance is at its connection cap
+		evicted = h.evictIdlestClient()
+	}
 
-	// Get or create channel
-	if h.channels[channelID] == nil {
-		h.channels[channelID] = make(map[string]*Client)
+	// Register new client
+	h.clients[c.userID] = c
+	h.metrics.observeConnections(len(h.clients))
+
+	// Send connection confirmation
+	connectMsg := NewConnectMessage(uuid.New().String(), c.conn.RemoteAddr().String(), c.userID, c.resumeToken)
+	h.sendToClient(c, connectMsg)
+	h.mu.Unlock()
+
+	if evicted != nil {
+		h.setPresence(evicted.userID, false)
 	}
+	h.setPresence(c.userID, true)
+	h.logger.Info("Client registered successfully", "userID", c.userID, "remoteAddr", c.conn.RemoteAddr().String())
+}
+
+func (h *Hub) removeClient(c *Client) {
+	var joined []string
+	for channelID, clients := range h.channels {
+		if _, exists := clients[c.userID]; exists {
+			joined = append(joined, channelID)
+			delete(clients, c.userID)
+			// Notify other clients in the channel
+			h.notifyChannelMembers(channelID, c.userID, "left")
+			h.notifyPresence(channelID, c.userID, PresenceOffline)
+
+			// Clean up empty channels
+			if len(clients) == 0 {
+				delete(h.channels, channelID)
+			}
+		}
+	}
+	delete(h.clients, c.userID)
+	h.presenceSubs.removeClient(c)
+	h.saveResumeState(c, joined)
+}
+
+// setPresence records the user's online status in Redis so other instances can see it
+func (h *Hub) setPresence(userID string, online bool) {
+	if online {
+		err := h.callRedis(context.Background(), redisOpTimeout, func(ctx context.Context) error {
+			if err := h.redisService.RefreshPresence(ctx, h.instanceID, []string{userID}, presenceKeyTTL); err != nil {
+				return err
+			}
+			return h.redisService.SetUserOnline(ctx, userID)
+		})
+		if err != nil {
+			h.logger.Warn("Failed to record presence", "userID", userID, "error", err)
+		}
+	} else {
+		// Saved even if the user is still connected elsewhere; off the hub loop
+		go h.recordLastSeen(userID)
+
+		stillOnline := false
+		err := h.callRedis(context.Background(), redisOpTimeout, func(ctx context.Context) error {
+			offline, err := h.redisService.ReleasePresence(ctx, h.instanceID, []string{userID})
+			if err != nil {
+				return err
+			}
+			if len(offline) == 0 {
+				// Still connected through another instance
+				stillOnline = true
+				return nil
+			}
+			return h.redisService.SetUserOffline(ctx, userID)
+		})
+		if err != nil {
+			h.logger.Warn("Failed to release presence", "userID", userID, "error", err)
+		} else if stillOnline {
+			return
+		}
+	}
+
+	if h.globalPresence != nil {
+		h.globalPresence.set(userID, online)
+	}
+	if h.presenceNotifier != nil {
+		h.presenceNotifier.NotifyPresence(userID, h.instanceID, online)
+	}
+}
+
+// JoinChannel subscribes the user's connection to the channel. Joining a channel
+// again is a no-op; a new channel beyond the per-connection cap is rejected with
+// ErrTooManyChannels.
+func (h *Hub) JoinChannel(userID string, channelID string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 
 	// Get client
 	client, exists := h.clients[userID]
@@ -138,16 +436,39 @@ func (h *Hub) JoinChannel(userID string, channelID string) error {
 		return ErrClientNotFound
 	}
 
+	if h.channels[channelID][userID] == client {
+		return nil
+	}
+	if limit := h.config.MaxChannelsPerConnection; limit > 0 && h.joinedChannelCount(client) >= limit {
+		return ErrTooManyChannels
+	}
+
+	// Get or create channel
+	if h.channels[channelID] == nil {
+		h.channels[channelID] = make(map[string]*Client)
+	}
+
 	// Add user to channel
 	h.channels[channelID][userID] = client
 
 	// Notify other clients in the channel
 	h.notifyChannelMembers(channelID, userID, "joined")
 
-	slog.Info("User joined channel", "userID", userID, "channelID", channelID)
+	h.logger.Info("User joined channel", "userID", userID, "channelID", channelID)
 	return nil
 }
 
+// joinedChannelCount counts the channels the client joined. Caller must hold h.mu.
+func (h *Hub) joinedChannelCount(c *Client) int {
+	count := 0
+	for _, clients := range h.channels {
+		if clients[c.userID] == c {
+			count++
+		}
+	}
+	return count
+}
+
 func (h *Hub) LeaveChannel(userID string, channelID string) error {
 	h.mu.Lock()
 	defer h.mu.Unlock()
@@ -164,7 +485,7 @@ func (h *Hub) LeaveChannel(userID string, channelID string) error {
 				delete(h.channels, channelID)
 			}
 
-			slog.Info("User left channel", "userID", userID, "channelID", channelID)
+			h.logger.Info("User left channel", "userID", userID, "channelID", channelID)
 			return nil
 		}
 	}
@@ -190,112 +511,177 @@ func (h *Hub) notifyChannelMembers(channelID, userID, action string) {
 	})
 
 	// Broadcast to all clients in the channel except the one who triggered the action
+	messageBytes := h.messageToBytes(notification)
 	for clientUserID, client := range clients {
 		if clientUserID != userID {
-			select {
-			case client.send <- h.messageToBytes(notification):
-			default:
-				slog.Warn("Failed to send notification to client", "userID", clientUserID)
-			}
+			h.sendBytes(client, messageBytes)
 		}
 	}
 }
 
 func (h *Hub) broadcastToChannel(channelID string, message *Message) {
+	// Snapshot the members so the map is not read after the lock is released
 	h.mu.RLock()
-	clients := h.channels[channelID]
+	clients := make([]*Client, 0, len(h.channels[channelID]))
+	for _, client := range h.channels[channelID] {
+		clients = append(clients, client)
+	}
 	h.mu.RUnlock()
 
-	if clients == nil {
-		return
-	}
+	h.fanOut(clients, message)
+}
 
-	messageBytes := h.messageToBytes(message)
-	for userID, client := range clients {
-		select {
-		case client.send <- messageBytes:
-		default:
-			slog.Warn("Failed to send message to client", "userID", userID, "channelID", channelID)
+// broadcastToChannelExcept delivers a message to every client in the channel but one user
+func (h *Hub) broadcastToChannelExcept(channelID, exceptUserID string, message *Message) {
+	h.mu.RLock()
+	clients := make([]*Client, 0, len(h.channels[channelID]))
+	for userID, client := range h.channels[channelID] {
+		if userID != exceptUserID {
+			clients = append(clients, client)
 		}
 	}
+	h.mu.RUnlock()
+
+	h.fanOut(clients, message)
 }
 
-func (h *Hub) handleClientMessage(msgByte []byte) {
-	message := &Message{}
-	if err := json.Unmarshal(msgByte, message); err != nil {
-		slog.Error("Failed to unmarshal message", "error", err)
-		return
+// fanOut sends one encoded message to each client and records the broadcast
+// metrics. It returns how many clients the message was queued for.
+func (h *Hub) fanOut(clients []*Client, message *Message) int {
+	if len(clients) == 0 {
+		return 0
 	}
 
-	// Validate message before processing
-	if err := message.Validate(); err != nil {
-		slog.Error("Invalid message received", "error", err, "message", message)
-		return
+	start := time.Now()
+	messageBytes := h.messageToBytes(message)
+	sent := 0
+	for _, client := range clients {
+		if h.sendBytes(client, messageBytes) {
+			sent++
+		}
 	}
+	h.metrics.observeBroadcast(time.Since(start), sent, len(clients)-sent)
+	return sent
+}
 
-	// Get client
+// BroadcastToChannel delivers a server-originated message to every client in the channel
+func (h *Hub) BroadcastToChannel(channelID string, message *Message) {
+	h.broadcastToChannel(channelID, message)
+}
+
+func (h *Hub) handleClientMessage(clientMessage *ClientMessage) {
+	client := clientMessage.Client
+	message := clientMessage.Message
+
+	// Only the currently registered connection may act for the user
 	h.mu.RLock()
-	client, exists := h.clients[message.UserID]
+	current, exists := h.clients[client.userID]
 	h.mu.RUnlock()
 
-	if !exists {
-		slog.Warn("Client not found for userID", "userID", message.UserID)
+	if !exists || current != client {
+		h.logger.Warn("Dropping message from unregistered client", "userID", client.userID)
 		return
 	}
 
-	switch message.Type {
-	case MessageTypeJoinChannel:
-		h.handleJoinChannel(client, message)
-	case MessageTypeLeaveChannel:
-		h.handleLeaveChannel(client, message)
-	case MessageTypeChannelMessage:
-		h.handleChannelMessage(client, message)
-	default:
+	// The sender is always the authenticated connection, never the payload
+	message.UserID = client.userID
+	h.logger.Debug("Handling client message", "userID", client.userID, "action", message.Type, "messageID", message.ID)
+
+	action, ok := clientActionsByType[message.Type]
+	if !ok {
 		errMsg := NewErrorMessage(uuid.New().String(), client.userID, "UNKNOWN_MESSAGE_TYPE", "Unknown message type")
-		client.send <- h.messageToBytes(errMsg)
+		h.sendToClient(client, errMsg)
+		return
 	}
+	action.handle(h, client, message)
 }
 
 func (h *Hub) handleJoinChannel(client *Client, message *Message) {
 	var data ChannelJoinLeaveData
 	if err := h.mapToStruct(message.Data, &data); err != nil {
-		client.send <- h.messageToBytes(NewErrorMessage(message.ID, client.userID, "INVALID_DATA", "Invalid join channel data"))
+		h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "INVALID_DATA", "Invalid join channel data"))
+		return
+	}
+	if err := data.Validate(); err != nil {
+		h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "INVALID_CHANNEL_ID", err.Error()))
 		return
 	}
 
 	if err := h.JoinChannel(client.userID, data.ChannelID); err != nil {
-		client.send <- h.messageToBytes(NewErrorMessage(message.ID, client.userID, "JOIN_FAILED", err.Error()))
+		if errors.Is(err, ErrTooManyChannels) {
+			h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "TOO_MANY_CHANNELS",
+				fmt.Sprintf("at most %d channels per connection", h.config.MaxChannelsPerConnection)))
+			return
+		}
+		h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "JOIN_FAILED", err.Error()))
 		return
 	}
 
-	// Send success confirmation
+	// Send success confirmation along with the presence of current members
 	successMsg := NewJoinChannelMessage(uuid.New().String(), client.userID, data.ChannelID)
-	client.send <- h.messageToBytes(successMsg)
+	successMsg.Data["members"] = h.channelRoster(data.ChannelID)
+	h.sendToClient(client, successMsg)
 }
 
 func (h *Hub) handleLeaveChannel(client *Client, message *Message) {
 	var data ChannelJoinLeaveData
-	slog.Info("TEST Handle Leave Channel", "message", message)
-	slog.Info("TEST Hub Channels", "channels", h.channels)
 	if err := h.mapToStruct(message.Data, &data); err != nil {
-		client.send <- h.messageToBytes(NewErrorMessage(message.ID, client.userID, "INVALID_DATA", "Invalid leave channel data"))
+		h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "INVALID_DATA", "Invalid leave channel data"))
+		return
+	}
+	if err := data.Validate(); err != nil {
+		h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "INVALID_CHANNEL_ID", err.Error()))
 		return
 	}
 
 	if err := h.LeaveChannel(client.userID, data.ChannelID); err != nil {
-		client.send <- h.messageToBytes(NewErrorMessage(message.ID, client.userID, "LEAVE_FAILED", err.Error()))
+		h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "LEAVE_FAILED", err.Error()))
 		return
 	}
 
 	// Send success confirmation
 	successMsg := NewLeaveChannelMessage(uuid.New().String(), client.userID, data.ChannelID)
-	client.send <- h.messageToBytes(successMsg)
+	h.sendToClient(client, successMsg)
 }
 
 func (h *Hub) handleChannelMessage(client *Client, message *Message) {
+	ctx, span := h.tracer.Start(h.ctx, "websocket.channel_message", trace.WithAttributes(attribute.String("ws.user_id", client.userID)))
+	defer span.End()
+
 	var data ChannelMessageData
 	if err := h.mapToStruct(message.Data, &data); err != nil {
-		client.send <- h.messageToBytes(NewErrorMessage(message.ID, client.userID, "INVALID_DATA", "Invalid message data"))
+		h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "INVALID_DATA", "Invalid message data"))
+		return
+	}
+	// Clients that tag a message with client_msg_id get a nack instead of an error event
+	reject := func(errMsg *Message) {
+		if data.ClientMsgID != nil {
+			errMsg = NewMessageNackMessage(message.ID, client.userID, *data.ClientMsgID, data.ChannelID, errMsg)
+		}
+		h.sendToClient(client, errMsg)
+	}
+
+	channelIDUint, err := parseChannelID(data.ChannelID)
+	if err != nil {
+		reject(NewErrorMessage(message.ID, client.userID, "INVALID_CHANNEL_ID", err.Error()))
+		return
+	}
+	span.SetAttributes(attribute.String("ws.channel_id", data.ChannelID))
+	if err := data.Validate(); err != nil {
+		reject(NewErrorMessage(message.ID, client.userID, "INVALID_DATA", err.Error()))
+		return
+	}
+	if data.Text != nil {
+		if err := h.chatService.ValidateText(*data.Text); err != nil {
+			h.metrics.countError("message_too_large")
+			reject(NewErrorMessage(message.ID, client.userID, "MESSAGE_TOO_LARGE", err.Error()))
+			return
+		}
+	}
+
+	attachments := toAttachmentModels(data.Attachments)
+	if err := h.chatService.ValidateAttachments(attachments); err != nil {
+		reject(NewErrorMessage(message.ID, client.userID, "INVALID_ATTACHMENT", err.Error()))
 		return
 	}
 
@@ -306,61 +692,331 @@ func (h *Hub) handleChannelMessage(client *Client, message *Message) {
 	h.mu.RUnlock()
 
 	if !inChannel {
-		client.send <- h.messageToBytes(NewErrorMessage(message.ID, client.userID, "NOT_IN_CHANNEL", "You are not in this channel"))
+		reject(NewErrorMessage(message.ID, client.userID, "NOT_IN_CHANNEL", "You are not in this channel"))
 		return
 	}
 
-	// Convert client.userID (string) to uint
-	senderIDUint, err := strconv.ParseUint(client.userID, 10, 64)
+	// A resend of an accepted message is acknowledged again instead of posted twice
+	if data.ClientMsgID != nil {
+		if h.recentClientMsgs.isPending(client.userID, *data.ClientMsgID) {
+			// The first copy is acked once its batch is flushed
+			return
+		}
+		if sent := h.findResentMessage(client.userID, *data.ClientMsgID); sent != nil {
+			h.sendToClient(client, NewMessageAckMessage(message.ID, client.userID, data.ClientMsgID, sent, true))
+			return
+		}
+	}
+
+	// Archiving must stop new messages, so a channel whose settings cannot be
+	// loaded is not posted to
+	settings, err := h.channelSettings(channelIDUint)
 	if err != nil {
-		client.send <- h.messageToBytes(NewErrorMessage(message.ID, client.userID, "INVALID_USER_ID", "Invalid user ID format"))
+		h.recordError("persist")
+		reject(NewErrorMessage(message.ID, client.userID, "SAVE_FAILED", "Failed to save message"))
+		return
+	}
+	if settings.archived {
+		reject(NewErrorMessage(message.ID, client.userID, "CHANNEL_ARCHIVED", services.ErrChannelArchived.Error()))
+		return
+	}
+
+	if data.ParentID != nil {
+		if err := h.chatService.ValidateParent(channelIDUint, *data.ParentID); err != nil {
+			if errors.Is(err, services.ErrInvalidParent) {
+				reject(NewErrorMessage(message.ID, client.userID, "INVALID_PARENT", err.Error()))
+				return
+			}
+			h.recordError("persist")
+			h.logger.Error("Failed to validate parent message", "error", err, "userID", client.userID, "parentID", *data.ParentID)
+			reject(NewErrorMessage(message.ID, client.userID, "SAVE_FAILED", "Failed to save message"))
+			return
+		}
+	}
+
+	if retryAfter, allowed := h.checkClientRate(client); !allowed {
+		reject(NewRateLimitErrorMessage(message.ID, client.userID, retryAfter))
+		return
+	}
+
+	// Slow mode is checked before the shared limits, so messages it rejects do
+	// not use up the user's or the channel's rate allowance
+	if retryAfter, allowed := h.checkSlowMode(channelIDUint, data.ChannelID, client.userID); !allowed {
+		h.refundClientRate(client)
+		reject(NewSlowModeErrorMessage(message.ID, client.userID, retryAfter))
 		return
 	}
 
-	// Convert channelID (string) to uint
-	channelIDUint, err := strconv.ParseUint(data.ChannelID, 10, 64)
+	if !h.checkMessageRate(data.ChannelID, client.userID) {
+		h.releaseSlowMode(channelIDUint, data.ChannelID, client.userID)
+		reject(NewRateLimitErrorMessage(message.ID, client.userID, h.config.RateLimitWindow))
+		return
+	}
+
+	// Convert client.userID (string) to uint
+	senderIDUint, err := strconv.ParseUint(client.userID, 10, 64)
 	if err != nil {
-		client.send <- h.messageToBytes(NewErrorMessage(message.ID, client.userID, "INVALID_CHANNEL_ID", "Invalid channel ID format"))
+		reject(NewErrorMessage(message.ID, client.userID, "INVALID_USER_ID", "Invalid user ID format"))
 		return
 	}
 
+	// Assign the public message ID up front so it is part of the broadcast. A
+	// duplicate client ID fails only its own message, also when batching.
+	messageUUID := uuid.New().String()
+	if data.UUID != nil && h.config.AcceptClientUUIDs {
+		messageUUID = *data.UUID
+	}
+
 	// Save message to database
 	chat := &models.Chat{
+		UUID:      &messageUUID,
 		SenderID:  uint(senderIDUint),
-		ChannelID: uint(channelIDUint),
+		ChannelID: channelIDUint,
+		ParentID:  data.ParentID,
 		Text:      data.Text,
 		URL:       data.URL,
 		FileName:  data.FileName,
+
+		ClientMsgID: data.ClientMsgID,
+		Attachments: attachments,
+	}
+
+	// Blocking only applies between the two members of a direct channel
+	if settings.direct {
+		if err := h.chatService.CheckBlocked(h.ctx, chat); err != nil {
+			if errors.Is(err, services.ErrSenderBlocked) {
+				reject(NewErrorMessage(message.ID, client.userID, "BLOCKED", err.Error()))
+				return
+			}
+			h.recordError("persist")
+			h.logger.Error("Failed to check blocked users", "error", err, "userID", client.userID, "channelID", data.ChannelID)
+			reject(NewErrorMessage(message.ID, client.userID, "SAVE_FAILED", "Failed to save message"))
+			return
+		}
+	}
+
+	usage, refundQuota, allowed := h.consumeQuota(client, chat.SenderID)
+	if !allowed {
+		h.sendToClient(client, NewQuotaExceededMessage(message.ID, client.userID, usage))
+		if data.ClientMsgID != nil {
+			reject(NewErrorMessage(message.ID, client.userID, "QUOTA_EXCEEDED", services.ErrQuotaExceeded.Error()))
+		}
+		return
 	}
 
+	// Part of the broadcast, so resolved before the chat is handed to the batcher
+	h.resolveMentions(chat)
+
+	if h.batcher != nil {
+		_, persistSpan := h.tracer.Start(ctx, "websocket.persist", trace.WithAttributes(attribute.Bool("ws.batched", true)))
+		err := h.queueChat(&queuedChat{chat: chat, client: client, messageID: message.ID, refundQuota: refundQuota})
+		if err != nil {
+			recordSpanError(persistSpan, err)
+		}
+		persistSpan.End()
+		if err != nil {
+			refundQuota()
+			h.recordError("persist")
+			h.logger.Error("Failed to queue message for persistence", "error", err, "userID", client.userID, "channelID", data.ChannelID)
+			reject(NewErrorMessage(message.ID, client.userID, "SAVE_FAILED", "Failed to save message"))
+			return
+		}
+		if data.ClientMsgID != nil {
+			h.recentClientMsgs.putPending(client.userID, *data.ClientMsgID, chat)
+		}
+
+		// Acked, or withdrawn and nacked, once the batch is flushed; see completeQueuedChat
+		h.broadcastChat(ctx, message.ID, client.userID, chat)
+		return
+	}
+
+	_, persistSpan := h.tracer.Start(ctx, "websocket.persist", trace.WithAttributes(attribute.Bool("ws.batched", false)))
 	if err := h.chatRepo.Create(chat); err != nil {
-		slog.Error("Failed to save message to database", "error", err, "userID", client.userID, "channelID", data.ChannelID)
-		client.send <- h.messageToBytes(NewErrorMessage(message.ID, client.userID, "SAVE_FAILED", "Failed to save message"))
+		recordSpanError(persistSpan, err)
+		persistSpan.End()
+		refundQuota()
+
+		// A copy resent through another instance may have been stored first, which
+		// fails this insert on the client_msg_id index. That copy is re-acked.
+		if data.ClientMsgID != nil {
+			if existing, findErr := h.chatRepo.FindByClientMsgID(chat.SenderID, *data.ClientMsgID); findErr == nil {
+				h.recentClientMsgs.put(client.userID, *data.ClientMsgID, existing)
+				h.sendToClient(client, NewMessageAckMessage(message.ID, client.userID, data.ClientMsgID, existing, true))
+				return
+			}
+		}
+
+		h.recordError("persist")
+		h.logger.Error("Failed to save message to database", "error", err, "userID", client.userID, "channelID", data.ChannelID)
+		reject(NewErrorMessage(message.ID, client.userID, "SAVE_FAILED", "Failed to save message"))
 		return
 	}
 
 	// Preload sender data
-	chat, err = h.chatRepo.FindByID(chat.ID)
-	if err != nil {
-		slog.Error("Failed to load chat data", "error", err, "chatID", chat.ID)
+	if stored, err := h.chatRepo.FindByID(chat.ID); err != nil {
+		h.logger.Error("Failed to load chat data", "error", err, "chatID", chat.ID)
 		// Continue anyway, we can still broadcast the message
+	} else {
+		stored.Mentions = chat.Mentions
+		chat = stored
 	}
+	persistSpan.End()
 
-	// Prepare message for broadcast
-	broadcastMessage := NewChannelMessage(message.ID, client.userID, chat)
+	if data.ClientMsgID != nil {
+		h.recentClientMsgs.put(client.userID, *data.ClientMsgID, chat)
+	}
 
-	// Broadcast to all clients in the channel
-	h.broadcastToChannel(data.ChannelID, broadcastMessage)
+	h.broadcastChat(ctx, message.ID, client.userID, chat)
+	h.notifyPersisted(chat)
+	h.sendToClient(client, NewMessageAckMessage(message.ID, client.userID, data.ClientMsgID, chat, false))
+}
+
+// DeliverChannelMessage broadcasts a message persisted outside the hub (e.g. via
+// the REST API) and hands it to the same notifiers as messages sent over WebSocket
+func (h *Hub) DeliverChannelMessage(userID string, chat *models.Chat) {
+	ctx, span := h.tracer.Start(h.ctx, "websocket.deliver_message", trace.WithAttributes(attribute.String("ws.user_id", userID)))
+	defer span.End()
+
+	h.resolveMentions(chat)
+	h.broadcastChat(ctx, uuid.New().String(), userID, chat)
+	h.notifyPersisted(chat)
+}
+
+// resolveMentions fills in the members mentioned by the chat
+func (h *Hub) resolveMentions(chat *models.Chat) {
+	if h.mentions == nil {
+		return
+	}
+	mentioned, err := h.mentions.Resolve(chat)
+	if err != nil {
+		h.recordError("mentions")
+		h.logger.Error("Failed to resolve mentions", "error", err, "chatID", chat.ID)
+		// Continue anyway, the message is delivered without mentions
+	}
+	chat.Mentions = mentioned
+}
+
+// broadcastChat sends a channel message to all clients in the channel
+func (h *Hub) broadcastChat(ctx context.Context, messageID, userID string, chat *models.Chat) {
+	channelID := strconv.FormatUint(uint64(chat.ChannelID), 10)
+	h.broadcastChannelMessage(ctx, chat.ChannelID, channelID, NewChannelMessage(messageID, userID, chat))
+}
+
+// notifyPersisted hands a stored channel message to the notifiers
+func (h *Hub) notifyPersisted(chat *models.Chat) {
+	// Let external notification services reach recipients who are offline
+	if h.notifier != nil {
+		h.notifier.NotifyMessage(chat)
+	}
+	if h.webhooks != nil {
+		h.webhooks.NotifyMessage(chat)
+	}
+	if h.mentions != nil {
+		h.mentions.Record(chat)
+	}
+}
+
+func (h *Hub) handleReaction(client *Client, message *Message) {
+	var data ReactionData
+	if err := h.mapToStruct(message.Data, &data); err != nil {
+		h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "INVALID_DATA", "Invalid reaction data"))
+		return
+	}
+	if err := data.Validate(); err != nil {
+		h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "INVALID_DATA", err.Error()))
+		return
+	}
+
+	userIDUint, err := strconv.ParseUint(client.userID, 10, 64)
+	if err != nil {
+		h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "INVALID_USER_ID", "Invalid user ID format"))
+		return
+	}
+
+	var event *models.ReactionEvent
+	if data.Op == models.ReactionOpAdd {
+		event, err = h.chatService.AddReaction(uint(userIDUint), data.MessageID, data.Emoji)
+	} else {
+		event, err = h.chatService.RemoveReaction(uint(userIDUint), data.MessageID, data.Emoji)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrMessageNotFound):
+			h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "MESSAGE_NOT_FOUND", err.Error()))
+		case errors.Is(err, services.ErrNotChannelMember):
+			h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "NOT_IN_CHANNEL", err.Error()))
+		case errors.Is(err, services.ErrInvalidEmoji):
+			h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "INVALID_DATA", err.Error()))
+		default:
+			h.logger.Error("Failed to apply reaction", "error", err, "userID", client.userID, "messageID", data.MessageID)
+			h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "REACTION_FAILED", "Failed to apply reaction"))
+		}
+		return
+	}
+
+	reactionMsg := NewReactionMessage(message.ID, client.userID, event)
+	if !event.Changed {
+		// Duplicate add / missing remove: only echo the current count to the sender
+		h.sendToClient(client, reactionMsg)
+		return
+	}
+	h.broadcastToChannel(strconv.FormatUint(uint64(event.ChannelID), 10), reactionMsg)
+}
+
+// queueChat assigns a reserved ID to the chat and hands it to the write-behind
+// batcher. It fails without blocking when the batcher's queue is full.
+func (h *Hub) queueChat(item *queuedChat) error {
+	chat := item.chat
+	id, err := h.batcher.nextID()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	chat.ID = id
+	chat.CreatedAt = now
+	chat.UpdatedAt = now
+
+	if err := h.chatRepo.LoadSender(chat); err != nil {
+		h.logger.Error("Failed to load sender data", "error", err, "userID", chat.SenderID)
+		// Continue anyway, we can still broadcast the message
+	}
+
+	return h.batcher.enqueue(item)
 }
 
 // =============================================================================
 // Helper Functions
 // =============================================================================
 
+// sendToClient queues a message for the client, see sendBytes
+func (h *Hub) sendToClient(client *Client, message *Message) bool {
+	return h.sendBytes(client, h.messageToBytes(message))
+}
+
+// sendBytes queues data for the client without blocking. A client whose buffer is
+// full is too slow to keep up and gets unregistered; sends to a client that was
+// already closed are dropped.
+func (h *Hub) sendBytes(client *Client, data []byte) bool {
+	sent, closed := client.trySend(data)
+	if sent || closed {
+		return sent
+	}
+
+	client.recordWriteError()
+	h.logger.Warn("Client send buffer full, disconnecting", "userID", client.userID)
+	go func() {
+		select {
+		case h.unregister <- client:
+		case <-h.ctx.Done():
+		}
+	}()
+	return false
+}
+
 func (h *Hub) messageToBytes(message *Message) []byte {
 	data, err := json.Marshal(message)
 	if err != nil {
-		slog.Error("Failed to marshal message", "error", err)
+		h.logger.Error("Failed to marshal message", "error", err)
 		return nil
 	}
 	return data
@@ -371,5 +1027,5 @@ func (h *Hub) mapToStruct(data map[string]interface{}, dest interface{}) error {
 	if err != nil {
 		return err
 	}
-	return json.Unmarshal(jsonBytes, dest)
+	return decodeStrict(jsonBytes, dest)
 }
@@ -0,0 +1,323 @@
+package websocket
+
+import (
+	"chat-service/internal/models"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// dialTestClient returns a Client for userID backed by a real server side
+// connection. No pumps run; frames queued for the client stay in its send channel.
+func dialTestClient(t *testing.T, hub *Hub, userID string) *Client {
+	t.Helper()
+	conns := make(chan *websocket.Conn, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade: %v", err)
+			return
+		}
+		conns <- conn
+	}))
+	t.Cleanup(server.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	serverConn := <-conns
+	t.Cleanup(func() { serverConn.Close() })
+	return NewClient(hub, serverConn, userID)
+}
+
+func TestRegisterReplacesClientInChannels(t *testing.T) {
+	hub := newTestHub(t)
+	hub.redisService, _ = newTestRedis(t)
+
+	old := connectTestClient(hub, "1")
+	peer := connectTestClient(hub, "2")
+	for _, userID := range []string{"1", "2"} {
+		if err := hub.JoinChannel(userID, "10"); err != nil {
+			t.Fatalf("join: %v", err)
+		}
+	}
+
+	reconnected := dialTestClient(t, hub, "1")
+	hub.registerClient(reconnected)
+
+	if hub.clients["1"] != reconnected {
+		t.Fatal("reconnected client is not registered")
+	}
+	if _, ok := hub.channels["10"]["1"]; ok {
+		t.Fatal("old connection still subscribed to the channel")
+	}
+	if hub.channels["10"]["2"] != peer {
+		t.Fatal("other member lost its subscription")
+	}
+	for range old.send {
+	}
+	if !old.closed {
+		t.Fatal("old connection was not closed")
+	}
+
+	// The new connection joins like a fresh one and is the only one delivered to
+	if err := hub.JoinChannel("1", "10"); err != nil {
+		t.Fatalf("rejoin: %v", err)
+	}
+	if hub.channels["10"]["1"] != reconnected {
+		t.Fatal("channel does not deliver to the reconnected client")
+	}
+}
+
+func TestBroadcastMessageEdit(t *testing.T) {
+	hub := newTestHub(t)
+	member := connectTestClient(hub, "1")
+	outsider := connectTestClient(hub, "2")
+	if err := hub.JoinChannel("1", "10"); err != nil {
+		t.Fatalf("join: %v", err)
+	}
+
+	text := "edited"
+	editedAt := time.Now()
+	chat := &models.Chat{ChannelID: 10, Text: &text, EditedAt: &editedAt}
+	hub.BroadcastToChannel("10", NewMessageEditMessage("e1", "1", chat))
+
+	select {
+	case data := <-member.send:
+		var msg Message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			t.Fatalf("decode frame: %v", err)
+		}
+		if msg.Type != MessageTypeMessageEdit || msg.Data["channel_id"] != "10" || msg.Data["text"] != "edited" {
+			t.Fatalf("frame = %s %v, want the edit of channel 10", msg.Type, msg.Data)
+		}
+	default:
+		t.Fatal("channel member did not get the edit")
+	}
+	select {
+	case <-outsider.send:
+		t.Fatal("edit reached a client outside the channel")
+	default:
+	}
+}
+
+func TestSlowClientDoesNotHoldUpBroadcast(t *testing.T) {
+	hub := newTestHub(t)
+	slow := connectTestClient(hub, "1")
+	fast := connectTestClient(hub, "2")
+	for _, userID := range []string{"1", "2"} {
+		if err := hub.JoinChannel(userID, "10"); err != nil {
+			t.Fatalf("join: %v", err)
+		}
+	}
+
+	// The slow client's writer never drains its queue
+	for len(slow.send) < cap(slow.send) {
+		slow.send <- []byte(`{}`)
+	}
+
+	start := time.Now()
+	hub.BroadcastToChannel("10", NewMessage("b1", MessageTypeChannelMessage, "3", map[string]interface{}{"text": "hi"}))
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Fatalf("broadcast took %v with a slow client in the channel", elapsed)
+	}
+
+	select {
+	case <-fast.send:
+	default:
+		t.Fatal("fast client did not get the broadcast")
+	}
+	select {
+	case c := <-hub.unregister:
+		if c != slow {
+			t.Fatalf("unregistered user %s, want the slow client", c.userID)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("slow client was not unregistered")
+	}
+}
+
+func TestUnregisterDuringBroadcast(t *testing.T) {
+	hub := newTestHub(t)
+	hub.redisService, _ = newTestRedis(t)
+	go hub.Run()
+
+	// Each client's send channel is drained until the hub closes it
+	var drained sync.WaitGroup
+	clients := make([]*Client, 0, 20)
+	for i := 1; i <= 20; i++ {
+		userID := strconv.Itoa(i)
+		client := connectTestClient(hub, userID)
+		if err := hub.JoinChannel(userID, "10"); err != nil {
+			t.Fatalf("join channel: %v", err)
+		}
+		clients = append(clients, client)
+		drained.Add(1)
+		go func() {
+			defer drained.Done()
+			for range client.send {
+			}
+		}()
+	}
+
+	var broadcasting sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		broadcasting.Add(1)
+		go func() {
+			defer broadcasting.Done()
+			for i := 0; i < 200; i++ {
+				hub.BroadcastToChannel("10", NewErrorMessage("m", "", "TEST", "hello"))
+			}
+		}()
+	}
+	for _, client := range clients {
+		hub.unregister <- client
+	}
+	broadcasting.Wait()
+
+	done := make(chan struct{})
+	go func() {
+		drained.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("send channels of unregistered clients were not closed")
+	}
+
+	hub.mu.RLock()
+	defer hub.mu.RUnlock()
+	if len(hub.clients) != 0 || len(hub.channels) != 0 {
+		t.Fatalf("hub still holds %d clients and %d channels", len(hub.clients), len(hub.channels))
+	}
+}
+
+func TestJoinChannelCapPerConnection(t *testing.T) {
+	hub := newTestHub(t)
+	hub.config.MaxChannelsPerConnection = 3
+	client := dialTestClient(t, hub, "1")
+	hub.mu.Lock()
+	hub.clients["1"] = client
+	hub.mu.Unlock()
+
+	// join sends a channel.join frame and returns the type and error code of the reply
+	join := func(channelID string) (MessageType, interface{}) {
+		t.Helper()
+		message := NewMessage("j"+channelID, MessageTypeJoinChannel, "1", map[string]interface{}{"channel_id": channelID})
+		hub.handleClientMessage(&ClientMessage{Client: client, Message: message})
+		select {
+		case data := <-client.send:
+			var reply Message
+			if err := json.Unmarshal(data, &reply); err != nil {
+				t.Fatalf("decode reply: %v", err)
+			}
+			return reply.Type, reply.Data["code"]
+		default:
+			t.Fatalf("no reply to joining channel %s", channelID)
+			return "", nil
+		}
+	}
+	subscriptions := func() []string {
+		t.Helper()
+		connections := hub.ListConnections("")
+		if len(connections) != 1 {
+			t.Fatalf("listed %d connections, want 1", len(connections))
+		}
+		return connections[0].Channels
+	}
+
+	for _, channelID := range []string{"1", "2", "3"} {
+		if typ, code := join(channelID); typ != MessageTypeJoinChannel {
+			t.Fatalf("join %s under the cap: reply %s %v", channelID, typ, code)
+		}
+	}
+	for _, channelID := range []string{"4", "5"} {
+		if typ, code := join(channelID); typ != MessageTypeError || code != "TOO_MANY_CHANNELS" {
+			t.Fatalf("join %s past the cap: reply %s %v, want error TOO_MANY_CHANNELS", channelID, typ, code)
+		}
+	}
+	if got := subscriptions(); strings.Join(got, ",") != "1,2,3" {
+		t.Fatalf("subscriptions = %v, want [1 2 3]", got)
+	}
+	hub.mu.RLock()
+	_, created := hub.channels["4"]
+	hub.mu.RUnlock()
+	if created {
+		t.Fatal("rejected join created a channel entry")
+	}
+
+	// Rejoining a channel does not count against the cap
+	if typ, code := join("2"); typ != MessageTypeJoinChannel {
+		t.Fatalf("rejoin at the cap: reply %s %v", typ, code)
+	}
+	// Leaving frees a slot
+	if err := hub.LeaveChannel("1", "1"); err != nil {
+		t.Fatalf("leave channel: %v", err)
+	}
+	if typ, code := join("4"); typ != MessageTypeJoinChannel {
+		t.Fatalf("join after leaving: reply %s %v", typ, code)
+	}
+	if got := subscriptions(); strings.Join(got, ",") != "2,3,4" {
+		t.Fatalf("subscriptions = %v, want [2 3 4]", got)
+	}
+}
+
+func TestRegisterEvictsIdlestClientAtCapacity(t *testing.T) {
+	hub := newTestHub(t)
+	hub.redisService, _ = newTestRedis(t)
+	hub.config.MaxConnections = 3
+
+	now := time.Now()
+	clients := make(map[string]*Client)
+	for i, idle := range []time.Duration{time.Minute, time.Hour, time.Second} {
+		userID := strconv.Itoa(i + 1)
+		client := dialTestClient(t, hub, userID)
+		client.lastActivity = now.Add(-idle)
+		hub.registerClient(client)
+		clients[userID] = client
+	}
+
+	// A reconnect replaces the user's own connection and evicts nobody
+	replacement := dialTestClient(t, hub, "3")
+	hub.registerClient(replacement)
+	clients["3"] = replacement
+	for userID := range clients {
+		if hub.clients[userID] != clients[userID] {
+			t.Fatalf("user %s was evicted by a reconnect", userID)
+		}
+	}
+
+	// A new user at capacity evicts the client idle the longest, user 2
+	hub.registerClient(dialTestClient(t, hub, "4"))
+	hub.mu.RLock()
+	_, stillThere := hub.clients["2"]
+	count := len(hub.clients)
+	hub.mu.RUnlock()
+	if stillThere || count != 3 {
+		t.Fatalf("after the next add: user 2 registered=%v, %d clients; want user 2 evicted and 3 clients", stillThere, count)
+	}
+
+	evicted := clients["2"]
+	evicted.mu.Lock()
+	closed, code := evicted.closed, evicted.closeCode
+	evicted.mu.Unlock()
+	if !closed || code != websocket.CloseTryAgainLater {
+		t.Fatalf("evicted client closed=%v code=%d, want closed with %d", closed, code, websocket.CloseTryAgainLater)
+	}
+	hub.metrics.mu.Lock()
+	evictions := hub.metrics.errors["connection_evicted"]
+	hub.metrics.mu.Unlock()
+	if evictions != 1 {
+		t.Fatalf("connection_evicted count = %d, want 1", evictions)
+	}
+}
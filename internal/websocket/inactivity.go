@@ -0,0 +1,127 @@
+package websocket
+
+import (
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// readTimeout is the read deadline for a connection. With the inactivity check
+// enabled it outlasts the probe so the reaper, not the deadline, decides.
+func (h *Hub) readTimeout() time.Duration {
+	if h.config.InactivityTimeout <= 0 {
+		return pongWait
+	}
+	return h.config.InactivityTimeout + h.config.InactivityGrace + h.writeWait()
+}
+
+// runInactivityReaper applies the two-stage inactivity policy: a connection idle
+// for InactivityTimeout is sent a final ping, and is only dropped if no pong or
+// message arrives within InactivityGrace. Idle clients on a healthy connection
+// answer the ping and stay connected.
+func (h *Hub) runInactivityReaper() {
+	interval := h.config.InactivityGrace / 2
+	if interval <= 0 || interval > 15*time.Second {
+		interval = 15 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-h.ctx.Done():
+			return
+		case <-ticker.C:
+			h.reapInactiveClients()
+		}
+	}
+}
+
+func (h *Hub) reapInactiveClients() {
+	h.mu.RLock()
+	clients := make([]*Client, 0, len(h.clients))
+	for _, client := range h.clients {
+		clients = append(clients, client)
+	}
+	h.mu.RUnlock()
+
+	now := time.Now()
+	for _, client := range clients {
+		client.mu.Lock()
+		// Any inbound frame, message or pong, shows the connection is alive
+		lastActivity := client.lastActivity
+		if client.lastHeartbeat.After(lastActivity) {
+			lastActivity = client.lastHeartbeat
+		}
+		probeSentAt := client.probeSentAt
+		if !probeSentAt.IsZero() && lastActivity.After(probeSentAt) {
+			// The client answered the probe
+			client.probeSentAt = time.Time{}
+			probeSentAt = time.Time{}
+		}
+		client.mu.Unlock()
+
+		switch {
+		case probeSentAt.IsZero() && now.Sub(lastActivity) >= h.config.InactivityTimeout:
+			h.probeClient(client, now)
+		case !probeSentAt.IsZero() && now.Sub(probeSentAt) >= h.config.InactivityGrace:
+			h.logger.Info("Disconnecting unresponsive client", "userID", client.userID, "idle", now.Sub(lastActivity).String())
+			h.dropClient(client)
+		}
+	}
+}
+
+// probeClient sends the final ping before the grace period starts
+func (h *Hub) probeClient(client *Client, now time.Time) {
+	client.mu.Lock()
+	client.probeSentAt = now
+	client.mu.Unlock()
+
+	// WriteControl is safe to call concurrently with writePump
+	if err := client.conn.WriteControl(websocket.PingMessage, nil, now.Add(h.writeWait())); err != nil {
+		h.logger.Debug("Failed to send inactivity probe", "userID", client.userID, "error", err)
+	}
+}
+
+// dropClient removes a client the hub decided to disconnect and closes it
+func (h *Hub) dropClient(client *Client) {
+	h.mu.Lock()
+	current, exists := h.clients[client.userID]
+	removed := exists && current == client
+	if removed {
+		h.removeClient(client)
+	}
+	h.mu.Unlock()
+
+	client.close()
+	if removed {
+		h.setPresence(client.userID, false)
+	}
+}
+
+// evictIdlestClient closes the connection with the oldest inbound activity when
+// the hub is at MaxConnections, and returns it. Caller must hold h.mu.
+func (h *Hub) evictIdlestClient() *Client {
+	limit := h.config.MaxConnections
+	if limit <= 0 || len(h.clients) < limit {
+		return nil
+	}
+
+	var victim *Client
+	var oldest time.Time
+	for _, client := range h.clients {
+		client.mu.Lock()
+		lastActivity := client.lastActivity
+		client.mu.Unlock()
+		if victim == nil || lastActivity.Before(oldest) {
+			victim, oldest = client, lastActivity
+		}
+	}
+
+	h.removeClient(victim)
+	victim.closeWith(websocket.CloseTryAgainLater, "connection limit reached")
+	h.metrics.countError("connection_evicted")
+	h.logger.Warn("Connection limit reached, evicted least recently active client",
+		"userID", victim.userID, "idle", time.Since(oldest).String(), "limit", limit)
+	return victim
+}
@@ -0,0 +1,66 @@
+package websocket
+
+import "time"
+
+// recordLastSeen saves the current time as the users' last seen time. Users
+// whose IDs are not numeric are skipped.
+func (h *Hub) recordLastSeen(userIDs ...string) {
+	if h.presenceRepo == nil || len(userIDs) == 0 {
+		return
+	}
+
+	ids := make([]uint, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if id, err := parseUserID(userID); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	if err := h.presenceRepo.TouchLastSeen(ids, time.Now()); err != nil {
+		h.recordError("persist")
+		h.logger.Warn("Failed to record last seen", "count", len(ids), "error", err)
+	}
+}
+
+// runLastSeenRefresh periodically saves last seen for every connected user, so
+// users of an instance that dies without a clean disconnect are not left with
+// a stale time
+func (h *Hub) runLastSeenRefresh() {
+	ticker := time.NewTicker(h.config.LastSeenInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-h.ctx.Done():
+			return
+		case <-ticker.C:
+			h.recordLastSeen(h.localUserIDs()...)
+		}
+	}
+}
+
+// localUserIDs returns the users connected to this instance
+func (h *Hub) localUserIDs() []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	userIDs := make([]string, 0, len(h.clients))
+	for userID := range h.clients {
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs
+}
+
+// lastSeen returns when an offline user was last connected according to the database
+func (h *Hub) lastSeen(userID string) *time.Time {
+	if h.presenceRepo == nil {
+		return nil
+	}
+	id, err := parseUserID(userID)
+	if err != nil {
+		return nil
+	}
+	seenAt, err := h.presenceRepo.GetLastSeen(id)
+	if err != nil {
+		h.logger.Warn("Failed to read last seen", "userID", userID, "error", err)
+		return nil
+	}
+	return seenAt
+}
@@ -0,0 +1,42 @@
+package websocket
+
+import (
+	"context"
+	"log/slog"
+)
+
+// newHubLogger returns the default logger tagged with the websocket component
+// and filtered to the given level. An unknown level falls back to info.
+func newHubLogger(level string) *slog.Logger {
+	var minLevel slog.Level
+	if err := minLevel.UnmarshalText([]byte(level)); err != nil {
+		slog.Warn("Unknown WebSocket log level, using info", "level", level)
+		minLevel = slog.LevelInfo
+	}
+	handler := &levelHandler{level: minLevel, handler: slog.Default().Handler()}
+	return slog.New(handler).With("component", "websocket")
+}
+
+// levelHandler drops records below its level and passes the rest on. It does not
+// consult the wrapped handler's own level, so debug logs can be enabled for the
+// hub alone.
+type levelHandler struct {
+	level   slog.Level
+	handler slog.Handler
+}
+
+func (h *levelHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *levelHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.handler.Handle(ctx, r)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{level: h.level, handler: h.handler.WithAttrs(attrs)}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{level: h.level, handler: h.handler.WithGroup(name)}
+}
@@ -1,9 +1,15 @@
 package websocket
 
 import (
+	"bytes"
+	"chat-service/internal/models"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // MessageType represents the type of WebSocket message using a custom enum type for better type safety
@@ -14,11 +20,40 @@ const (
 	// Connection events
 	MessageTypeConnect    MessageType = "connection.connect"
 	MessageTypeDisconnect MessageType = "connection.disconnect"
+	// Server-initiated: the session was revoked and the connection is being closed.
+	// This is the "force-logout" frame; it is namespaced like every other type.
+	MessageTypeForceLogout MessageType = "connection.force_logout"
+	// Server-initiated: connection quality is poor and the client should reconnect
+	MessageTypeReconnectHint MessageType = "connection.reconnect_hint"
+	// Rejoin a previous connection's channels with its resume token
+	MessageTypeResume MessageType = "connection.resume"
+
+	// User events
+	MessageTypePresence MessageType = "user.presence"
+	// Server-initiated: the user has used up their daily message quota
+	MessageTypeQuotaExceeded MessageType = "user.quota_exceeded"
+
+	// Presence subscriptions for users outside your channels, e.g. a contact list
+	MessageTypePresenceSubscribe   MessageType = "presence.subscribe"
+	MessageTypePresenceUnsubscribe MessageType = "presence.unsubscribe"
+	MessageTypePresenceUpdate      MessageType = "presence.update"
 
 	// Channel events
 	MessageTypeJoinChannel    MessageType = "channel.join"
 	MessageTypeLeaveChannel   MessageType = "channel.leave"
 	MessageTypeChannelMessage MessageType = "channel.message"
+	MessageTypeReaction       MessageType = "channel.reaction"
+	MessageTypeTyping         MessageType = "channel.typing"
+	MessageTypeRead           MessageType = "channel.read"
+	MessageTypeMessageEdit    MessageType = "channel.message.edit"
+	MessageTypeMessageDelete  MessageType = "channel.message.delete"
+	MessageTypeMessagePin     MessageType = "channel.message.pin"
+	MessageTypeMessageUnpin   MessageType = "channel.message.unpin"
+	MessageTypeMessageAck     MessageType = "channel.message.ack"
+	MessageTypeMessageNack    MessageType = "channel.message.nack"
+
+	// Direct message events, addressed to a user rather than a channel
+	MessageTypeDirectMessage MessageType = "direct.message"
 
 	// Error events
 	MessageTypeError MessageType = "error"
@@ -32,8 +67,9 @@ func (mt MessageType) String() string {
 // IsValid checks if the MessageType is a valid enum value
 func (mt MessageType) IsValid() bool {
 	switch mt {
-	case MessageTypeConnect, MessageTypeDisconnect, MessageTypeJoinChannel,
-		MessageTypeLeaveChannel, MessageTypeChannelMessage, MessageTypeError:
+	case MessageTypeConnect, MessageTypeDisconnect, MessageTypeForceLogout, MessageTypeReconnectHint, MessageTypeResume, MessageTypePresence, MessageTypeQuotaExceeded,
+		MessageTypePresenceSubscribe, MessageTypePresenceUnsubscribe, MessageTypePresenceUpdate,
+		MessageTypeJoinChannel, MessageTypeLeaveChannel, MessageTypeChannelMessage, MessageTypeMessageEdit, MessageTypeMessageDelete, MessageTypeMessagePin, MessageTypeMessageUnpin, MessageTypeMessageAck, MessageTypeMessageNack, MessageTypeDirectMessage, MessageTypeReaction, MessageTypeTyping, MessageTypeRead, MessageTypeError:
 		return true
 	default:
 		return false
@@ -43,14 +79,23 @@ func (mt MessageType) IsValid() bool {
 // GetAllMessageTypes returns all valid message types for documentation and validation
 func GetAllMessageTypes() []MessageType {
 	return []MessageType{
-		MessageTypeConnect, MessageTypeDisconnect, MessageTypeJoinChannel,
-		MessageTypeLeaveChannel, MessageTypeChannelMessage, MessageTypeError,
+		MessageTypeConnect, MessageTypeDisconnect, MessageTypeForceLogout, MessageTypeReconnectHint, MessageTypeResume, MessageTypePresence, MessageTypeQuotaExceeded,
+		MessageTypePresenceSubscribe, MessageTypePresenceUnsubscribe, MessageTypePresenceUpdate,
+		MessageTypeJoinChannel, MessageTypeLeaveChannel, MessageTypeChannelMessage, MessageTypeMessageEdit, MessageTypeMessageDelete, MessageTypeMessagePin, MessageTypeMessageUnpin, MessageTypeMessageAck, MessageTypeMessageNack, MessageTypeDirectMessage, MessageTypeReaction, MessageTypeTyping, MessageTypeRead, MessageTypeError,
 	}
 }
 
+// EnvelopeVersion is the version of the message envelope this server speaks.
+// Clients may omit the version, which is read as version 1.
+const EnvelopeVersion = 1
+
+// ErrUnsupportedVersion is returned for client frames with an envelope version the server does not speak
+var ErrUnsupportedVersion = errors.New("unsupported message version")
+
 // Base message structure with typed MessageType for better type safety
 type Message struct {
 	ID        string                 `json:"id"`
+	Version   int                    `json:"version,omitempty"`
 	Type      MessageType            `json:"type"`
 	Data      map[string]interface{} `json:"data"`
 	Timestamp int64                  `json:"timestamp"`
@@ -62,6 +107,9 @@ func (m *Message) Validate() error {
 	if m.ID == "" {
 		return fmt.Errorf("message ID is required")
 	}
+	if m.Version != 0 && m.Version != EnvelopeVersion {
+		return fmt.Errorf("%w %d, this server speaks version %d", ErrUnsupportedVersion, m.Version, EnvelopeVersion)
+	}
 	if !m.Type.IsValid() {
 		return fmt.Errorf("invalid message type: %s", m.Type)
 	}
@@ -71,30 +119,315 @@ func (m *Message) Validate() error {
 	return nil
 }
 
+// ErrIncompleteFrame is returned for frames that are not exactly one complete JSON document,
+// such as a truncated payload or one fragment of a larger message
+var ErrIncompleteFrame = errors.New("frame is not a complete JSON document")
+
+// DecodeMessage strictly decodes a client frame, rejecting unknown fields and mistyped values
+func DecodeMessage(data []byte) (*Message, error) {
+	// The decoder alone would accept a valid prefix followed by trailing bytes,
+	// so check the whole frame parses as a single document first
+	if !json.Valid(data) {
+		return nil, ErrIncompleteFrame
+	}
+
+	message := &Message{}
+	if err := decodeStrict(data, message); err != nil {
+		return nil, fmt.Errorf("invalid message format: %w", err)
+	}
+	if err := message.Validate(); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// decodeStrict unmarshals JSON into dest, failing on fields dest does not declare
+func decodeStrict(data []byte, dest interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(dest)
+}
+
+// parseChannelID validates that a channel ID is a positive integer
+func parseChannelID(channelID string) (uint, error) {
+	id, err := strconv.ParseUint(channelID, 10, 64)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("channel_id must be a positive integer")
+	}
+	return uint(id), nil
+}
+
 // Message data structures for different message types
 type ChannelMessageData struct {
-	ChannelID string  `json:"channel_id" binding:"required" validate:"required"`
-	Text      *string `json:"text,omitempty"`
-	URL       *string `json:"url,omitempty"`
-	FileName  *string `json:"fileName,omitempty"`
+	ChannelID   string  `json:"channel_id" binding:"required" validate:"required"`
+	UUID        *string `json:"uuid,omitempty"`          // optional client-generated message ID
+	ClientMsgID *string `json:"client_msg_id,omitempty"` // client reference echoed in the ack or nack; resends with the same value are not posted twice
+	ParentID    *uint   `json:"parent_id,omitempty"`     // reply to this top-level message in the same channel
+	Text        *string `json:"text,omitempty"`
+	URL         *string `json:"url,omitempty"`
+	FileName    *string `json:"fileName,omitempty"`
+
+	Attachments []AttachmentData `json:"attachments,omitempty"`
+}
+
+// AttachmentData is the metadata of a file attached to a channel message
+type AttachmentData struct {
+	URL      string `json:"url" validate:"required"`
+	MimeType string `json:"mime_type" validate:"required"`
+	Size     int64  `json:"size"` // bytes
+	Width    *int   `json:"width,omitempty"`
+	Height   *int   `json:"height,omitempty"`
+}
+
+// toAttachmentModels converts attachment payloads to unsaved attachment rows
+func toAttachmentModels(data []AttachmentData) []models.Attachment {
+	if len(data) == 0 {
+		return nil
+	}
+	attachments := make([]models.Attachment, len(data))
+	for i, d := range data {
+		attachments[i] = models.Attachment{URL: d.URL, MimeType: d.MimeType, Size: d.Size, Width: d.Width, Height: d.Height}
+	}
+	return attachments
+}
+
+// Longest client_msg_id echoed back in acks
+const maxClientMsgIDLength = 128
+
+// Validate checks the channel ID and optional IDs of a channel message
+func (d *ChannelMessageData) Validate() error {
+	if _, err := parseChannelID(d.ChannelID); err != nil {
+		return err
+	}
+	if d.UUID != nil {
+		if _, err := uuid.Parse(*d.UUID); err != nil {
+			return fmt.Errorf("uuid must be a valid UUID")
+		}
+	}
+	if d.ClientMsgID != nil && len(*d.ClientMsgID) > maxClientMsgIDLength {
+		return fmt.Errorf("client_msg_id must be at most %d bytes", maxClientMsgIDLength)
+	}
+	return nil
+}
+
+// parseUserID validates that a user ID is a positive integer
+func parseUserID(userID string) (uint, error) {
+	id, err := strconv.ParseUint(userID, 10, 64)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("receiver_id must be a positive integer")
+	}
+	return uint(id), nil
+}
+
+// DirectMessageData is a 1:1 message addressed to a user instead of a channel
+type DirectMessageData struct {
+	ReceiverID string  `json:"receiver_id" validate:"required"`
+	Text       *string `json:"text,omitempty"`
+	URL        *string `json:"url,omitempty"`
+	FileName   *string `json:"fileName,omitempty"`
+}
+
+// Validate checks the recipient and that the message has content
+func (d *DirectMessageData) Validate() error {
+	if _, err := parseUserID(d.ReceiverID); err != nil {
+		return err
+	}
+	if d.Text == nil && d.URL == nil {
+		return fmt.Errorf("text or url is required")
+	}
+	return nil
 }
 
 type ChannelJoinLeaveData struct {
 	ChannelID string `json:"channel_id" binding:"required" validate:"required"`
 }
 
+// Validate checks the channel ID of a join/leave request
+func (d *ChannelJoinLeaveData) Validate() error {
+	_, err := parseChannelID(d.ChannelID)
+	return err
+}
+
+type MessageEditEventData struct {
+	ChannelID string    `json:"channel_id" validate:"required"`
+	MessageID uint      `json:"message_id" validate:"required"`
+	Text      *string   `json:"text"`
+	EditedAt  time.Time `json:"edited_at" validate:"required"`
+}
+
+// MessageAckData confirms to the sender that a channel message was accepted
+type MessageAckData struct {
+	ClientMsgID *string `json:"client_msg_id,omitempty"`
+	ChannelID   string  `json:"channel_id" validate:"required"`
+	MessageID   uint    `json:"message_id,omitempty"` // 0 while the message waits in the write-behind batch
+	UUID        string  `json:"uuid" validate:"required"`
+	Duplicate   bool    `json:"duplicate,omitempty"` // client_msg_id was already accepted; nothing new was posted
+}
+
+// MessageNackData tells the sender a channel message was rejected and why
+type MessageNackData struct {
+	ClientMsgID  string `json:"client_msg_id" validate:"required"`
+	ChannelID    string `json:"channel_id" validate:"required"`
+	Reason       string `json:"reason" validate:"required"` // same codes as error events
+	Message      string `json:"message"`
+	RetryAfterMs *int64 `json:"retry_after_ms,omitempty"`
+}
+
+type MessageDeleteEventData struct {
+	ChannelID string `json:"channel_id" validate:"required"`
+	MessageID uint   `json:"message_id" validate:"required"`
+}
+
+// PinEventData tells channel members a message was pinned or unpinned
+type PinEventData struct {
+	ChannelID string     `json:"channel_id" validate:"required"`
+	MessageID uint       `json:"message_id" validate:"required"`
+	UserID    string     `json:"user_id" validate:"required"` // who pinned, or who unpinned
+	PinnedAt  *time.Time `json:"pinned_at,omitempty"`         // absent for unpins
+}
+
+type ReactionData struct {
+	MessageID uint   `json:"message_id" validate:"required"`
+	Emoji     string `json:"emoji" validate:"required"`
+	Op        string `json:"op" validate:"required"` // "add" | "remove"
+}
+
+// Validate checks a reaction request
+func (d *ReactionData) Validate() error {
+	if d.MessageID == 0 {
+		return fmt.Errorf("message_id is required")
+	}
+	if d.Emoji == "" {
+		return fmt.Errorf("emoji is required")
+	}
+	if d.Op != models.ReactionOpAdd && d.Op != models.ReactionOpRemove {
+		return fmt.Errorf("op must be %q or %q", models.ReactionOpAdd, models.ReactionOpRemove)
+	}
+	return nil
+}
+
+type TypingData struct {
+	ChannelID string `json:"channel_id" validate:"required"`
+	IsTyping  bool   `json:"is_typing"`
+}
+
+type TypingEventData struct {
+	ChannelID string `json:"channel_id" validate:"required"`
+	UserID    string `json:"user_id" validate:"required"`
+	IsTyping  bool   `json:"is_typing"`
+}
+
+type ReadData struct {
+	ChannelID string `json:"channel_id" validate:"required"`
+	MessageID uint   `json:"message_id" validate:"required"` // last message read
+}
+
+type ReadEventData struct {
+	ChannelID string `json:"channel_id" validate:"required"`
+	UserID    string `json:"user_id" validate:"required"`
+	MessageID uint   `json:"message_id" validate:"required"`
+}
+
 type ErrorData struct {
-	Code    string `json:"code" validate:"required"`
-	Message string `json:"message" validate:"required"`
+	Code         string `json:"code" validate:"required"`
+	Message      string `json:"message" validate:"required"`
+	RetryAfterMs *int64 `json:"retry_after_ms,omitempty"` // set for SLOW_MODE and RATE_LIMITED
+}
+
+type ForceLogoutData struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+type ReconnectHintData struct {
+	Reason string `json:"reason" validate:"required"`
+	Score  int    `json:"score"` // connection quality score, 0-100
+}
+
+// PresenceSubscribeData lists users whose online/offline changes the client wants.
+// For unsubscribe an empty list clears every subscription.
+type PresenceSubscribeData struct {
+	UserIDs []string `json:"user_ids"`
+}
+
+// PresenceSubscribedData confirms a subscription with the current status of each user
+type PresenceSubscribedData struct {
+	Statuses map[string]PresenceStatus `json:"statuses" validate:"required"` // user ID -> online | offline
+}
+
+// PresenceUpdateData is an online/offline change of a subscribed user
+type PresenceUpdateData struct {
+	UserID string         `json:"user_id" validate:"required"`
+	Status PresenceStatus `json:"status" validate:"required"` // online | offline
+}
+
+type PresenceData struct {
+	ChannelID string         `json:"channel_id" validate:"required"`
+	UserID    string         `json:"user_id" validate:"required"`
+	Status    PresenceStatus `json:"status" validate:"required"` // online | away | offline
+}
+
+type ReactionEventData struct {
+	ChannelID string `json:"channel_id" validate:"required"`
+	MessageID uint   `json:"message_id" validate:"required"`
+	UserID    string `json:"user_id" validate:"required"`
+	Emoji     string `json:"emoji" validate:"required"`
+	Op        string `json:"op" validate:"required"`
+	Count     int64  `json:"count" validate:"required"` // reactions with this emoji after the change
+
+	Reactions []ReactionSummaryData `json:"reactions"` // every emoji on the message after the change
+}
+
+// ReactionSummaryData is one emoji's count and reacting users on a message
+type ReactionSummaryData struct {
+	Emoji   string   `json:"emoji"`
+	Count   int64    `json:"count"`
+	UserIDs []string `json:"user_ids"`
 }
 
 type ConnectData struct {
-	ClientID string `json:"client_id"`
-	Status   string `json:"status"`
+	ClientID    string `json:"client_id"`
+	Status      string `json:"status"`
+	ResumeToken string `json:"resume_token,omitempty"` // send with connection.resume after reconnecting; absent when resume is disabled
+}
+
+// ResumeData asks to rejoin the channels of a previous connection
+type ResumeData struct {
+	ResumeToken string `json:"resume_token" validate:"required"`
+}
+
+// Validate checks a resume request
+func (d *ResumeData) Validate() error {
+	if d.ResumeToken == "" {
+		return fmt.Errorf("resume_token is required")
+	}
+	return nil
+}
+
+// ResumedData lists the channels rejoined by a resume
+type ResumedData struct {
+	Channels []string `json:"channels"`
+}
+
+// QuotaExceededData tells the sender their message was dropped by the daily quota
+type QuotaExceededData struct {
+	Limit    int       `json:"limit"`
+	Used     int64     `json:"used"`
+	ResetsAt time.Time `json:"resets_at"` // next UTC midnight
 }
 
 // Message constructors for type safety and consistency
 
+// toDataMap converts a payload struct to the generic message data map
+func toDataMap(data interface{}) map[string]interface{} {
+	dataMap := make(map[string]interface{})
+	if data != nil {
+		if dataBytes, err := json.Marshal(data); err == nil {
+			json.Unmarshal(dataBytes, &dataMap)
+		}
+	}
+	return dataMap
+}
+
 // NewMessage creates a new message with the specified type and data
 func NewMessage(id string, msgType MessageType, userID string, data map[string]interface{}) *Message {
 	if data == nil {
@@ -102,6 +435,7 @@ func NewMessage(id string, msgType MessageType, userID string, data map[string]i
 	}
 	return &Message{
 		ID:        id,
+		Version:   EnvelopeVersion,
 		Type:      msgType,
 		Data:      data,
 		Timestamp: time.Now().Unix(),
@@ -110,11 +444,56 @@ func NewMessage(id string, msgType MessageType, userID string, data map[string]i
 }
 
 // NewConnectMessage creates a connection success message
-func NewConnectMessage(id, clientID, userID string) *Message {
-	return NewMessage(id, MessageTypeConnect, userID, map[string]interface{}{
-		"client_id": clientID,
-		"status":    "connected",
-	})
+func NewConnectMessage(id, clientID, userID, resumeToken string) *Message {
+	return NewMessage(id, MessageTypeConnect, userID, toDataMap(ConnectData{
+		ClientID:    clientID,
+		Status:      "connected",
+		ResumeToken: resumeToken,
+	}))
+}
+
+// NewResumeMessage confirms a resume with the channels that were rejoined
+func NewResumeMessage(id, userID string, channels []string) *Message {
+	return NewMessage(id, MessageTypeResume, userID, toDataMap(ResumedData{Channels: channels}))
+}
+
+// NewForceLogoutMessage tells the client its session was revoked
+func NewForceLogoutMessage(id, userID, reason string) *Message {
+	return NewMessage(id, MessageTypeForceLogout, userID, toDataMap(ForceLogoutData{Reason: reason}))
+}
+
+// NewReconnectHintMessage suggests the client reconnects because its connection quality is poor
+func NewReconnectHintMessage(id, userID string, score int) *Message {
+	return NewMessage(id, MessageTypeReconnectHint, userID, toDataMap(ReconnectHintData{
+		Reason: "poor connection quality",
+		Score:  score,
+	}))
+}
+
+// NewQuotaExceededMessage tells a user their daily message quota is used up
+func NewQuotaExceededMessage(id, userID string, usage *models.MessageQuotaUsage) *Message {
+	return NewMessage(id, MessageTypeQuotaExceeded, userID, toDataMap(QuotaExceededData{
+		Limit:    usage.Limit,
+		Used:     usage.Used,
+		ResetsAt: usage.ResetsAt,
+	}))
+}
+
+// NewPresenceMessage announces a user's presence status to a channel
+func NewPresenceMessage(id, userID, channelID string, status PresenceStatus) *Message {
+	return NewMessage(id, MessageTypePresence, userID, toDataMap(PresenceData{
+		ChannelID: channelID,
+		UserID:    userID,
+		Status:    status,
+	}))
+}
+
+// NewPresenceUpdateMessage tells a subscriber that a user went online or offline
+func NewPresenceUpdateMessage(id, subscriberID, userID string, status PresenceStatus) *Message {
+	return NewMessage(id, MessageTypePresenceUpdate, subscriberID, toDataMap(PresenceUpdateData{
+		UserID: userID,
+		Status: status,
+	}))
 }
 
 // NewErrorMessage creates an error message
@@ -125,16 +504,143 @@ func NewErrorMessage(id, userID, code, message string) *Message {
 	})
 }
 
+// NewSlowModeErrorMessage rejects a send made before the channel's slow mode interval elapsed
+func NewSlowModeErrorMessage(id, userID string, retryAfter time.Duration) *Message {
+	retryAfterMs := retryAfter.Milliseconds()
+	return NewMessage(id, MessageTypeError, userID, toDataMap(ErrorData{
+		Code:         "SLOW_MODE",
+		Message:      "Slow mode is enabled for this channel",
+		RetryAfterMs: &retryAfterMs,
+	}))
+}
+
+// NewRateLimitErrorMessage rejects a send that exceeded the user or channel message rate
+func NewRateLimitErrorMessage(id, userID string, retryAfter time.Duration) *Message {
+	retryAfterMs := retryAfter.Milliseconds()
+	return NewMessage(id, MessageTypeError, userID, toDataMap(ErrorData{
+		Code:         "RATE_LIMITED",
+		Message:      "Too many messages, slow down",
+		RetryAfterMs: &retryAfterMs,
+	}))
+}
+
 // NewChannelMessage creates a channel message
 func NewChannelMessage(id, userID string, data interface{}) *Message {
-	dataMap := make(map[string]interface{})
-	if data != nil {
-		// Convert struct to map for JSON serialization
-		if dataBytes, err := json.Marshal(data); err == nil {
-			json.Unmarshal(dataBytes, &dataMap)
+	return NewMessage(id, MessageTypeChannelMessage, userID, toDataMap(data))
+}
+
+// NewDirectMessage delivers a direct message to its recipient and echoes it to the sender
+func NewDirectMessage(id, userID string, chat *models.Chat) *Message {
+	return NewMessage(id, MessageTypeDirectMessage, userID, toDataMap(chat))
+}
+
+// NewMessageEditMessage tells channel members that a message's text changed
+func NewMessageEditMessage(id, userID string, chat *models.Chat) *Message {
+	data := MessageEditEventData{
+		ChannelID: strconv.FormatUint(uint64(chat.ChannelID), 10),
+		MessageID: chat.ID,
+		Text:      chat.Text,
+	}
+	if chat.EditedAt != nil {
+		data.EditedAt = *chat.EditedAt
+	}
+	return NewMessage(id, MessageTypeMessageEdit, userID, toDataMap(data))
+}
+
+// NewMessageAckMessage confirms an accepted channel message to its sender.
+// duplicate marks the re-ack of a message resent with a known client_msg_id.
+func NewMessageAckMessage(id, userID string, clientMsgID *string, chat *models.Chat, duplicate bool) *Message {
+	data := MessageAckData{
+		ClientMsgID: clientMsgID,
+		ChannelID:   strconv.FormatUint(uint64(chat.ChannelID), 10),
+		MessageID:   chat.ID,
+		Duplicate:   duplicate,
+	}
+	if chat.UUID != nil {
+		data.UUID = *chat.UUID
+	}
+	return NewMessage(id, MessageTypeMessageAck, userID, toDataMap(data))
+}
+
+// NewMessageNackMessage turns an error event into a nack for the client message it rejects
+func NewMessageNackMessage(id, userID, clientMsgID, channelID string, errMsg *Message) *Message {
+	var errData ErrorData
+	if dataBytes, err := json.Marshal(errMsg.Data); err == nil {
+		json.Unmarshal(dataBytes, &errData)
+	}
+	return NewMessage(id, MessageTypeMessageNack, userID, toDataMap(MessageNackData{
+		ClientMsgID:  clientMsgID,
+		ChannelID:    channelID,
+		Reason:       errData.Code,
+		Message:      errData.Message,
+		RetryAfterMs: errData.RetryAfterMs,
+	}))
+}
+
+// NewMessageDeleteMessage is the tombstone telling channel members a message was deleted
+func NewMessageDeleteMessage(id, userID string, chat *models.Chat) *Message {
+	return NewMessage(id, MessageTypeMessageDelete, userID, toDataMap(MessageDeleteEventData{
+		ChannelID: strconv.FormatUint(uint64(chat.ChannelID), 10),
+		MessageID: chat.ID,
+	}))
+}
+
+// NewPinMessage announces a pin or unpin to channel members
+func NewPinMessage(id, userID string, event *models.PinEvent) *Message {
+	msgType := MessageTypeMessagePin
+	if event.PinnedAt == nil {
+		msgType = MessageTypeMessageUnpin
+	}
+	return NewMessage(id, msgType, userID, toDataMap(PinEventData{
+		ChannelID: strconv.FormatUint(uint64(event.ChannelID), 10),
+		MessageID: event.MessageID,
+		UserID:    strconv.FormatUint(uint64(event.UserID), 10),
+		PinnedAt:  event.PinnedAt,
+	}))
+}
+
+// NewReactionMessage creates a reaction update carrying the new count for the
+// emoji and the message's full reaction summary
+func NewReactionMessage(id, userID string, event *models.ReactionEvent) *Message {
+	return NewMessage(id, MessageTypeReaction, userID, toDataMap(ReactionEventData{
+		ChannelID: strconv.FormatUint(uint64(event.ChannelID), 10),
+		MessageID: event.MessageID,
+		UserID:    strconv.FormatUint(uint64(event.UserID), 10),
+		Emoji:     event.Emoji,
+		Op:        event.Op,
+		Count:     event.Count,
+		Reactions: reactionSummaryData(event.Reactions),
+	}))
+}
+
+func reactionSummaryData(summary []models.ReactionSummary) []ReactionSummaryData {
+	data := make([]ReactionSummaryData, len(summary))
+	for i, r := range summary {
+		userIDs := make([]string, len(r.UserIDs))
+		for j, id := range r.UserIDs {
+			userIDs[j] = strconv.FormatUint(uint64(id), 10)
 		}
+		data[i] = ReactionSummaryData{Emoji: r.Emoji, Count: r.Count, UserIDs: userIDs}
 	}
-	return NewMessage(id, MessageTypeChannelMessage, userID, dataMap)
+	return data
+}
+
+// NewTypingMessage tells channel members that a user started or stopped typing
+func NewTypingMessage(id, userID, channelID string, isTyping bool) *Message {
+	return NewMessage(id, MessageTypeTyping, userID, toDataMap(TypingEventData{
+		ChannelID: channelID,
+		UserID:    userID,
+		IsTyping:  isTyping,
+	}))
+}
+
+// NewReadMessage tells channel members how far a user has read
+func NewReadMessage(id, userID, channelID string, messageID uint) *Message {
+	return NewMessage(id, MessageTypeRead, userID, toDataMap(ReadEventData{
+		ChannelID: channelID,
+		UserID:    userID,
+		MessageID: messageID,
+	}))
 }
 
 // NewJoinChannelMessage creates a channel join message
@@ -144,6 +650,16 @@ func NewJoinChannelMessage(id, userID, channelID string) *Message {
 	})
 }
 
+// NewMemberJoinedMessage tells a channel that a user joined it outside the
+// WebSocket, e.g. through an invite link
+func NewMemberJoinedMessage(id, userID, channelID string) *Message {
+	return NewMessage(id, MessageTypeJoinChannel, userID, map[string]interface{}{
+		"channel_id": channelID,
+		"user_id":    userID,
+		"action":     "joined",
+	})
+}
+
 // NewLeaveChannelMessage creates a channel leave message
 func NewLeaveChannelMessage(id, userID, channelID string) *Message {
 	return NewMessage(id, MessageTypeLeaveChannel, userID, map[string]interface{}{
@@ -0,0 +1,195 @@
+package websocket
+
+import (
+	"chat-service/internal/models"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		frame   string
+		wantErr string // substring of the error, "" for success
+	}{
+		{"valid", `{"id":"1","type":"channel.join","data":{"channel_id":"5"}}`, ""},
+		{"data omitted", `{"id":"1","type":"channel.typing"}`, ""},
+		{"current version", `{"id":"1","version":1,"type":"channel.join","data":{}}`, ""},
+		{"unknown field", `{"id":"1","type":"channel.join","data":{},"extra":true}`, "unknown field"},
+		{"mistyped field", `{"id":1,"type":"channel.join","data":{}}`, "invalid message format"},
+		{"data not an object", `{"id":"1","type":"channel.join","data":"5"}`, "invalid message format"},
+		{"missing id", `{"type":"channel.join","data":{}}`, "message ID is required"},
+		{"unknown type", `{"id":"1","type":"channel.explode","data":{}}`, "invalid message type"},
+		{"unsupported version", `{"id":"1","version":2,"type":"channel.join","data":{}}`, ErrUnsupportedVersion.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := DecodeMessage([]byte(tt.frame))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if msg.Data == nil {
+					t.Error("Data is nil, want an empty map")
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecodeMessageUnsupportedVersionIs(t *testing.T) {
+	_, err := DecodeMessage([]byte(`{"id":"1","version":3,"type":"channel.join","data":{}}`))
+	if !errors.Is(err, ErrUnsupportedVersion) {
+		t.Fatalf("error = %v, want ErrUnsupportedVersion", err)
+	}
+}
+
+func TestChannelMessageDataValidate(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name    string
+		data    ChannelMessageData
+		wantErr bool
+	}{
+		{"valid", ChannelMessageData{ChannelID: "12", Text: str("hi")}, false},
+		{"empty channel", ChannelMessageData{ChannelID: ""}, true},
+		{"zero channel", ChannelMessageData{ChannelID: "0"}, true},
+		{"negative channel", ChannelMessageData{ChannelID: "-3"}, true},
+		{"non-numeric channel", ChannelMessageData{ChannelID: "general"}, true},
+		{"valid uuid", ChannelMessageData{ChannelID: "1", UUID: str("6f1c3c3e-8a59-4d57-9d3e-2f0b6a6d8c11")}, false},
+		{"invalid uuid", ChannelMessageData{ChannelID: "1", UUID: str("not-a-uuid")}, true},
+		{"client_msg_id at limit", ChannelMessageData{ChannelID: "1", ClientMsgID: str(strings.Repeat("a", maxClientMsgIDLength))}, false},
+		{"client_msg_id too long", ChannelMessageData{ChannelID: "1", ClientMsgID: str(strings.Repeat("a", maxClientMsgIDLength+1))}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.data.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecodeMessageIncompleteFrame(t *testing.T) {
+	frames := map[string]string{
+		"truncated":        `{"id":"1","type":"channel.join","data":{`,
+		"trailing bytes":   `{"id":"1","type":"channel.join","data":{}}xyz`,
+		"two documents":    `{"id":"1","type":"channel.join","data":{}}{"id":"2","type":"channel.join","data":{}}`,
+		"empty":            ``,
+		"not json":         `hello`,
+		"unterminated str": `{"id":"1`,
+	}
+
+	for name, frame := range frames {
+		t.Run(name, func(t *testing.T) {
+			if _, err := DecodeMessage([]byte(frame)); !errors.Is(err, ErrIncompleteFrame) {
+				t.Fatalf("error = %v, want ErrIncompleteFrame", err)
+			}
+		})
+	}
+}
+
+func TestMessageRoundTrip(t *testing.T) {
+	text, url, name, clientMsgID := "hello @bob", "https://cdn.example.com/a.png", "a.png", "c-1"
+	parentID, width := uint(3), 640
+	retryAfter := int64(1500)
+	at := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	chat := models.Chat{SenderID: 7, ChannelID: 5, Text: &text}
+	chat.UUID = &clientMsgID
+	chat.CreatedAt = at
+	chat.UpdatedAt = at
+
+	tests := []struct {
+		name    string
+		msgType MessageType
+		payload interface{}
+	}{
+		{"connect", MessageTypeConnect, ConnectData{ClientID: "c", Status: "connected", ResumeToken: "r"}},
+		{"disconnect", MessageTypeDisconnect, struct{}{}},
+		{"force logout", MessageTypeForceLogout, ForceLogoutData{Reason: "banned"}},
+		{"reconnect hint", MessageTypeReconnectHint, ReconnectHintData{Reason: "poor quality", Score: 20}},
+		{"resume request", MessageTypeResume, ResumeData{ResumeToken: "r"}},
+		{"resume confirmation", MessageTypeResume, ResumedData{Channels: []string{"5", "6"}}},
+		{"presence", MessageTypePresence, PresenceData{ChannelID: "5", UserID: "7", Status: PresenceAway}},
+		{"quota exceeded", MessageTypeQuotaExceeded, QuotaExceededData{Limit: 100, Used: 100, ResetsAt: at}},
+		{"presence subscribe", MessageTypePresenceSubscribe, PresenceSubscribeData{UserIDs: []string{"7", "8"}}},
+		{"presence subscribed", MessageTypePresenceSubscribe, PresenceSubscribedData{Statuses: map[string]PresenceStatus{"7": PresenceOnline}}},
+		{"presence unsubscribe", MessageTypePresenceUnsubscribe, PresenceSubscribeData{UserIDs: []string{"7"}}},
+		{"presence update", MessageTypePresenceUpdate, PresenceUpdateData{UserID: "7", Status: PresenceOffline}},
+		{"join", MessageTypeJoinChannel, ChannelJoinLeaveData{ChannelID: "5"}},
+		{"leave", MessageTypeLeaveChannel, ChannelJoinLeaveData{ChannelID: "5"}},
+		{"channel message", MessageTypeChannelMessage, ChannelMessageData{
+			ChannelID: "5", UUID: &clientMsgID, ClientMsgID: &clientMsgID, ParentID: &parentID, Text: &text, URL: &url, FileName: &name,
+			Attachments: []AttachmentData{{URL: url, MimeType: "image/png", Size: 1024, Width: &width}},
+		}},
+		{"channel message event", MessageTypeChannelMessage, chat},
+		{"edit", MessageTypeMessageEdit, MessageEditEventData{ChannelID: "5", MessageID: 9, Text: &text, EditedAt: at}},
+		{"delete", MessageTypeMessageDelete, MessageDeleteEventData{ChannelID: "5", MessageID: 9}},
+		{"pin", MessageTypeMessagePin, PinEventData{ChannelID: "5", MessageID: 9, UserID: "7", PinnedAt: &at}},
+		{"unpin", MessageTypeMessageUnpin, PinEventData{ChannelID: "5", MessageID: 9, UserID: "7"}},
+		{"ack", MessageTypeMessageAck, MessageAckData{ClientMsgID: &clientMsgID, ChannelID: "5", MessageID: 9, UUID: "u", Duplicate: true}},
+		{"nack", MessageTypeMessageNack, MessageNackData{ClientMsgID: clientMsgID, ChannelID: "5", Reason: "SLOW_MODE", Message: "slow down", RetryAfterMs: &retryAfter}},
+		{"direct message", MessageTypeDirectMessage, DirectMessageData{ReceiverID: "8", Text: &text, URL: &url, FileName: &name}},
+		{"reaction", MessageTypeReaction, ReactionData{MessageID: 9, Emoji: "👍", Op: "add"}},
+		{"reaction event", MessageTypeReaction, ReactionEventData{
+			ChannelID: "5", MessageID: 9, UserID: "7", Emoji: "👍", Op: "add", Count: 2,
+			Reactions: []ReactionSummaryData{{Emoji: "👍", Count: 2, UserIDs: []string{"7", "8"}}},
+		}},
+		{"typing", MessageTypeTyping, TypingData{ChannelID: "5", IsTyping: true}},
+		{"typing event", MessageTypeTyping, TypingEventData{ChannelID: "5", UserID: "7", IsTyping: true}},
+		{"read", MessageTypeRead, ReadData{ChannelID: "5", MessageID: 9}},
+		{"read event", MessageTypeRead, ReadEventData{ChannelID: "5", UserID: "7", MessageID: 9}},
+		{"error", MessageTypeError, ErrorData{Code: "RATE_LIMITED", Message: "too many messages", RetryAfterMs: &retryAfter}},
+	}
+
+	covered := make(map[MessageType]bool)
+	for _, tt := range tests {
+		covered[tt.msgType] = true
+		t.Run(tt.name, func(t *testing.T) {
+			sent := NewMessage("m-1", tt.msgType, "7", toDataMap(tt.payload))
+			sent.Version = EnvelopeVersion
+			frame, err := json.Marshal(sent)
+			if err != nil {
+				t.Fatalf("encode: %v", err)
+			}
+
+			got, err := DecodeMessage(frame)
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if got.ID != sent.ID || got.Type != sent.Type || got.Version != sent.Version || got.UserID != sent.UserID || got.Timestamp != sent.Timestamp {
+				t.Fatalf("envelope = %+v, want %+v", got, sent)
+			}
+
+			data, err := json.Marshal(got.Data)
+			if err != nil {
+				t.Fatalf("encode data: %v", err)
+			}
+			payload := reflect.New(reflect.TypeOf(tt.payload))
+			if err := decodeStrict(data, payload.Interface()); err != nil {
+				t.Fatalf("decode payload: %v", err)
+			}
+			if !reflect.DeepEqual(payload.Elem().Interface(), tt.payload) {
+				t.Fatalf("payload = %+v, want %+v", payload.Elem().Interface(), tt.payload)
+			}
+		})
+	}
+
+	for _, msgType := range GetAllMessageTypes() {
+		if !covered[msgType] {
+			t.Errorf("no round trip case for %s", msgType)
+		}
+	}
+}
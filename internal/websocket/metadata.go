@@ -0,0 +1,113 @@
+package websocket
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// ConnectionMetadata is a point-in-time snapshot of a client connection
+type ConnectionMetadata struct {
+	UserID         string         `json:"userId"`
+	RemoteAddr     string         `json:"remoteAddr"`
+	ConnectedAt    time.Time      `json:"connectedAt"`
+	LastActivity   time.Time      `json:"lastActivity"`
+	LastHeartbeat  time.Time      `json:"lastHeartbeat"`
+	HeartbeatCount int            `json:"heartbeatCount"`
+	Status         PresenceStatus `json:"status"`
+	Quality        QualityReport  `json:"quality"`
+	Channels       []string       `json:"channels"`
+}
+
+// ConnectionState describes where a user is known to be connected
+type ConnectionState struct {
+	UserID       string              `json:"userId"`
+	Local        bool                `json:"local"`        // connected to this instance
+	GlobalOnline bool                `json:"globalOnline"` // reported online in Redis by any instance
+	Connection   *ConnectionMetadata `json:"connection,omitempty"`
+}
+
+// metadata builds a snapshot of the client. Caller must hold h.mu.
+func (h *Hub) metadata(c *Client) *ConnectionMetadata {
+	return c.metadata(h, h.joinedChannels(c))
+}
+
+// joinedChannels returns the sorted channels the client joined. Caller must hold h.mu.
+func (h *Hub) joinedChannels(c *Client) []string {
+	channels := make([]string, 0)
+	for channelID, clients := range h.channels {
+		if clients[c.userID] == c {
+			channels = append(channels, channelID)
+		}
+	}
+	sort.Strings(channels)
+	return channels
+}
+
+// metadata builds a snapshot of the client given its sorted joined channels
+func (c *Client) metadata(h *Hub, channels []string) *ConnectionMetadata {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return &ConnectionMetadata{
+		UserID:         c.userID,
+		RemoteAddr:     c.conn.RemoteAddr().String(),
+		ConnectedAt:    c.connectedAt,
+		LastActivity:   c.lastActivity,
+		LastHeartbeat:  c.lastHeartbeat,
+		HeartbeatCount: c.heartbeatCount,
+		Status:         h.classify(c.lastActivity),
+		Quality:        c.quality.report(),
+		Channels:       channels,
+	}
+}
+
+// ListConnections returns a snapshot of every local connection, ordered by user
+// ID. When channelID is not empty only clients that joined that channel are
+// listed. The snapshot is copied under the hub lock and does not change with it.
+func (h *Hub) ListConnections(channelID string) []*ConnectionMetadata {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	// Index joined channels by user in one pass instead of once per client
+	joined := make(map[*Client][]string)
+	for id, clients := range h.channels {
+		for _, client := range clients {
+			joined[client] = append(joined[client], id)
+		}
+	}
+
+	connections := make([]*ConnectionMetadata, 0, len(h.clients))
+	for _, client := range h.clients {
+		if channelID != "" && h.channels[channelID][client.userID] != client {
+			continue
+		}
+		channels := joined[client]
+		if channels == nil {
+			channels = make([]string, 0)
+		}
+		sort.Strings(channels)
+		connections = append(connections, client.metadata(h, channels))
+	}
+
+	sort.Slice(connections, func(i, j int) bool {
+		return connections[i].UserID < connections[j].UserID
+	})
+	return connections
+}
+
+// GetConnectionState returns the local connection metadata for a user along with
+// whether any instance reports them online
+func (h *Hub) GetConnectionState(ctx context.Context, userID string) *ConnectionState {
+	state := &ConnectionState{UserID: userID}
+
+	h.mu.RLock()
+	if client, ok := h.clients[userID]; ok {
+		state.Local = true
+		state.Connection = h.metadata(client)
+	}
+	h.mu.RUnlock()
+
+	state.GlobalOnline = state.Local || h.isOnlineGlobally(ctx, userID)
+
+	return state
+}
@@ -0,0 +1,63 @@
+package websocket
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestListConnectionsSnapshot(t *testing.T) {
+	hub := newTestHub(t)
+	for _, userID := range []string{"2", "1"} {
+		client := dialTestClient(t, hub, userID)
+		hub.mu.Lock()
+		hub.clients[userID] = client
+		hub.mu.Unlock()
+	}
+	joins := map[string][]string{"1": {"20", "10"}, "2": {"10"}}
+	for userID, channels := range joins {
+		for _, channelID := range channels {
+			if err := hub.JoinChannel(userID, channelID); err != nil {
+				t.Fatalf("join channel: %v", err)
+			}
+		}
+	}
+
+	snapshot := hub.ListConnections("")
+	if len(snapshot) != 2 || snapshot[0].UserID != "1" || snapshot[1].UserID != "2" {
+		t.Fatalf("snapshot = %+v, want users 1 and 2 in order", snapshot)
+	}
+	if got := strings.Join(snapshot[0].Channels, ","); got != "10,20" {
+		t.Fatalf("user 1 channels = %s, want 10,20", got)
+	}
+	if snapshot[0].RemoteAddr == "" || snapshot[0].ConnectedAt.IsZero() {
+		t.Fatalf("snapshot is missing connection details: %+v", snapshot[0])
+	}
+	if filtered := hub.ListConnections("20"); len(filtered) != 1 || filtered[0].UserID != "1" {
+		t.Fatalf("connections in channel 20 = %+v, want only user 1", filtered)
+	}
+
+	// Later changes to the hub leave the snapshot as it was
+	if err := hub.LeaveChannel("1", "20"); err != nil {
+		t.Fatalf("leave channel: %v", err)
+	}
+	if err := hub.JoinChannel("2", "30"); err != nil {
+		t.Fatalf("join channel: %v", err)
+	}
+	hub.mu.Lock()
+	hub.removeClient(hub.clients["1"])
+	hub.mu.Unlock()
+
+	if got := strings.Join(snapshot[0].Channels, ","); got != "10,20" {
+		t.Fatalf("user 1 channels in the old snapshot = %s, want 10,20", got)
+	}
+	if got := strings.Join(snapshot[1].Channels, ","); got != "10" {
+		t.Fatalf("user 2 channels in the old snapshot = %s, want 10", got)
+	}
+
+	// Nor does changing the snapshot reach the hub
+	snapshot[1].Channels[0] = "99"
+	current := hub.ListConnections("")
+	if len(current) != 1 || strings.Join(current[0].Channels, ",") != "10,30" {
+		t.Fatalf("current connections = %+v, want user 2 in 10,30", current)
+	}
+}
@@ -0,0 +1,201 @@
+package websocket
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Upper bounds in seconds for the broadcast duration histogram
+var broadcastDurationBuckets = []float64{0.0005, 0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1}
+
+// hubMetrics holds cumulative counters for the Prometheus endpoint. Gauges such as
+// active connections are read from the hub at scrape time instead.
+type hubMetrics struct {
+	broadcasts        atomic.Int64
+	deliveriesSent    atomic.Int64
+	deliveriesDropped atomic.Int64
+	peakConnections   atomic.Int64
+	compressedFrames  atomic.Int64
+	compressedBytes   atomic.Int64 // size before compression
+
+	mu            sync.Mutex
+	durationCount []uint64 // per bucket, not cumulative; the last entry is +Inf
+	durationSum   float64
+	errors        map[string]uint64 // error_type -> count
+
+	// Sampled compression sizes, see observeCompressed
+	sampledRawBytes      int64
+	sampledDeflatedBytes int64
+}
+
+func newHubMetrics() *hubMetrics {
+	return &hubMetrics{
+		durationCount: make([]uint64, len(broadcastDurationBuckets)+1),
+		errors:        make(map[string]uint64),
+	}
+}
+
+func (m *hubMetrics) observeBroadcast(elapsed time.Duration, sent, dropped int) {
+	m.broadcasts.Add(1)
+	m.deliveriesSent.Add(int64(sent))
+	m.deliveriesDropped.Add(int64(dropped))
+
+	seconds := elapsed.Seconds()
+	i := sort.SearchFloat64s(broadcastDurationBuckets, seconds)
+	m.mu.Lock()
+	m.durationCount[i]++
+	m.durationSum += seconds
+	m.mu.Unlock()
+}
+
+func (m *hubMetrics) observeConnections(n int) {
+	for {
+		peak := m.peakConnections.Load()
+		if int64(n) <= peak || m.peakConnections.CompareAndSwap(peak, int64(n)) {
+			return
+		}
+	}
+}
+
+func (m *hubMetrics) countError(errorType string) {
+	m.mu.Lock()
+	m.errors[errorType]++
+	m.mu.Unlock()
+}
+
+// recordError counts an error for metrics, keeps it in the error history, feeds
+// it to load shedding and forwards it to the monitoring webhook
+func (h *Hub) recordError(source string) {
+	h.metrics.countError(source)
+	h.errorHistory.add(source)
+	h.health.RecordError(source)
+	if h.errorNotifier != nil {
+		h.errorNotifier.NotifyError(source, h.instanceID)
+	}
+}
+
+// WritePrometheus writes the hub's metrics in the Prometheus text exposition format
+func (h *Hub) WritePrometheus(w io.Writer) error {
+	h.mu.RLock()
+	activeConnections := len(h.clients)
+	channelClients := make(map[string]int, len(h.channels))
+	for channelID, clients := range h.channels {
+		channelClients[channelID] = len(clients)
+	}
+	h.mu.RUnlock()
+
+	m := h.metrics
+	m.mu.Lock()
+	durationCount := append([]uint64(nil), m.durationCount...)
+	durationSum := m.durationSum
+	sampledRaw, sampledDeflated := m.sampledRawBytes, m.sampledDeflatedBytes
+	errorCounts := make(map[string]uint64, len(m.errors))
+	for errorType, count := range m.errors {
+		errorCounts[errorType] = count
+	}
+	m.mu.Unlock()
+
+	p := &promWriter{w: w}
+
+	p.header("chat_ws_active_connections", "gauge", "WebSocket clients connected to this instance.")
+	p.sample("chat_ws_active_connections", "", float64(activeConnections))
+
+	p.header("chat_ws_peak_connections", "gauge", "Most WebSocket clients connected at once since start.")
+	p.sample("chat_ws_peak_connections", "", float64(m.peakConnections.Load()))
+
+	p.header("chat_ws_channel_connections", "gauge", "Clients on this instance that joined each channel.")
+	for _, channelID := range sortedKeys(channelClients) {
+		p.sample("chat_ws_channel_connections", label("channel", channelID), float64(channelClients[channelID]))
+	}
+
+	p.header("chat_ws_broadcasts_total", "counter", "Messages fanned out to a channel.")
+	p.sample("chat_ws_broadcasts_total", "", float64(m.broadcasts.Load()))
+
+	p.header("chat_ws_broadcast_deliveries_total", "counter", "Per-client deliveries attempted by broadcasts.")
+	p.sample("chat_ws_broadcast_deliveries_total", label("result", "sent"), float64(m.deliveriesSent.Load()))
+	p.sample("chat_ws_broadcast_deliveries_total", label("result", "dropped"), float64(m.deliveriesDropped.Load()))
+
+	p.header("chat_ws_broadcast_duration_seconds", "histogram", "Time to fan a message out to a channel's local clients.")
+	var cumulative uint64
+	for i, bound := range broadcastDurationBuckets {
+		cumulative += durationCount[i]
+		p.sample("chat_ws_broadcast_duration_seconds_bucket", label("le", strconv.FormatFloat(bound, 'g', -1, 64)), float64(cumulative))
+	}
+	cumulative += durationCount[len(broadcastDurationBuckets)]
+	p.sample("chat_ws_broadcast_duration_seconds_bucket", label("le", "+Inf"), float64(cumulative))
+	p.sample("chat_ws_broadcast_duration_seconds_sum", "", durationSum)
+	p.sample("chat_ws_broadcast_duration_seconds_count", "", float64(cumulative))
+
+	p.header("chat_ws_slow_writes_total", "counter", "Client writes slower than the slow write threshold.")
+	p.sample("chat_ws_slow_writes_total", "", float64(h.SlowWrites()))
+
+	p.header("chat_ws_compressed_frames_total", "counter", "Outbound frames sent with permessage-deflate.")
+	p.sample("chat_ws_compressed_frames_total", "", float64(m.compressedFrames.Load()))
+
+	p.header("chat_ws_compressed_bytes_total", "counter", "Uncompressed size of the frames sent with permessage-deflate.")
+	p.sample("chat_ws_compressed_bytes_total", "", float64(m.compressedBytes.Load()))
+
+	if sampledRaw > 0 {
+		p.header("chat_ws_compression_ratio", "gauge", "Compressed to uncompressed size, estimated from sampled frames.")
+		p.sample("chat_ws_compression_ratio", "", float64(sampledDeflated)/float64(sampledRaw))
+	}
+
+	p.header("chat_ws_errors_total", "counter", "Hub errors by type.")
+	for _, errorType := range sortedKeys(errorCounts) {
+		p.sample("chat_ws_errors_total", label("error_type", errorType), float64(errorCounts[errorType]))
+	}
+
+	healthy := 0.0
+	if h.Health() == HealthHealthy {
+		healthy = 1
+	}
+	p.header("chat_ws_healthy", "gauge", "1 when the hub is accepting new connections, 0 while shedding load.")
+	p.sample("chat_ws_healthy", "", healthy)
+
+	redisOpen := 0.0
+	if h.RedisBreakerState() != BreakerClosed {
+		redisOpen = 1
+	}
+	p.header("chat_ws_redis_circuit_open", "gauge", "1 while the Redis circuit breaker is open or half-open.")
+	p.sample("chat_ws_redis_circuit_open", "", redisOpen)
+
+	return p.err
+}
+
+// promWriter writes exposition lines and keeps the first write error
+type promWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (p *promWriter) header(name, metricType, help string) {
+	if p.err != nil {
+		return
+	}
+	_, p.err = fmt.Fprintf(p.w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+func (p *promWriter) sample(name, labels string, value float64) {
+	if p.err != nil {
+		return
+	}
+	_, p.err = fmt.Fprintf(p.w, "%s%s %s\n", name, labels, strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+func label(name, value string) string {
+	return "{" + name + "=" + strconv.Quote(value) + "}"
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,58 @@
+package websocket
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// scrapeMetrics fetches the hub's metrics over HTTP the way Prometheus does
+func scrapeMetrics(t *testing.T, hub *Hub) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := hub.WritePrometheus(w); err != nil {
+			t.Errorf("write metrics: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("scrape: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read metrics: %v", err)
+	}
+	return string(body)
+}
+
+func TestMetricsScrape(t *testing.T) {
+	hub := newTestHub(t)
+	hub.redisService, _ = newTestRedis(t)
+	for _, userID := range []string{"1", "2"} {
+		connectTestClient(hub, userID)
+		if err := hub.JoinChannel(userID, "10"); err != nil {
+			t.Fatalf("join channel: %v", err)
+		}
+	}
+	hub.BroadcastToChannel("10", NewErrorMessage("m1", "", "TEST", "hello"))
+
+	body := scrapeMetrics(t, hub)
+	for _, want := range []string{
+		"# TYPE chat_ws_active_connections gauge",
+		"chat_ws_active_connections 2\n",
+		"# TYPE chat_ws_broadcast_duration_seconds histogram",
+		`chat_ws_broadcast_duration_seconds_bucket{le="+Inf"} 1` + "\n",
+		"chat_ws_broadcast_duration_seconds_count 1\n",
+		`chat_ws_broadcast_deliveries_total{result="sent"} 2` + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %q\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package websocket
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// broadcastChannelMessage delivers a new channel message to the channel's clients.
+// Recipients who muted the channel get the same message with a "muted" hint so
+// their client can skip the notification.
+func (h *Hub) broadcastChannelMessage(ctx context.Context, channelID uint, channelKey string, message *Message) {
+	_, span := h.tracer.Start(ctx, "websocket.broadcast", trace.WithAttributes(attribute.String("ws.channel_id", channelKey)))
+	defer span.End()
+
+	h.mu.RLock()
+	clients := make([]*Client, 0, len(h.channels[channelKey]))
+	for _, client := range h.channels[channelKey] {
+		clients = append(clients, client)
+	}
+	h.mu.RUnlock()
+
+	if len(clients) == 0 {
+		setDeliveryAttributes(span, 0, 0)
+		return
+	}
+
+	mutedIDs, err := h.channelRepo.MutedUserIDs(channelID, time.Now())
+	if err != nil {
+		// Deliver without hints rather than not at all
+		h.logger.Warn("Failed to load channel mutes", "channelID", channelID, "error", err)
+	}
+	if len(mutedIDs) == 0 {
+		setDeliveryAttributes(span, len(clients), h.fanOut(clients, message))
+		return
+	}
+
+	muted := make(map[string]bool, len(mutedIDs))
+	for _, id := range mutedIDs {
+		muted[strconv.FormatUint(uint64(id), 10)] = true
+	}
+
+	mutedData := make(map[string]interface{}, len(message.Data)+1)
+	for k, v := range message.Data {
+		mutedData[k] = v
+	}
+	mutedData["muted"] = true
+	mutedMessage := *message
+	mutedMessage.Data = mutedData
+
+	start := time.Now()
+	messageBytes := h.messageToBytes(message)
+	mutedBytes := h.messageToBytes(&mutedMessage)
+	sent := 0
+	for _, client := range clients {
+		data := messageBytes
+		if muted[client.userID] {
+			data = mutedBytes
+		}
+		if h.sendBytes(client, data) {
+			sent++
+		}
+	}
+	h.metrics.observeBroadcast(time.Since(start), sent, len(clients)-sent)
+	setDeliveryAttributes(span, len(clients), sent)
+}
@@ -0,0 +1,85 @@
+package websocket
+
+import (
+	"chat-service/internal/models"
+	"chat-service/internal/repositories/postgres"
+	"chat-service/internal/services"
+	"encoding/json"
+	"strconv"
+	"testing"
+)
+
+// readAck returns the ack among the frames queued for the client
+func readAck(t *testing.T, hub *Hub, client *Client) MessageAckData {
+	t.Helper()
+	for len(client.send) > 0 {
+		var msg Message
+		if err := json.Unmarshal(<-client.send, &msg); err != nil {
+			t.Fatalf("decode frame: %v", err)
+		}
+		if msg.Type == MessageTypeError {
+			t.Fatalf("message rejected: %v", msg.Data)
+		}
+		if msg.Type != MessageTypeMessageAck {
+			continue
+		}
+		var ack MessageAckData
+		if err := hub.mapToStruct(msg.Data, &ack); err != nil {
+			t.Fatalf("decode ack: %v", err)
+		}
+		return ack
+	}
+	t.Fatal("no ack queued for the sender")
+	return MessageAckData{}
+}
+
+func TestChannelMessageSurvivesReconnect(t *testing.T) {
+	db := newTestDB(t)
+	chatRepo := postgres.NewChatRepository(db)
+	channelRepo := postgres.NewChannelRepository(db)
+	userRepo := postgres.NewUserRepository(db)
+
+	hub := newTestHub(t)
+	hub.chatRepo = chatRepo
+	hub.channelRepo = channelRepo
+	hub.chatService = services.NewChatService(chatRepo, channelRepo, postgres.NewReactionRepository(db), userRepo, 0, 0)
+
+	user := createTestUser(t, db)
+	channel := &models.Channel{Name: "persist", OwnerID: user.ID, Type: models.ChannelTypeGroup}
+	if err := db.Create(channel).Error; err != nil {
+		t.Fatalf("create channel: %v", err)
+	}
+	userID := strconv.FormatUint(uint64(user.ID), 10)
+	channelID := strconv.FormatUint(uint64(channel.ID), 10)
+
+	client := connectTestClient(hub, userID)
+	if err := hub.JoinChannel(userID, channelID); err != nil {
+		t.Fatalf("join: %v", err)
+	}
+	hub.handleChannelMessage(client, &Message{ID: "m1", Type: MessageTypeChannelMessage, Data: map[string]interface{}{
+		"channel_id": channelID,
+		"text":       "still here",
+	}})
+	ack := readAck(t, hub, client)
+	if ack.MessageID == 0 {
+		t.Fatal("ack carries no stored message ID")
+	}
+
+	// A new connection starts with no state; the message comes from the database
+	hub.disconnectLocal(userID, "reconnect")
+	connectTestClient(hub, userID)
+
+	history, err := chatRepo.ListChannelPage(channel.ID, nil, 10)
+	if err != nil {
+		t.Fatalf("load history: %v", err)
+	}
+	if len(history) != 1 || history[0].ID != ack.MessageID {
+		t.Fatalf("history = %+v, want the acked message %d", history, ack.MessageID)
+	}
+	if history[0].Text == nil || *history[0].Text != "still here" {
+		t.Fatalf("stored text = %v, want %q", history[0].Text, "still here")
+	}
+	if history[0].UUID == nil || *history[0].UUID != ack.UUID {
+		t.Fatalf("stored UUID = %v, want the broadcast UUID %q", history[0].UUID, ack.UUID)
+	}
+}
@@ -0,0 +1,134 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// How often connected clients are re-classified for away status
+const presenceCheckInterval = 15 * time.Second
+
+// Presence keys expire after presenceKeyTTL unless refreshed, which happens every
+// presenceHeartbeatInterval while the user stays connected
+const (
+	presenceKeyTTL            = 90 * time.Second
+	presenceHeartbeatInterval = 30 * time.Second
+)
+
+// PresenceStatus is the user's presence as seen by other channel members
+type PresenceStatus string
+
+const (
+	PresenceOnline  PresenceStatus = "online"
+	PresenceAway    PresenceStatus = "away"
+	PresenceOffline PresenceStatus = "offline"
+)
+
+// classify derives the presence of a connected client from its last activity
+func (h *Hub) classify(lastActivity time.Time) PresenceStatus {
+	if h.config.AwayThreshold > 0 && time.Since(lastActivity) > h.config.AwayThreshold {
+		return PresenceAway
+	}
+	return PresenceOnline
+}
+
+// checkPresence announces users who crossed the away threshold in either direction
+func (h *Hub) checkPresence() {
+	if h.config.AwayThreshold <= 0 {
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for userID, client := range h.clients {
+		client.mu.Lock()
+		status := h.classify(client.lastActivity)
+		changed := status != client.status
+		client.status = status
+		client.mu.Unlock()
+
+		if !changed {
+			continue
+		}
+
+		h.logger.Debug("User presence changed", "userID", userID, "status", status)
+		for channelID, clients := range h.channels {
+			if _, ok := clients[userID]; ok {
+				h.notifyPresence(channelID, userID, status)
+			}
+		}
+	}
+}
+
+// channelRoster returns the presence of every connected member of a channel
+func (h *Hub) channelRoster(channelID string) map[string]PresenceStatus {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	roster := make(map[string]PresenceStatus, len(h.channels[channelID]))
+	for userID, client := range h.channels[channelID] {
+		client.mu.Lock()
+		roster[userID] = h.classify(client.lastActivity)
+		client.mu.Unlock()
+	}
+	return roster
+}
+
+// notifyPresence sends a presence update to the other members of a channel.
+// Caller must hold h.mu.
+func (h *Hub) notifyPresence(channelID, userID string, status PresenceStatus) {
+	clients := h.channels[channelID]
+	if clients == nil {
+		return
+	}
+
+	messageBytes := h.messageToBytes(NewPresenceMessage(uuid.New().String(), userID, channelID, status))
+	for clientUserID, client := range clients {
+		if clientUserID == userID {
+			continue
+		}
+		h.sendBytes(client, messageBytes)
+	}
+}
+
+// runPresenceHeartbeat keeps the presence keys of locally connected users alive
+func (h *Hub) runPresenceHeartbeat() {
+	ticker := time.NewTicker(presenceHeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-h.ctx.Done():
+			return
+		case <-ticker.C:
+			h.refreshPresence()
+		}
+	}
+}
+
+func (h *Hub) refreshPresence() {
+	h.mu.RLock()
+	userIDs := make([]string, 0, len(h.clients))
+	for userID := range h.clients {
+		userIDs = append(userIDs, userID)
+	}
+	h.mu.RUnlock()
+
+	if len(userIDs) == 0 {
+		return
+	}
+
+	err := h.callRedis(h.ctx, 5*redisOpTimeout, func(ctx context.Context) error {
+		return h.redisService.RefreshPresence(ctx, h.instanceID, userIDs, presenceKeyTTL)
+	})
+	if errors.Is(err, errRedisCircuitOpen) {
+		return
+	}
+	if err != nil {
+		h.recordError("redis")
+		h.logger.Warn("Failed to refresh presence keys", "count", len(userIDs), "error", err)
+	}
+}
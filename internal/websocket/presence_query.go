@@ -0,0 +1,131 @@
+package websocket
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// UserPresence is a user's cluster-wide presence
+type UserPresence struct {
+	UserID       string         `json:"userId"`
+	Online       bool           `json:"online"`
+	Status       PresenceStatus `json:"status,omitempty"`       // online or away, only known for local connections
+	LastActivity *time.Time     `json:"lastActivity,omitempty"` // last activity, or last status change when not local
+	LastSeen     *time.Time     `json:"lastSeen,omitempty"`     // when an offline user was last connected
+	Channels     []string       `json:"channels"`               // joined channels on this instance
+}
+
+// ChannelPresence lists the online members of a channel
+type ChannelPresence struct {
+	ChannelID string   `json:"channelId"`
+	Online    []string `json:"online"`
+}
+
+// GetUserPresence answers from the local connection when the user is connected
+// here and from Redis otherwise. Offline users also get their last seen time
+// from the database. Unknown users are simply offline.
+func (h *Hub) GetUserPresence(ctx context.Context, userID string) *UserPresence {
+	h.mu.RLock()
+	client, local := h.clients[userID]
+	var meta *ConnectionMetadata
+	if local {
+		meta = h.metadata(client)
+	}
+	h.mu.RUnlock()
+
+	if local {
+		return &UserPresence{
+			UserID:       userID,
+			Online:       true,
+			Status:       meta.Status,
+			LastActivity: &meta.LastActivity,
+			Channels:     meta.Channels,
+		}
+	}
+
+	presence := &UserPresence{
+		UserID:   userID,
+		Online:   h.isOnlineGlobally(ctx, userID),
+		Channels: []string{},
+	}
+	lastSeen, err := h.redisService.GetUserLastSeen(ctx, userID)
+	if err != nil {
+		h.logger.Warn("Failed to read last seen", "userID", userID, "error", err)
+	} else if !lastSeen.IsZero() {
+		presence.LastActivity = &lastSeen
+	}
+	if !presence.Online {
+		presence.LastSeen = h.lastSeen(userID)
+	}
+	return presence
+}
+
+// GetChannelPresence returns which of the given channel members are online on any instance
+func (h *Hub) GetChannelPresence(ctx context.Context, channelID string, memberIDs []string) *ChannelPresence {
+	presence := &ChannelPresence{ChannelID: channelID, Online: []string{}}
+
+	remote := make([]string, 0, len(memberIDs))
+	h.mu.RLock()
+	for _, userID := range memberIDs {
+		if _, ok := h.clients[userID]; ok {
+			presence.Online = append(presence.Online, userID)
+		} else {
+			remote = append(remote, userID)
+		}
+	}
+	h.mu.RUnlock()
+
+	if h.globalPresence != nil {
+		unresolved := remote[:0]
+		for _, userID := range remote {
+			online, ok := h.globalPresence.lookup(userID)
+			switch {
+			case !ok:
+				unresolved = append(unresolved, userID)
+			case online:
+				presence.Online = append(presence.Online, userID)
+			}
+		}
+		remote = unresolved
+	}
+
+	if len(remote) > 0 {
+		online, err := h.redisService.AreUsersOnline(ctx, remote)
+		if err != nil {
+			h.logger.Warn("Failed to read channel presence", "channelID", channelID, "error", err)
+		}
+		for i, isOnline := range online {
+			if isOnline {
+				presence.Online = append(presence.Online, remote[i])
+			}
+		}
+	}
+
+	sort.Strings(presence.Online)
+	return presence
+}
+
+// ChannelSize is the number of clients on this instance that joined a channel
+type ChannelSize struct {
+	ChannelID string `json:"channelId"`
+	Clients   int    `json:"clients"`
+}
+
+// ChannelSizes returns the local client count of every joined channel, largest first
+func (h *Hub) ChannelSizes() []ChannelSize {
+	h.mu.RLock()
+	sizes := make([]ChannelSize, 0, len(h.channels))
+	for channelID, clients := range h.channels {
+		sizes = append(sizes, ChannelSize{ChannelID: channelID, Clients: len(clients)})
+	}
+	h.mu.RUnlock()
+
+	sort.Slice(sizes, func(i, j int) bool {
+		if sizes[i].Clients != sizes[j].Clients {
+			return sizes[i].Clients > sizes[j].Clients
+		}
+		return sizes[i].ChannelID < sizes[j].ChannelID
+	})
+	return sizes
+}
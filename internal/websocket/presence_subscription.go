@@ -0,0 +1,188 @@
+package websocket
+
+import (
+	"chat-service/internal/services"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Most users one connection may watch through presence subscriptions
+const maxPresenceSubscriptions = 500
+
+// presenceSubscriptions indexes which clients watch which users' presence. It is
+// written from the hub loop and read by the presence update listener.
+type presenceSubscriptions struct {
+	mu       sync.RWMutex
+	byTarget map[string]map[*Client]struct{} // watched user ID -> subscribers
+	byClient map[*Client]map[string]struct{} // subscriber -> watched user IDs
+}
+
+func newPresenceSubscriptions() *presenceSubscriptions {
+	return &presenceSubscriptions{
+		byTarget: make(map[string]map[*Client]struct{}),
+		byClient: make(map[*Client]map[string]struct{}),
+	}
+}
+
+// add subscribes the client to the users. It fails without changes if the
+// client would exceed maxPresenceSubscriptions.
+func (s *presenceSubscriptions) add(c *Client, userIDs []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	targets := s.byClient[c]
+	added := 0
+	for _, id := range userIDs {
+		if _, exists := targets[id]; !exists {
+			added++
+		}
+	}
+	if len(targets)+added > maxPresenceSubscriptions {
+		return fmt.Errorf("at most %d presence subscriptions per connection", maxPresenceSubscriptions)
+	}
+
+	if targets == nil {
+		targets = make(map[string]struct{}, len(userIDs))
+		s.byClient[c] = targets
+	}
+	for _, id := range userIDs {
+		targets[id] = struct{}{}
+		subscribers := s.byTarget[id]
+		if subscribers == nil {
+			subscribers = make(map[*Client]struct{})
+			s.byTarget[id] = subscribers
+		}
+		subscribers[c] = struct{}{}
+	}
+	return nil
+}
+
+// remove unsubscribes the client from the users
+func (s *presenceSubscriptions) remove(c *Client, userIDs []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeLocked(c, userIDs)
+}
+
+// removeClient drops every subscription of the client
+func (s *presenceSubscriptions) removeClient(c *Client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userIDs := make([]string, 0, len(s.byClient[c]))
+	for id := range s.byClient[c] {
+		userIDs = append(userIDs, id)
+	}
+	s.removeLocked(c, userIDs)
+}
+
+func (s *presenceSubscriptions) removeLocked(c *Client, userIDs []string) {
+	targets := s.byClient[c]
+	for _, id := range userIDs {
+		delete(targets, id)
+		if subscribers := s.byTarget[id]; subscribers != nil {
+			delete(subscribers, c)
+			if len(subscribers) == 0 {
+				delete(s.byTarget, id)
+			}
+		}
+	}
+	if len(targets) == 0 {
+		delete(s.byClient, c)
+	}
+}
+
+// subscribers returns the clients watching the user
+func (s *presenceSubscriptions) subscribers(userID string) []*Client {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	clients := make([]*Client, 0, len(s.byTarget[userID]))
+	for c := range s.byTarget[userID] {
+		clients = append(clients, c)
+	}
+	return clients
+}
+
+func (h *Hub) handlePresenceSubscribe(client *Client, message *Message) {
+	var data PresenceSubscribeData
+	if err := h.mapToStruct(message.Data, &data); err != nil {
+		h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "INVALID_DATA", "Invalid presence subscription data"))
+		return
+	}
+	if len(data.UserIDs) == 0 {
+		h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "INVALID_DATA", "user_ids is required"))
+		return
+	}
+	if err := h.presenceSubs.add(client, data.UserIDs); err != nil {
+		h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "TOO_MANY_SUBSCRIPTIONS", err.Error()))
+		return
+	}
+
+	// Reply with the current status so the client does not wait for the next change
+	var online []bool
+	err := h.callRedis(h.ctx, redisOpTimeout, func(ctx context.Context) error {
+		var err error
+		online, err = h.redisService.AreUsersOnline(ctx, data.UserIDs)
+		return err
+	})
+	if err != nil {
+		h.logger.Warn("Failed to load presence for subscription", "userID", client.userID, "error", err)
+	}
+	statuses := make(map[string]PresenceStatus, len(data.UserIDs))
+	for i, id := range data.UserIDs {
+		statuses[id] = PresenceOffline
+		if i < len(online) && online[i] {
+			statuses[id] = PresenceOnline
+		}
+	}
+	h.sendToClient(client, NewMessage(message.ID, MessageTypePresenceSubscribe, client.userID, toDataMap(PresenceSubscribedData{Statuses: statuses})))
+}
+
+func (h *Hub) handlePresenceUnsubscribe(client *Client, message *Message) {
+	var data PresenceSubscribeData
+	if err := h.mapToStruct(message.Data, &data); err != nil {
+		h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "INVALID_DATA", "Invalid presence subscription data"))
+		return
+	}
+	if len(data.UserIDs) == 0 {
+		h.presenceSubs.removeClient(client)
+		return
+	}
+	h.presenceSubs.remove(client, data.UserIDs)
+}
+
+// listenPresenceUpdates forwards presence changes published by any instance to
+// the local clients subscribed to those users
+func (h *Hub) listenPresenceUpdates() {
+	pubsub := h.redisService.Subscribe(h.ctx, services.PresenceUpdatesChannel)
+	defer pubsub.Close()
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-h.ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			var update services.PresenceUpdate
+			if err := json.Unmarshal([]byte(msg.Payload), &update); err != nil {
+				h.logger.Warn("Ignoring malformed presence update", "error", err)
+				continue
+			}
+			status := PresenceStatus(update.Status)
+			for _, userID := range update.UserIDs {
+				for _, client := range h.presenceSubs.subscribers(userID) {
+					h.sendToClient(client, NewPresenceUpdateMessage(uuid.New().String(), client.userID, userID, status))
+				}
+			}
+		}
+	}
+}
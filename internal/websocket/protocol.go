@@ -0,0 +1,192 @@
+package websocket
+
+import (
+	"chat-service/internal/models"
+	"reflect"
+	"strings"
+)
+
+// ProtocolVersion is bumped whenever actions or events change incompatibly
+const ProtocolVersion = "1.0"
+
+// clientAction is an entry in the registry of messages clients may send.
+// The hub dispatches through this registry, so the published protocol
+// description cannot drift from what the server accepts.
+type clientAction struct {
+	Type        MessageType
+	Description string
+	Data        interface{} // zero value of the payload struct
+	handle      func(h *Hub, c *Client, m *Message)
+}
+
+var clientActions = []clientAction{
+	{MessageTypeJoinChannel, "Join a channel to receive its messages", ChannelJoinLeaveData{}, (*Hub).handleJoinChannel},
+	{MessageTypeLeaveChannel, "Stop receiving a channel's messages", ChannelJoinLeaveData{}, (*Hub).handleLeaveChannel},
+	{MessageTypeChannelMessage, "Send a message to a joined channel", ChannelMessageData{}, (*Hub).handleChannelMessage},
+	{MessageTypeDirectMessage, "Send a 1:1 message to a user; it is echoed back to the sender once saved", DirectMessageData{}, (*Hub).handleDirectMessage},
+	{MessageTypeReaction, "Add or remove an emoji reaction on a message", ReactionData{}, (*Hub).handleReaction},
+	{MessageTypeTyping, "Signal that you started or stopped typing in a joined channel (not persisted)", TypingData{}, (*Hub).handleTyping},
+	{MessageTypeRead, "Mark a channel read up to a message; the pointer never moves backwards", ReadData{}, (*Hub).handleRead},
+	{MessageTypePresenceSubscribe, "Receive online/offline changes for the listed users", PresenceSubscribeData{}, (*Hub).handlePresenceSubscribe},
+	{MessageTypeResume, "Rejoin the channels of a previous connection with the resume_token it was given on connect; if the token is rejected, join channels again one by one", ResumeData{}, (*Hub).handleResume},
+	{MessageTypePresenceUnsubscribe, "Stop presence changes for the listed users, or for everyone when the list is empty", PresenceSubscribeData{}, (*Hub).handlePresenceUnsubscribe},
+}
+
+var clientActionsByType = indexClientActions(clientActions)
+
+// readPumpActions are handled on the sender's read goroutine instead of the hub
+// loop. Storing a message takes several database and Redis round trips, which
+// would otherwise stall every connection; one connection's messages stay in order.
+var readPumpActions = map[MessageType]bool{
+	MessageTypeChannelMessage: true,
+	MessageTypeDirectMessage:  true,
+}
+
+func indexClientActions(actions []clientAction) map[MessageType]clientAction {
+	index := make(map[MessageType]clientAction, len(actions))
+	for _, action := range actions {
+		index[action.Type] = action
+	}
+	return index
+}
+
+// serverEvent describes a message the server pushes to clients
+type serverEvent struct {
+	Type        MessageType
+	Description string
+	Data        interface{}
+}
+
+var serverEvents = []serverEvent{
+	{MessageTypeConnect, "Connection accepted", ConnectData{}},
+	{MessageTypeForceLogout, "Session revoked or the user was disconnected by an admin; the connection is closed after this frame. This is the only forced-logout frame; there is no separate force-logout or disconnected type", ForceLogoutData{}},
+	{MessageTypeReconnectHint, "Connection quality is poor; reconnecting may help", ReconnectHintData{}},
+	{MessageTypeResume, "Resume confirmation listing the rejoined channels, sent after their join confirmations", ResumedData{}},
+	{MessageTypePresence, "A channel member's presence changed", PresenceData{}},
+	{MessageTypeQuotaExceeded, "Your message was not sent because the daily message quota is used up", QuotaExceededData{}},
+	{MessageTypePresenceSubscribe, "Subscription confirmation with the current status of each user", PresenceSubscribedData{}},
+	{MessageTypePresenceUpdate, "A subscribed user went online or offline", PresenceUpdateData{}},
+	{MessageTypeJoinChannel, "Join confirmation (with a members roster) or another member joined", ChannelJoinLeaveData{}},
+	{MessageTypeLeaveChannel, "Leave confirmation or another member left", ChannelJoinLeaveData{}},
+	{MessageTypeChannelMessage, "A message was posted to a joined channel", models.Chat{}},
+	{MessageTypeMessageEdit, "A message's text was edited", MessageEditEventData{}},
+	{MessageTypeMessageDelete, "A message was deleted; clients should show a placeholder", MessageDeleteEventData{}},
+	{MessageTypeMessagePin, "A message was pinned in the channel", PinEventData{}},
+	{MessageTypeMessageUnpin, "A message was unpinned from the channel", PinEventData{}},
+	{MessageTypeMessageAck, "Sent only to the sender once its channel message was accepted", MessageAckData{}},
+	{MessageTypeMessageNack, "Sent only to the sender instead of an error when a channel message carrying client_msg_id was rejected", MessageNackData{}},
+	{MessageTypeDirectMessage, "A direct message to or from you", models.Chat{}},
+	{MessageTypeReaction, "A reaction was added or removed", ReactionEventData{}},
+	{MessageTypeTyping, "Another member started or stopped typing", TypingEventData{}},
+	{MessageTypeRead, "A member's read pointer advanced", ReadEventData{}},
+	{MessageTypeError, "A request failed", ErrorData{}},
+}
+
+// FieldSpec describes one field of a message payload
+type FieldSpec struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+}
+
+// MessageSpec describes a message type and its payload
+type MessageSpec struct {
+	Type        MessageType `json:"type"`
+	Description string      `json:"description"`
+	Fields      []FieldSpec `json:"fields"`
+}
+
+// ProtocolDescription is the machine-readable WebSocket protocol
+type ProtocolDescription struct {
+	Version         string        `json:"version"`
+	EnvelopeVersion int           `json:"envelopeVersion"` // value of the "version" field in message envelopes
+	ClientActions   []MessageSpec `json:"clientActions"`
+	ServerEvents    []MessageSpec `json:"serverEvents"`
+}
+
+// DescribeProtocol builds the protocol description from the action registry
+// and the server event list
+func DescribeProtocol() ProtocolDescription {
+	desc := ProtocolDescription{
+		Version:         ProtocolVersion,
+		EnvelopeVersion: EnvelopeVersion,
+		ClientActions:   make([]MessageSpec, 0, len(clientActions)),
+		ServerEvents:    make([]MessageSpec, 0, len(serverEvents)),
+	}
+	for _, action := range clientActions {
+		desc.ClientActions = append(desc.ClientActions, MessageSpec{
+			Type:        action.Type,
+			Description: action.Description,
+			Fields:      describeFields(reflect.TypeOf(action.Data)),
+		})
+	}
+	for _, event := range serverEvents {
+		desc.ServerEvents = append(desc.ServerEvents, MessageSpec{
+			Type:        event.Type,
+			Description: event.Description,
+			Fields:      describeFields(reflect.TypeOf(event.Data)),
+		})
+	}
+	return desc
+}
+
+// describeFields lists the JSON fields of a payload struct, flattening embedded structs
+func describeFields(t reflect.Type) []FieldSpec {
+	fields := make([]FieldSpec, 0)
+	if t == nil || t.Kind() != reflect.Struct {
+		return fields
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			fields = append(fields, describeFields(f.Type)...)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		fields = append(fields, FieldSpec{
+			Name:     name,
+			Type:     jsonTypeName(f.Type),
+			Required: strings.Contains(f.Tag.Get("validate"), "required") && !strings.Contains(opts, "omitempty"),
+		})
+	}
+	return fields
+}
+
+// jsonTypeName maps a Go type to its JSON type name
+func jsonTypeName(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Struct:
+		if t.PkgPath() == "time" && t.Name() == "Time" {
+			return "string"
+		}
+		return "object"
+	default:
+		return "object"
+	}
+}
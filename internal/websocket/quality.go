@@ -0,0 +1,186 @@
+package websocket
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// Latency is smoothed with an exponentially weighted moving average
+const qualityLatencyWeight = 0.3
+
+// connectionQuality accumulates the signals behind a connection's quality score.
+// It is embedded in Client and guarded by Client.mu.
+type connectionQuality struct {
+	pingSentAt    time.Time // outstanding quality ping, zero when none
+	pingsSent     int
+	pongsReceived int
+	missedPongs   int
+	missedInARow  int // quality pings unanswered since the last pong
+	writeErrors   int
+	slowWrites    int
+	latency       time.Duration // smoothed round trip time
+	hinted        bool          // a reconnect hint was sent and the score has not recovered
+}
+
+// QualityReport is a snapshot of a connection's quality for diagnostics
+type QualityReport struct {
+	Score         int   `json:"score"` // 0 (unusable) to 100 (healthy)
+	PingsSent     int   `json:"pingsSent"`
+	PongsReceived int   `json:"pongsReceived"`
+	MissedPongs   int   `json:"missedPongs"`
+	WriteErrors   int   `json:"writeErrors"`
+	SlowWrites    int   `json:"slowWrites"`
+	LatencyMs     int64 `json:"latencyMs"`
+}
+
+// recordPong closes out an outstanding quality ping and folds in its round trip time
+func (q *connectionQuality) recordPong(now time.Time) {
+	if q.pingSentAt.IsZero() {
+		return
+	}
+	rtt := now.Sub(q.pingSentAt)
+	if q.latency == 0 {
+		q.latency = rtt
+	} else {
+		q.latency = time.Duration(qualityLatencyWeight*float64(rtt) + (1-qualityLatencyWeight)*float64(q.latency))
+	}
+	q.pongsReceived++
+	q.missedInARow = 0
+	q.pingSentAt = time.Time{}
+}
+
+// score combines heartbeat success, write errors and latency into 0-100
+func (q *connectionQuality) score() int {
+	score := 100.0
+
+	// Up to 40 points for unanswered heartbeats
+	if q.pingsSent > 0 {
+		answered := float64(q.pongsReceived) / float64(q.pingsSent)
+		score -= 40 * (1 - answered)
+	}
+
+	// 10 points per write failure, up to 30
+	writePenalty := float64(q.writeErrors) * 10
+	if writePenalty > 30 {
+		writePenalty = 30
+	}
+	score -= writePenalty
+
+	// Latency above 100ms costs up to 30 points, reached at 1s
+	if q.latency > 100*time.Millisecond {
+		latencyPenalty := 30 * float64(q.latency-100*time.Millisecond) / float64(900*time.Millisecond)
+		if latencyPenalty > 30 {
+			latencyPenalty = 30
+		}
+		score -= latencyPenalty
+	}
+
+	if score < 0 {
+		return 0
+	}
+	return int(score + 0.5)
+}
+
+func (q *connectionQuality) report() QualityReport {
+	return QualityReport{
+		Score:         q.score(),
+		PingsSent:     q.pingsSent,
+		PongsReceived: q.pongsReceived,
+		MissedPongs:   q.missedPongs,
+		WriteErrors:   q.writeErrors,
+		SlowWrites:    q.slowWrites,
+		LatencyMs:     q.latency.Milliseconds(),
+	}
+}
+
+// recordWriteError counts a failed or dropped write to the client
+func (c *Client) recordWriteError() {
+	c.mu.Lock()
+	c.quality.writeErrors++
+	c.mu.Unlock()
+	c.hub.recordError("write")
+}
+
+// recordSlowWrite counts a write that completed but exceeded slowWriteThreshold
+func (c *Client) recordSlowWrite(elapsed time.Duration) {
+	c.mu.Lock()
+	c.quality.slowWrites++
+	c.mu.Unlock()
+	c.hub.slowWrites.Add(1)
+	c.hub.logger.Warn("Slow WebSocket write", "userID", c.userID, "elapsed", elapsed.String())
+}
+
+// SlowWrites returns how many client writes have exceeded the slow write threshold
+func (h *Hub) SlowWrites() int64 {
+	return h.slowWrites.Load()
+}
+
+// runQualityMonitor periodically pings every connection and hints clients whose
+// quality score has degraded to reconnect
+func (h *Hub) runQualityMonitor() {
+	ticker := time.NewTicker(h.config.QualityCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-h.ctx.Done():
+			return
+		case <-ticker.C:
+			h.checkConnectionQuality()
+		}
+	}
+}
+
+func (h *Hub) checkConnectionQuality() {
+	h.mu.RLock()
+	clients := make([]*Client, 0, len(h.clients))
+	for _, client := range h.clients {
+		clients = append(clients, client)
+	}
+	h.mu.RUnlock()
+
+	now := time.Now()
+	for _, client := range clients {
+		client.mu.Lock()
+		q := &client.quality
+		if !q.pingSentAt.IsZero() {
+			// The previous ping went unanswered for a whole interval
+			q.missedPongs++
+			q.missedInARow++
+		}
+		missedInARow := q.missedInARow
+		q.pingSentAt = now
+		q.pingsSent++
+
+		score := q.score()
+		sendHint := false
+		if h.config.QualityHintThreshold > 0 {
+			if score < h.config.QualityHintThreshold && !q.hinted {
+				q.hinted = true
+				sendHint = true
+			} else if score >= h.config.QualityHintThreshold {
+				q.hinted = false
+			}
+		}
+		client.mu.Unlock()
+
+		// Messages keep the read deadline alive, so a client that stops answering
+		// pings but still sends frames is only caught here
+		if limit := h.config.MaxMissedPongs; limit > 0 && missedInARow >= limit {
+			h.logger.Info("Disconnecting client that stopped answering pings", "userID", client.userID, "missedPongs", missedInARow)
+			h.dropClient(client)
+			continue
+		}
+
+		// WriteControl is safe to call concurrently with writePump
+		if err := client.conn.WriteControl(websocket.PingMessage, nil, now.Add(h.writeWait())); err != nil {
+			h.logger.Debug("Failed to send quality ping", "userID", client.userID, "error", err)
+		}
+
+		if sendHint {
+			h.logger.Info("Connection quality degraded, hinting reconnect", "userID", client.userID, "score", score)
+			h.sendToClient(client, NewReconnectHintMessage(uuid.New().String(), client.userID, score))
+		}
+	}
+}
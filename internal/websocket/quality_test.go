@@ -0,0 +1,61 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConnectionQualityScore(t *testing.T) {
+	tests := []struct {
+		name string
+		q    connectionQuality
+		want int
+	}{
+		{"new connection", connectionQuality{}, 100},
+		{"all pongs answered", connectionQuality{pingsSent: 5, pongsReceived: 5}, 100},
+		{"half the pongs missed", connectionQuality{pingsSent: 4, pongsReceived: 2}, 80},
+		{"no pongs", connectionQuality{pingsSent: 3}, 60},
+		{"one write error", connectionQuality{writeErrors: 1}, 90},
+		{"write penalty capped", connectionQuality{writeErrors: 10}, 70},
+		{"latency at threshold", connectionQuality{latency: 100 * time.Millisecond}, 100},
+		{"latency halfway", connectionQuality{latency: 550 * time.Millisecond}, 85},
+		{"latency penalty capped", connectionQuality{latency: 5 * time.Second}, 70},
+		{"everything bad", connectionQuality{pingsSent: 3, writeErrors: 5, latency: 2 * time.Second}, 0},
+		{"rounded to nearest", connectionQuality{pingsSent: 3, pongsReceived: 2}, 87},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.q.score(); got != tt.want {
+				t.Errorf("score() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConnectionQualityRecordPong(t *testing.T) {
+	start := time.Now()
+	q := connectionQuality{pingsSent: 1, missedInARow: 2, pingSentAt: start}
+
+	q.recordPong(start.Add(200 * time.Millisecond))
+	if q.latency != 200*time.Millisecond {
+		t.Errorf("first latency = %v, want 200ms", q.latency)
+	}
+	if q.pongsReceived != 1 || q.missedInARow != 0 || !q.pingSentAt.IsZero() {
+		t.Errorf("after pong: %+v", q)
+	}
+
+	// Later samples are smoothed
+	q.pingSentAt = start
+	q.recordPong(start.Add(1200 * time.Millisecond))
+	if want := 500 * time.Millisecond; q.latency != want {
+		t.Errorf("smoothed latency = %v, want %v", q.latency, want)
+	}
+
+	// A pong with no ping outstanding changes nothing
+	before := q
+	q.recordPong(start.Add(time.Hour))
+	if q != before {
+		t.Errorf("unsolicited pong changed quality: %+v", q)
+	}
+}
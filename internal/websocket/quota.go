@@ -0,0 +1,60 @@
+package websocket
+
+import (
+	"chat-service/internal/models"
+	"chat-service/internal/services"
+	"context"
+	"errors"
+	"time"
+)
+
+// consumeQuota counts a message against the sender's daily quota and reports
+// whether it may be sent. The returned refund gives the message back and must be
+// called if the message is not stored. A failed check lets the message through
+// so a Redis outage does not stop chat; while the Redis breaker is open the
+// check is skipped.
+func (h *Hub) consumeQuota(client *Client, senderID uint) (*models.MessageQuotaUsage, func(), bool) {
+	noRefund := func() {}
+	if h.quota == nil || !h.quota.Enabled() {
+		return nil, noRefund, true
+	}
+
+	now := time.Now()
+	var used int64
+	err := h.callRedis(h.ctx, redisOpTimeout, func(ctx context.Context) error {
+		var err error
+		used, err = h.quota.Count(ctx, senderID, now)
+		return err
+	})
+	if err != nil {
+		if !errors.Is(err, errRedisCircuitOpen) {
+			h.recordError("quota")
+			h.logger.Warn("Message quota check failed, allowing message", "userID", client.userID, "error", err)
+		}
+		return nil, noRefund, true
+	}
+
+	usage, err := h.quota.Check(senderID, used, now)
+	switch {
+	case err == nil:
+		return nil, func() { h.refundQuota(senderID, now) }, true
+	case errors.Is(err, services.ErrQuotaExceeded):
+		return usage, noRefund, false
+	default:
+		h.recordError("quota")
+		h.logger.Warn("Message quota check failed, allowing message", "userID", client.userID, "error", err)
+		return nil, func() { h.refundQuota(senderID, now) }, true
+	}
+}
+
+// refundQuota gives back a counted message off the caller's goroutine
+func (h *Hub) refundQuota(senderID uint, countedAt time.Time) {
+	go func() {
+		err := h.callRedis(h.ctx, redisOpTimeout, func(ctx context.Context) error {
+			return h.quota.Refund(ctx, senderID, countedAt)
+		})
+		if err != nil && !errors.Is(err, errRedisCircuitOpen) {
+			h.logger.Warn("Failed to refund message quota", "userID", senderID, "error", err)
+		}
+	}()
+}
@@ -0,0 +1,161 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Sweep idle local rate limit keys once the map grows past this size
+const localRateLimitSweepSize = 1024
+
+// localRateLimiter is an in-process sliding window limiter used when Redis is
+// unreachable. Limits then hold per instance only.
+type localRateLimiter struct {
+	mu      sync.Mutex
+	windows map[string][]time.Time
+}
+
+func newLocalRateLimiter() *localRateLimiter {
+	return &localRateLimiter{windows: make(map[string][]time.Time)}
+}
+
+// allow records a hit for key and reports whether it is within limit for the window
+func (l *localRateLimiter) allow(key string, limit int, window time.Duration) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	cutoff := now.Add(-window)
+
+	if len(l.windows) > localRateLimitSweepSize {
+		for k, hits := range l.windows {
+			if len(hits) == 0 || !hits[len(hits)-1].After(cutoff) {
+				delete(l.windows, k)
+			}
+		}
+	}
+
+	hits := l.windows[key]
+	i := 0
+	for i < len(hits) && !hits[i].After(cutoff) {
+		i++
+	}
+	hits = hits[i:]
+
+	allowed := len(hits) < limit
+	if allowed {
+		hits = append(hits, now)
+	}
+	l.windows[key] = hits
+	return allowed
+}
+
+// tokenBucket refills continuously at a fixed rate up to a burst size
+type tokenBucket struct {
+	tokens float64
+	last   time.Time // zero until first use, when the bucket starts full
+}
+
+// take removes one token if available. Otherwise it reports how long until one is.
+func (b *tokenBucket) take(now time.Time, rate float64, burst int) (time.Duration, bool) {
+	if b.last.IsZero() {
+		b.tokens = float64(burst)
+	} else {
+		b.tokens += now.Sub(b.last).Seconds() * rate
+		if b.tokens > float64(burst) {
+			b.tokens = float64(burst)
+		}
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, true
+	}
+	return time.Duration((1 - b.tokens) / rate * float64(time.Second)), false
+}
+
+// refund returns a token taken for a message that was rejected later on
+func (b *tokenBucket) refund(burst int) {
+	b.tokens++
+	if b.tokens > float64(burst) {
+		b.tokens = float64(burst)
+	}
+}
+
+// checkClientRate applies the per-connection token bucket to a channel message.
+// It is checked before the shared limits so a flooding connection is cut off
+// without a Redis round trip.
+func (h *Hub) checkClientRate(client *Client) (time.Duration, bool) {
+	rate, burst := h.config.ClientMessageRate, h.config.ClientMessageBurst
+	if rate <= 0 || burst <= 0 {
+		return 0, true
+	}
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	return client.messageTokens.take(time.Now(), rate, burst)
+}
+
+// refundClientRate gives back the token checkClientRate took
+func (h *Hub) refundClientRate(client *Client) {
+	rate, burst := h.config.ClientMessageRate, h.config.ClientMessageBurst
+	if rate <= 0 || burst <= 0 {
+		return
+	}
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	client.messageTokens.refund(burst)
+}
+
+// checkMessageRate reports whether the user may send to the channel under the
+// per-user and per-channel message limits. The counters live in Redis so they
+// hold across instances; if Redis fails the local limiter is used instead.
+func (h *Hub) checkMessageRate(channelID, userID string) bool {
+	window := h.config.RateLimitWindow
+	if window <= 0 {
+		return true
+	}
+
+	if limit := h.config.UserMessageLimit; limit > 0 {
+		if !h.allowRate("ws:ratelimit:user:"+userID, limit, window) {
+			return false
+		}
+	}
+	if limit := h.config.ChannelMessageLimit; limit > 0 {
+		if !h.allowRate("ws:ratelimit:channel:"+channelID, limit, window) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkUserMessageRate applies only the per-user message limit, for messages
+// that are not sent to a channel
+func (h *Hub) checkUserMessageRate(userID string) bool {
+	window, limit := h.config.RateLimitWindow, h.config.UserMessageLimit
+	if window <= 0 || limit <= 0 {
+		return true
+	}
+	return h.allowRate("ws:ratelimit:user:"+userID, limit, window)
+}
+
+func (h *Hub) allowRate(key string, limit int, window time.Duration) bool {
+	if h.redisService != nil {
+		var allowed bool
+		err := h.callRedis(context.Background(), redisOpTimeout, func(ctx context.Context) error {
+			var err error
+			allowed, err = h.redisService.CheckRateLimit(ctx, key, limit, window)
+			return err
+		})
+		if err == nil {
+			return allowed
+		}
+		if !errors.Is(err, errRedisCircuitOpen) {
+			h.recordError("redis")
+			h.logger.Warn("Redis rate limit check failed, using local limit", "key", key, "error", err)
+		}
+	}
+	return h.localLimits.allow(key, limit, window)
+}
@@ -0,0 +1,128 @@
+package websocket
+
+import (
+	"chat-service/internal/services"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketTake(t *testing.T) {
+	const rate, burst = 2.0, 3 // 2 tokens per second, up to 3
+	start := time.Unix(1_700_000_000, 0)
+
+	tests := []struct {
+		name      string
+		steps     []time.Duration // offsets from start of each take
+		wantOK    []bool
+		wantRetry time.Duration // retry-after of the last take when rejected
+	}{
+		{"starts full", []time.Duration{0, 0, 0}, []bool{true, true, true}, 0},
+		{"burst exhausted", []time.Duration{0, 0, 0, 0}, []bool{true, true, true, false}, 500 * time.Millisecond},
+		{"refills at rate", []time.Duration{0, 0, 0, 500 * time.Millisecond}, []bool{true, true, true, true}, 0},
+		{"partial refill", []time.Duration{0, 0, 0, 250 * time.Millisecond}, []bool{true, true, true, false}, 250 * time.Millisecond},
+		{"refill capped at burst", []time.Duration{0, time.Hour, time.Hour, time.Hour, time.Hour}, []bool{true, true, true, true, false}, 500 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b tokenBucket
+			var retry time.Duration
+			for i, offset := range tt.steps {
+				var ok bool
+				retry, ok = b.take(start.Add(offset), rate, burst)
+				if ok != tt.wantOK[i] {
+					t.Fatalf("take %d allowed = %v, want %v", i, ok, tt.wantOK[i])
+				}
+			}
+			if retry != tt.wantRetry {
+				t.Errorf("retry after = %v, want %v", retry, tt.wantRetry)
+			}
+		})
+	}
+}
+
+func TestTokenBucketRefund(t *testing.T) {
+	const rate, burst = 1.0, 2
+	now := time.Unix(1_700_000_000, 0)
+
+	var b tokenBucket
+	b.take(now, rate, burst)
+	b.take(now, rate, burst)
+	if _, ok := b.take(now, rate, burst); ok {
+		t.Fatal("bucket not empty after burst")
+	}
+
+	b.refund(burst)
+	if _, ok := b.take(now, rate, burst); !ok {
+		t.Fatal("refunded token not available")
+	}
+
+	// Refunds never lift the bucket above its burst size
+	for i := 0; i < 5; i++ {
+		b.refund(burst)
+	}
+	if b.tokens != burst {
+		t.Errorf("tokens = %v after refunds, want %d", b.tokens, burst)
+	}
+}
+
+func TestCheckClientRateDisabled(t *testing.T) {
+	hub := newTestHub(t)
+	client := NewClient(hub, nil, "1")
+	for i := 0; i < 100; i++ {
+		if _, ok := hub.checkClientRate(client); !ok {
+			t.Fatalf("message %d limited with the bucket disabled", i)
+		}
+	}
+}
+
+// newRateLimitedHub returns a hub allowing limit messages per user per minute,
+// counted in the given Redis
+func newRateLimitedHub(t *testing.T, redis *services.RedisService, limit int) *Hub {
+	t.Helper()
+	hub := newTestHub(t)
+	hub.redisService = redis
+	hub.config.RateLimitWindow = time.Minute
+	hub.config.UserMessageLimit = limit
+	return hub
+}
+
+func TestMessageRateSharedAcrossInstances(t *testing.T) {
+	redis, _ := newTestRedis(t)
+	first := newRateLimitedHub(t, redis, 3)
+	second := newRateLimitedHub(t, redis, 3)
+
+	for i, hub := range []*Hub{first, second, first} {
+		if !hub.checkMessageRate("10", "1") {
+			t.Fatalf("message %d rejected within the limit", i+1)
+		}
+	}
+	if second.checkMessageRate("10", "1") {
+		t.Fatal("fourth message allowed; instances do not share the count")
+	}
+	if first.checkMessageRate("10", "1") {
+		t.Fatal("fourth message allowed on the first instance")
+	}
+	if !second.checkMessageRate("10", "2") {
+		t.Fatal("another user was limited by the first user's messages")
+	}
+}
+
+func TestMessageRateFallsBackToLocalLimit(t *testing.T) {
+	redis, mr := newTestRedis(t)
+	first := newRateLimitedHub(t, redis, 2)
+	second := newRateLimitedHub(t, redis, 2)
+	mr.Close()
+
+	// Without Redis each instance enforces the limit on its own
+	for _, hub := range []*Hub{first, second} {
+		for i := 0; i < 2; i++ {
+			if !hub.checkMessageRate("10", "1") {
+				t.Fatalf("message %d rejected within the local limit", i+1)
+			}
+		}
+		if hub.checkMessageRate("10", "1") {
+			t.Fatal("local limit not enforced while Redis is down")
+		}
+	}
+}
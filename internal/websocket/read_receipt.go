@@ -0,0 +1,50 @@
+package websocket
+
+import (
+	"chat-service/internal/services"
+	"errors"
+	"strconv"
+)
+
+// handleRead advances the sender's read pointer in a channel and tells the other
+// members. Reads at or behind the current pointer change nothing and are only
+// echoed back to the sender.
+func (h *Hub) handleRead(client *Client, message *Message) {
+	var data ReadData
+	if err := h.mapToStruct(message.Data, &data); err != nil || data.MessageID == 0 {
+		h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "INVALID_DATA", "Invalid read data"))
+		return
+	}
+	channelID, err := parseChannelID(data.ChannelID)
+	if err != nil {
+		h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "INVALID_CHANNEL_ID", err.Error()))
+		return
+	}
+
+	userIDUint, err := strconv.ParseUint(client.userID, 10, 64)
+	if err != nil {
+		h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "INVALID_USER_ID", "Invalid user ID format"))
+		return
+	}
+
+	state, err := h.readService.MarkRead(uint(userIDUint), channelID, data.MessageID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrMessageNotFound):
+			h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "MESSAGE_NOT_FOUND", err.Error()))
+		case errors.Is(err, services.ErrNotChannelMember):
+			h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "NOT_IN_CHANNEL", err.Error()))
+		default:
+			h.logger.Error("Failed to mark channel read", "error", err, "userID", client.userID, "channelID", data.ChannelID)
+			h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "READ_FAILED", "Failed to mark as read"))
+		}
+		return
+	}
+
+	readMsg := NewReadMessage(message.ID, client.userID, data.ChannelID, *state.LastReadMessageID)
+	if !state.Advanced {
+		h.sendToClient(client, readMsg)
+		return
+	}
+	h.broadcastToChannel(data.ChannelID, readMsg)
+}
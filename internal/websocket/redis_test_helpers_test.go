@@ -0,0 +1,21 @@
+package websocket
+
+import (
+	"chat-service/internal/database"
+	"chat-service/internal/services"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// newTestRedis returns a RedisService backed by an in-memory Redis server
+func newTestRedis(t *testing.T) (*services.RedisService, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client, err := database.NewRedisConnection("redis://" + mr.Addr())
+	if err != nil {
+		t.Fatalf("connect to miniredis: %v", err)
+	}
+	t.Cleanup(func() { client.GetClient().Close() })
+	return services.NewRedisService(client), mr
+}
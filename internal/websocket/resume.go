@@ -0,0 +1,109 @@
+package websocket
+
+import (
+	"chat-service/internal/services"
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// newResumeToken returns a token for a new connection, or "" when resume is disabled
+func (h *Hub) newResumeToken() string {
+	if h.config.ResumeTokenTTL <= 0 || h.redisService == nil {
+		return ""
+	}
+	return uuid.New().String()
+}
+
+// saveResumeState keeps a removed client's joined channels in Redis under its
+// resume token. It runs in the background since callers hold h.mu.
+func (h *Hub) saveResumeState(c *Client, channels []string) {
+	if c.resumeToken == "" || len(channels) == 0 {
+		return
+	}
+	states := map[string]*services.ResumeState{
+		c.resumeToken: {UserID: c.userID, Channels: channels},
+	}
+	go h.storeResumeStates(states)
+}
+
+// saveAllResumeStates keeps every local client's joined channels on shutdown so
+// clients can resume on another instance
+func (h *Hub) saveAllResumeStates() {
+	h.mu.RLock()
+	states := make(map[string]*services.ResumeState)
+	for channelID, clients := range h.channels {
+		for userID, client := range clients {
+			if client.resumeToken == "" {
+				continue
+			}
+			state, ok := states[client.resumeToken]
+			if !ok {
+				state = &services.ResumeState{UserID: userID}
+				states[client.resumeToken] = state
+			}
+			state.Channels = append(state.Channels, channelID)
+		}
+	}
+	h.mu.RUnlock()
+
+	if len(states) > 0 {
+		h.storeResumeStates(states)
+	}
+}
+
+func (h *Hub) storeResumeStates(states map[string]*services.ResumeState) {
+	err := h.callRedis(context.Background(), redisOpTimeout, func(ctx context.Context) error {
+		return h.redisService.SaveResumeStates(ctx, states, h.config.ResumeTokenTTL)
+	})
+	if err != nil && !errors.Is(err, errRedisCircuitOpen) {
+		h.recordError("redis")
+		h.logger.Warn("Failed to save resume state", "count", len(states), "error", err)
+	}
+}
+
+// handleResume rejoins the channels saved under a previous connection's resume
+// token. Each channel is confirmed like a join, followed by a resume event
+// listing them all. An unknown, expired or foreign token leaves the client to
+// join its channels again.
+func (h *Hub) handleResume(client *Client, message *Message) {
+	var data ResumeData
+	if err := h.mapToStruct(message.Data, &data); err != nil {
+		h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "INVALID_DATA", "Invalid resume data"))
+		return
+	}
+	if err := data.Validate(); err != nil {
+		h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "INVALID_DATA", err.Error()))
+		return
+	}
+
+	var state *services.ResumeState
+	err := h.callRedis(context.Background(), redisOpTimeout, func(ctx context.Context) error {
+		var err error
+		state, err = h.redisService.TakeResumeState(ctx, data.ResumeToken)
+		return err
+	})
+	if err != nil && !errors.Is(err, errRedisCircuitOpen) {
+		h.recordError("redis")
+		h.logger.Warn("Failed to load resume state", "userID", client.userID, "error", err)
+	}
+	if state == nil || state.UserID != client.userID {
+		h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "RESUME_FAILED", "Resume token is invalid or expired; join channels again"))
+		return
+	}
+
+	joined := make([]string, 0, len(state.Channels))
+	for _, channelID := range state.Channels {
+		if err := h.JoinChannel(client.userID, channelID); err != nil {
+			h.logger.Warn("Failed to rejoin channel on resume", "userID", client.userID, "channelID", channelID, "error", err)
+			continue
+		}
+		joined = append(joined, channelID)
+
+		successMsg := NewJoinChannelMessage(uuid.New().String(), client.userID, channelID)
+		successMsg.Data["members"] = h.channelRoster(channelID)
+		h.sendToClient(client, successMsg)
+	}
+	h.sendToClient(client, NewResumeMessage(message.ID, client.userID, joined))
+}
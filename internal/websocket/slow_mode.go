@@ -0,0 +1,126 @@
+package websocket
+
+import (
+	"chat-service/internal/models"
+	"context"
+	"sync"
+	"time"
+)
+
+// How long a channel's settings are cached before re-reading them. Changes made
+// through the API invalidate the cache on every instance at once; this bounds
+// how long a missed invalidation can go unnoticed.
+const channelSettingsCacheTTL = 30 * time.Second
+
+type channelSettingsEntry struct {
+	slowMode  time.Duration
+	archived  bool
+	direct    bool
+	fetchedAt time.Time
+}
+
+// channelSettingsCache keeps the per-channel settings checked on every message
+// so the hub does not hit the database for each one
+type channelSettingsCache struct {
+	mu      sync.Mutex
+	entries map[uint]channelSettingsEntry
+}
+
+func newChannelSettingsCache() *channelSettingsCache {
+	return &channelSettingsCache{entries: make(map[uint]channelSettingsEntry)}
+}
+
+// channelSettings returns the channel's cached settings, reloading them once
+// stale. If a reload fails the stale entry is used and the load is retried on
+// the next call; with no entry at all the error is returned.
+func (h *Hub) channelSettings(channelID uint) (channelSettingsEntry, error) {
+	h.settings.mu.Lock()
+	entry, ok := h.settings.entries[channelID]
+	h.settings.mu.Unlock()
+	if ok && time.Since(entry.fetchedAt) < channelSettingsCacheTTL {
+		return entry, nil
+	}
+
+	channel, err := h.channelRepo.GetByID(channelID)
+	if err != nil {
+		h.logger.Warn("Failed to load channel settings", "channelID", channelID, "error", err)
+		if ok {
+			return entry, nil
+		}
+		return channelSettingsEntry{}, err
+	}
+
+	entry = channelSettingsEntry{
+		slowMode:  time.Duration(channel.SlowModeSeconds) * time.Second,
+		archived:  channel.Archived,
+		direct:    channel.Type == models.ChannelTypeDirect,
+		fetchedAt: time.Now(),
+	}
+	h.settings.mu.Lock()
+	h.settings.entries[channelID] = entry
+	h.settings.mu.Unlock()
+	return entry, nil
+}
+
+// InvalidateChannelSettings drops the channel's cached settings on this instance
+// and asks the other instances to do the same, so an archive or slow mode change
+// applies to the next message everywhere
+func (h *Hub) InvalidateChannelSettings(channelID uint) {
+	h.dropChannelSettings(channelID)
+
+	go func() {
+		cmd := hubCommand{Type: hubCommandChannelSettings, ChannelID: channelID, Origin: h.instanceID}
+		if err := h.publishCommand(context.Background(), cmd); err != nil {
+			h.logger.Error("Failed to publish channel settings invalidation", "channelID", channelID, "error", err)
+		}
+	}()
+}
+
+func (h *Hub) dropChannelSettings(channelID uint) {
+	h.settings.mu.Lock()
+	delete(h.settings.entries, channelID)
+	h.settings.mu.Unlock()
+}
+
+// slowModeInterval returns the channel's slow mode interval, 0 when disabled or
+// when the settings cannot be loaded
+func (h *Hub) slowModeInterval(channelID uint) time.Duration {
+	settings, _ := h.channelSettings(channelID)
+	return settings.slowMode
+}
+
+// checkSlowMode reports whether the user may send to the channel now and, if
+// not, how long they have to wait. Redis failures fail open.
+func (h *Hub) checkSlowMode(channelID uint, channelKey, userID string) (time.Duration, bool) {
+	interval := h.slowModeInterval(channelID)
+	if interval <= 0 {
+		return 0, true
+	}
+
+	var allowed bool
+	var retryAfter time.Duration
+	err := h.callRedis(context.Background(), redisOpTimeout, func(ctx context.Context) error {
+		var err error
+		allowed, retryAfter, err = h.redisService.AcquireSlowModeSlot(ctx, channelKey, userID, interval)
+		return err
+	})
+	if err != nil {
+		h.logger.Warn("Slow mode check failed, allowing message", "channelID", channelID, "userID", userID, "error", err)
+		return 0, true
+	}
+	return retryAfter, allowed
+}
+
+// releaseSlowMode frees the slot checkSlowMode acquired for a message that was
+// rejected afterwards, so the user is not made to wait for a message never sent
+func (h *Hub) releaseSlowMode(channelID uint, channelKey, userID string) {
+	if h.slowModeInterval(channelID) <= 0 {
+		return
+	}
+	err := h.callRedis(context.Background(), redisOpTimeout, func(ctx context.Context) error {
+		return h.redisService.ReleaseSlowModeSlot(ctx, channelKey, userID)
+	})
+	if err != nil {
+		h.logger.Warn("Failed to release slow mode slot", "channelID", channelID, "userID", userID, "error", err)
+	}
+}
@@ -0,0 +1,56 @@
+package websocket
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Instrumentation name of the hub's spans. The hub uses the global tracer
+// provider, which is a no-op until main installs one with otel.SetTracerProvider.
+const tracerName = "chat-service/internal/websocket"
+
+func newTracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// Trace context travels with hub commands in W3C traceparent form, so a command
+// applied on another instance joins the publisher's trace
+var tracePropagator = propagation.TraceContext{}
+
+// injectTrace stores the span context of ctx in the command
+func injectTrace(ctx context.Context, cmd *hubCommand) {
+	carrier := propagation.MapCarrier{}
+	tracePropagator.Inject(ctx, carrier)
+	if len(carrier) > 0 {
+		cmd.Trace = carrier
+	}
+}
+
+// extractTrace returns a context carrying the span context the publisher stored
+// in the command, or ctx unchanged if there is none
+func extractTrace(ctx context.Context, cmd hubCommand) context.Context {
+	if len(cmd.Trace) == 0 {
+		return ctx
+	}
+	return tracePropagator.Extract(ctx, propagation.MapCarrier(cmd.Trace))
+}
+
+// recordSpanError marks the span failed
+func recordSpanError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// setDeliveryAttributes records how many of a broadcast's recipients were reached
+func setDeliveryAttributes(span trace.Span, recipients, sent int) {
+	span.SetAttributes(
+		attribute.Int("ws.recipients", recipients),
+		attribute.Int("ws.sent", sent),
+		attribute.Int("ws.failed", recipients-sent),
+	)
+}
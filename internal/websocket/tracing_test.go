@@ -0,0 +1,81 @@
+package websocket
+
+import (
+	"chat-service/internal/services"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// endedSpan returns the ended span with the given name
+func endedSpan(t *testing.T, recorder *tracetest.SpanRecorder, name string) sdktrace.ReadOnlySpan {
+	t.Helper()
+	for _, span := range recorder.Ended() {
+		if span.Name() == name {
+			return span
+		}
+	}
+	t.Fatalf("no %q span recorded", name)
+	return nil
+}
+
+func TestHubCommandContinuesTraceAcrossInstances(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer(tracerName)
+
+	publisher := newTestHub(t)
+	publisher.redisService, _ = newTestRedis(t)
+	publisher.tracer = tracer
+	receiver := newTestHub(t)
+	receiver.tracer = tracer
+
+	// Stands in for the receiving instance's listenCommands
+	pubsub := publisher.redisService.Subscribe(context.Background(), services.HubCommandsChannel)
+	defer pubsub.Close()
+	if _, err := pubsub.Receive(context.Background()); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	cmd := hubCommand{Type: hubCommandChannelSettings, ChannelID: 3, Origin: publisher.instanceID}
+	if err := publisher.publishCommand(context.Background(), cmd); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	var received hubCommand
+	select {
+	case msg := <-pubsub.Channel():
+		if err := json.Unmarshal([]byte(msg.Payload), &received); err != nil {
+			t.Fatalf("decode command: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("command was not published")
+	}
+	receiver.applyCommand(received)
+
+	publish := endedSpan(t, recorder, "websocket.publish_command")
+	apply := endedSpan(t, recorder, "websocket.apply_command")
+	if apply.SpanContext().TraceID() != publish.SpanContext().TraceID() {
+		t.Fatal("apply span is not in the publisher's trace")
+	}
+	if apply.Parent().SpanID() != publish.SpanContext().SpanID() || !apply.Parent().IsRemote() {
+		t.Fatalf("apply span parent = %v, want the remote publish span %v", apply.Parent().SpanID(), publish.SpanContext().SpanID())
+	}
+}
+
+func TestHubCommandWithoutTraceStartsNewTrace(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	hub := newTestHub(t)
+	hub.tracer = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer(tracerName)
+
+	// Commands from instances that predate tracing carry no trace field
+	hub.applyCommand(hubCommand{Type: hubCommandChannelSettings, ChannelID: 3, Origin: "other"})
+
+	apply := endedSpan(t, recorder, "websocket.apply_command")
+	if apply.Parent().IsValid() {
+		t.Fatal("apply span has a parent without trace context in the command")
+	}
+}
@@ -0,0 +1,50 @@
+package websocket
+
+import (
+	"time"
+)
+
+// Minimum time between typing-start broadcasts from one client
+const typingBroadcastInterval = 2 * time.Second
+
+// handleTyping relays a typing indicator to the other members of a joined channel.
+// Nothing is persisted. Repeated starts within typingBroadcastInterval are dropped
+// so a fast typist does not flood the channel; stops are always relayed.
+func (h *Hub) handleTyping(client *Client, message *Message) {
+	var data TypingData
+	if err := h.mapToStruct(message.Data, &data); err != nil {
+		h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "INVALID_DATA", "Invalid typing data"))
+		return
+	}
+	if _, err := parseChannelID(data.ChannelID); err != nil {
+		h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "INVALID_CHANNEL_ID", err.Error()))
+		return
+	}
+
+	h.mu.RLock()
+	_, inChannel := h.channels[data.ChannelID][client.userID]
+	h.mu.RUnlock()
+	if !inChannel {
+		h.sendToClient(client, NewErrorMessage(message.ID, client.userID, "NOT_IN_CHANNEL", "You are not in this channel"))
+		return
+	}
+
+	if data.IsTyping {
+		now := time.Now()
+		client.mu.Lock()
+		throttled := now.Sub(client.lastTypingAt) < typingBroadcastInterval
+		if !throttled {
+			client.lastTypingAt = now
+		}
+		client.mu.Unlock()
+		if throttled {
+			return
+		}
+	} else {
+		client.mu.Lock()
+		client.lastTypingAt = time.Time{}
+		client.mu.Unlock()
+	}
+
+	h.broadcastToChannelExcept(data.ChannelID, client.userID, NewTypingMessage(message.ID, client.userID, data.ChannelID, data.IsTyping))
+}
@@ -0,0 +1,82 @@
+package websocket
+
+import (
+	"time"
+)
+
+// beginWrite marks a write to the peer as in progress and returns its start time
+func (c *Client) beginWrite() time.Time {
+	now := time.Now()
+	c.mu.Lock()
+	c.writeStartedAt = now
+	c.mu.Unlock()
+	return now
+}
+
+// endWrite clears the in-progress write, recording progress if it succeeded
+func (c *Client) endWrite(ok bool) {
+	c.mu.Lock()
+	c.writeStartedAt = time.Time{}
+	if ok {
+		c.lastWrite = time.Now()
+	}
+	c.mu.Unlock()
+}
+
+// writeStalled reports whether outbound writes have made no progress for timeout:
+// either a write has been blocked that long, or messages are queued and nothing
+// was written for that long. An idle connection with nothing to send is not stalled.
+func (c *Client) writeStalled(now time.Time, timeout time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return false
+	}
+	if !c.writeStartedAt.IsZero() {
+		return now.Sub(c.writeStartedAt) >= timeout
+	}
+	return len(c.send) > 0 && now.Sub(c.lastWrite) >= timeout
+}
+
+// runWriteStallReaper closes connections whose writer is stuck. Inbound activity
+// does not keep such a connection alive, since the peer may still be sending
+// while never reading what we write.
+func (h *Hub) runWriteStallReaper() {
+	interval := h.config.WriteStallTimeout / 3
+	if interval <= 0 || interval > 15*time.Second {
+		interval = 15 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-h.ctx.Done():
+			return
+		case <-ticker.C:
+			h.reapStalledWriters()
+		}
+	}
+}
+
+func (h *Hub) reapStalledWriters() {
+	h.mu.RLock()
+	clients := make([]*Client, 0, len(h.clients))
+	for _, client := range h.clients {
+		clients = append(clients, client)
+	}
+	h.mu.RUnlock()
+
+	now := time.Now()
+	for _, client := range clients {
+		if !client.writeStalled(now, h.config.WriteStallTimeout) {
+			continue
+		}
+		h.logger.Warn("Closing connection with stalled writer", "userID", client.userID, "timeout", h.config.WriteStallTimeout.String())
+		h.recordError("write_stall")
+		h.dropClient(client)
+		// Closing the socket unblocks a write stuck in the kernel
+		_ = client.conn.Close()
+	}
+}